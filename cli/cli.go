@@ -0,0 +1,42 @@
+// Package cli runs gocovsh as its main package does, so that it can be built
+// with extensions, such as a parser of another coverage format.
+package cli
+
+import (
+	"os"
+
+	"github.com/orlangure/gocovsh/internal/program"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/parser"
+)
+
+// Option configures gocovsh started with Run.
+type Option func(*options)
+
+type options struct {
+	parser parser.Parser
+}
+
+// WithParser reads the coverage profile using a custom parser instead of the
+// built-in one selected by -format.
+func WithParser(custom parser.Parser) Option {
+	return func(o *options) {
+		o.parser = custom
+	}
+}
+
+// Run parses the command line flags and runs gocovsh until it exits.
+func Run(opts ...Option) error {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	styles.SetTheme()
+
+	return program.New(
+		program.WithGoModInfo(),
+		program.WithLogFile(os.Getenv("GOCOVSH_LOG_FILE")),
+		program.WithParser(o.parser),
+	).Run()
+}
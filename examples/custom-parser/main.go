@@ -0,0 +1,68 @@
+// Command custom-parser is gocovsh built with a parser of a made up format:
+// every line of the report is a file name, a line number and the number of
+// times the line was executed. It is a separate module, to show that custom
+// parsers can be built outside of gocovsh.
+//
+// Run it with a report in the new format:
+//
+//	go run . -profile report.txt
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/orlangure/gocovsh/cli"
+	"golang.org/x/tools/cover"
+)
+
+// lineParser implements parser.Parser.
+type lineParser struct{}
+
+func (lineParser) Parse(r io.Reader) ([]*cover.Profile, error) {
+	var profiles []*cover.Profile
+
+	byName := map[string]*cover.Profile{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		line, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid line %q: %w", fields[1], err)
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count %q: %w", fields[2], err)
+		}
+
+		p, ok := byName[fields[0]]
+		if !ok {
+			p = &cover.Profile{FileName: fields[0], Mode: "count"}
+			byName[fields[0]] = p
+			profiles = append(profiles, p)
+		}
+
+		p.Blocks = append(p.Blocks, cover.ProfileBlock{
+			StartLine: line, StartCol: 1, EndLine: line, EndCol: 2, NumStmt: 1, Count: count,
+		})
+	}
+
+	return profiles, scanner.Err()
+}
+
+func main() {
+	if err := cli.Run(cli.WithParser(lineParser{})); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,210 @@
+// Package blockview provides a bubbletea component for displaying the blocks
+// of a coverage profile as they were parsed: their positions, the number of
+// statements in them and how many times they ran.
+package blockview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"golang.org/x/tools/cover"
+)
+
+const ellipsis = "…"
+
+var (
+	titleStyle = func() lipgloss.Style {
+		b := lipgloss.RoundedBorder()
+		b.Right = "├"
+		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
+	}()
+
+	rowStyle    = lipgloss.NewStyle().PaddingLeft(2)
+	columnStyle = rowStyle.Copy().Faint(true)
+	helpStyle   = lipgloss.NewStyle().Padding(0, 0, 1, 4)
+)
+
+// New creates a new blockview model which is rendered into the provided
+// width and height.
+func New(width, height int) Model {
+	return Model{
+		viewport: viewport.New(width, height),
+		help:     help.New(),
+		showHelp: true,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Model is the blockview model. Use New to create a new instance.
+type Model struct {
+	viewport viewport.Model
+	help     help.Model
+	width    int
+	height   int
+	title    string
+	blocks   []cover.ProfileBlock
+	showHelp bool
+}
+
+// Update is used to update the internal model state based on the external
+// events.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(msg, DefaultKeyMap.Home) {
+			_ = m.viewport.GotoTop()
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.End) {
+			_ = m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	vp, cmd := m.viewport.Update(msg)
+	m.viewport = vp
+
+	return m, cmd
+}
+
+// View renders the model to be displayed.
+func (m *Model) View() string {
+	sections := []string{m.headerView(), m.viewport.View()}
+
+	if helpView := m.helpView(); helpView != "" {
+		sections = append(sections, helpView)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// SetTitle sets the title of the blockview, usually the file name.
+func (m *Model) SetTitle(title string) {
+	m.title = title
+	m.recalculateSize()
+}
+
+// SetBlocks sets the blocks to be displayed, in the order of the profile.
+func (m *Model) SetBlocks(blocks []cover.ProfileBlock) {
+	m.blocks = blocks
+	m.redrawBlocks()
+	m.viewport.SetYOffset(0)
+}
+
+// SetWidth sets the width of the blockview.
+func (m *Model) SetWidth(width int) {
+	m.setSize(width, m.height)
+}
+
+// SetHeight sets the height of the blockview.
+func (m *Model) SetHeight(height int) {
+	m.setSize(m.width, height)
+}
+
+// ShortHelp implements help.KeyMap interface.
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		DefaultKeyMap.Up,
+		DefaultKeyMap.Down,
+		DefaultKeyMap.Back,
+		DefaultKeyMap.Help,
+	}
+}
+
+// FullHelp implements help.KeyMap interface.
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{DefaultKeyMap.Up, DefaultKeyMap.Down, DefaultKeyMap.Home, DefaultKeyMap.End},
+		{DefaultKeyMap.Back, DefaultKeyMap.Help, DefaultKeyMap.Quit},
+	}
+}
+
+// SetShowHelp allows to hide or show the help section.
+func (m *Model) SetShowHelp(showHelp bool) {
+	m.showHelp = showHelp
+	m.setSize(m.width, m.height)
+}
+
+// SetShowFullHelp allows to view extended help section, if visible.
+func (m *Model) SetShowFullHelp(showFullHelp bool) {
+	m.help.ShowAll = showFullHelp
+	m.setSize(m.width, m.height)
+}
+
+func (m *Model) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.help.Width = width
+	m.viewport.Width = width
+	m.recalculateSize()
+	m.redrawBlocks()
+}
+
+func (m *Model) recalculateSize() {
+	height := m.height
+	height -= lipgloss.Height(m.headerView())
+	height -= lipgloss.Height(m.helpView())
+
+	if height < 1 {
+		height = 1
+	}
+
+	m.viewport.Height = height
+}
+
+// redrawBlocks renders the blocks as a table, with their positions written
+// like in the profile, as line.column.
+func (m *Model) redrawBlocks() {
+	rows := make([]string, 0, len(m.blocks)+1)
+	rows = append(rows, columnStyle.Render(fmt.Sprintf("%-12s %-12s %10s %10s", "start", "end", "statements", "count")))
+
+	for _, b := range m.blocks {
+		row := fmt.Sprintf(
+			"%-12s %-12s %10d %10d",
+			fmt.Sprintf("%d.%d", b.StartLine, b.StartCol), fmt.Sprintf("%d.%d", b.EndLine, b.EndCol), b.NumStmt, b.Count,
+		)
+		rows = append(rows, rowStyle.Foreground(countColor(b)).Render(row))
+	}
+
+	m.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+func countColor(b cover.ProfileBlock) lipgloss.Color {
+	if b.Count > 0 {
+		return lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+	}
+
+	return lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+}
+
+func (m *Model) headerView() string {
+	truncatedTitle := m.title
+
+	if maxWidth := m.width - 5; maxWidth > 0 && len(m.title) > maxWidth {
+		truncatedTitle = fmt.Sprintf("%s%s", ellipsis, m.title[len(m.title)-maxWidth:])
+	}
+
+	title := titleStyle.Render(truncatedTitle)
+
+	lineWidth := m.width - lipgloss.Width(title)
+	if lineWidth < 0 {
+		lineWidth = 0
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, strings.Repeat("─", lineWidth))
+}
+
+func (m *Model) helpView() string {
+	if m.showHelp {
+		return helpStyle.Render(m.help.View(m))
+	}
+
+	return ""
+}
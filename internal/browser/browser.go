@@ -0,0 +1,71 @@
+// Package browser builds the web addresses of source files and the commands
+// that open them in the browser of the user.
+package browser
+
+import (
+	"os/exec"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultTemplate opens the documentation of the package of the file.
+const DefaultTemplate = "https://pkg.go.dev/{package}"
+
+// File is a source file of a module, as used in address templates.
+type File struct {
+	// Module is the path of the module, such as github.com/user/repo
+	Module string
+
+	// Path is the path of the file relative to the module root
+	Path string
+
+	// Line is the line of the file to open, or 0 for its beginning
+	Line int
+}
+
+// URL fills the template with the placeholders of the file: {module},
+// {package} for the import path of its package, {path} relative to the
+// module root, {dir} for the directory of the path, and {line}.
+func URL(template string, f File) string {
+	dir := path.Dir(f.Path)
+	pkg := f.Module
+
+	if dir != "." {
+		pkg = f.Module + "/" + dir
+	}
+
+	line := f.Line
+	if line < 1 {
+		line = 1
+	}
+
+	return strings.NewReplacer(
+		"{module}", f.Module,
+		"{package}", pkg,
+		"{path}", f.Path,
+		"{dir}", dir,
+		"{line}", strconv.Itoa(line),
+	).Replace(template)
+}
+
+// Command returns a command that opens the address in the default browser.
+func Command(url string) *exec.Cmd {
+	args := Args(runtime.GOOS, url)
+
+	return exec.Command(args[0], args[1:]...) // nolint: gosec
+}
+
+// Args builds arguments of the command opening the address on the operating
+// system: open on macOS, the URL handler on Windows, and xdg-open elsewhere.
+func Args(goos, url string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"open", url}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}
+	default:
+		return []string{"xdg-open", url}
+	}
+}
@@ -0,0 +1,49 @@
+package browser_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/browser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		template string
+		file     browser.File
+		url      string
+	}{
+		{
+			template: browser.DefaultTemplate,
+			file:     browser.File{Module: "github.com/user/repo", Path: "internal/model/model.go", Line: 12},
+			url:      "https://pkg.go.dev/github.com/user/repo/internal/model",
+		},
+		{
+			template: browser.DefaultTemplate,
+			file:     browser.File{Module: "github.com/user/repo", Path: "main.go"},
+			url:      "https://pkg.go.dev/github.com/user/repo",
+		},
+		{
+			template: "https://{module}/blob/main/{path}#L{line}",
+			file:     browser.File{Module: "github.com/user/repo", Path: "internal/model/model.go", Line: 12},
+			url:      "https://github.com/user/repo/blob/main/internal/model/model.go#L12",
+		},
+		{
+			template: "https://git.example.com/repo/-/tree/main/{dir}?line={line}",
+			file:     browser.File{Module: "example.com/repo", Path: "cmd/tool/main.go"},
+			url:      "https://git.example.com/repo/-/tree/main/cmd/tool?line=1",
+		},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.url, browser.URL(test.template, test.file), test.template)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	url := "https://pkg.go.dev/github.com/user/repo"
+
+	require.Equal(t, []string{"open", url}, browser.Args("darwin", url))
+	require.Equal(t, []string{"xdg-open", url}, browser.Args("linux", url))
+	require.Equal(t, []string{"rundll32", "url.dll,FileProtocolHandler", url}, browser.Args("windows", url))
+}
@@ -1,17 +1,22 @@
 // Package codeview provides a bubbletea component for displaying code. It adds
 // line numbers and makes sure the code fits on the screen. It does not support
-// wrapping long lines; instead, it trims them.
+// wrapping long lines; instead, it trims them, and allows to scroll them
+// horizontally.
 package codeview
 
 import (
 	"fmt"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 	"github.com/muesli/reflow/truncate"
 	"github.com/orlangure/gocovsh/internal/styles"
 )
@@ -20,6 +25,19 @@ const (
 	ellipsis        = "…"
 	newLine         = "\n"
 	lineNumberColor = "#505050"
+
+	statusMessageLifetime = 2 * time.Second
+
+	// horizontalStep is the number of columns to scroll horizontally at once
+	horizontalStep = 4
+
+	// DefaultFoldContext is the default number of lines displayed around
+	// uncovered blocks when covered code is folded.
+	DefaultFoldContext = 3
+
+	// defaultFilterContext is the number of lines displayed around filtered
+	// lines, unless set otherwise
+	defaultFilterContext = 1
 )
 
 var (
@@ -48,26 +66,63 @@ var (
 					BorderForeground(lipgloss.Color(lineNumberColor)).
 					Border(lipgloss.NormalBorder(), false, true, false, false)
 
+	foldSeparatorStyle = lipgloss.NewStyle().
+				PaddingLeft(1).
+				Foreground(lipgloss.Color(lineNumberColor))
+
 	blankBlockSeparatorStyle = lipgloss.NewStyle().
 					MarginTop(1).MarginBottom(1).
 					Foreground(lipgloss.Color(lineNumberColor))
+
+	statusMessageStyle = lipgloss.NewStyle().Padding(0, 1)
+
+	warningStyle = lipgloss.NewStyle().Padding(0, 1).Bold(true)
 )
 
+type statusMessageTimeoutMsg struct{ id int }
+
 type filteredLines struct {
 	actualLines  []int
 	contextLines map[int]bool
 }
 
+// LineRange is a range of lines, both ends included. Line numbers start
+// from 1.
+type LineRange struct {
+	Start int
+	End   int
+}
+
 // New creates a new codeview model which is rendered into the provided width
 // and height.
 func New(width, height int) Model {
 	return Model{
-		viewport: viewport.New(width, height),
+		viewport: newViewport(width, height),
 		help:     help.New(),
 		showHelp: true,
+
+		showLineNumbers: true,
+		foldContext:     DefaultFoldContext,
+		filterContext:   defaultFilterContext,
+		searchInput:     newSearchInput(),
+		currentMatch:    -1,
 	}
 }
 
+// newViewport creates the viewport scrolling the code using the keys of
+// less, as listed in DefaultKeyMap.
+func newViewport(width, height int) viewport.Model {
+	vp := viewport.New(width, height)
+	vp.KeyMap.PageDown = DefaultKeyMap.PageDown
+	vp.KeyMap.PageUp = DefaultKeyMap.PageUp
+	vp.KeyMap.HalfPageDown = DefaultKeyMap.HalfScreenDown
+	vp.KeyMap.HalfPageUp = DefaultKeyMap.HalfScreenUp
+	vp.KeyMap.Down = DefaultKeyMap.Down
+	vp.KeyMap.Up = DefaultKeyMap.Up
+
+	return vp
+}
+
 // Model is the codeview model. Use New to create a new instance.
 type Model struct {
 	viewport      viewport.Model
@@ -78,13 +133,125 @@ type Model struct {
 	lines         []string
 	filteredLines filteredLines
 	showHelp      bool
+
+	statusMessage   string
+	statusMessageID int
+
+	// coverage and legend are displayed in the footer when there is no
+	// status message
+	coverage string
+	legend   string
+
+	// warning is displayed under the title, for example when the coverage
+	// may not match the content
+	warning string
+
+	// info follows the title, for example the last commit of the file
+	info string
+
+	// rows maps line numbers to the rows they are rendered at;
+	// contentRows is the number of rendered rows
+	rows        map[int]int
+	contentRows int
+
+	uncoveredBlocks       []LineRange
+	currentUncoveredBlock int
+
+	// deltaLines are the lines whose coverage changed since another
+	// profile: true for the newly covered ones, false for the newly
+	// uncovered ones
+	deltaLines map[int]bool
+
+	// coveredBlocks and uncoveredBlocks color the line numbers
+	coveredBlocks   []LineRange
+	showLineNumbers bool
+
+	// xOffset is the number of columns hidden on the left; lineWidth is the
+	// number of columns available for the code
+	xOffset   int
+	lineWidth int
+
+	// uncoveredOnly folds the code that is not within foldContext lines of
+	// an uncovered block
+	uncoveredOnly bool
+	foldContext   int
+
+	// gapsOnly displays nothing but the uncovered lines, one per row
+	gapsOnly bool
+
+	// minimap summarizes the coverage of the whole file next to the code
+	minimap bool
+
+	// hunks are the hunks of the diff of the file, displayed instead of the
+	// whole file in the diff view
+	hunks    []DiffHunk
+	diffView bool
+
+	// filterContext is the number of lines displayed around filtered lines
+	filterContext int
+
+	// searchInput edits the query while searching; the query stays
+	// highlighted after the input is closed, until the search is cleared.
+	// matches are the numbers of the lines with matches.
+	searchInput   textinput.Model
+	searching     bool
+	query         string
+	caseSensitive bool
+	matches       []int
+	currentMatch  int
+
+	// folds are the first lines of the covered blocks folded into a single
+	// line; foldPending is set when the fold key is pressed, and the next key
+	// selects the command
+	folds       map[int]bool
+	foldPending bool
+
+	// topLine is the line at the top of the screen before the last toggle of
+	// folding, and topOffset is the offset right after it; the line is
+	// restored by the next toggle unless the code was scrolled in between
+	topLine   int
+	topOffset int
 }
 
 // Update is used to update the internal model state based on the external
 // events.
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(statusMessageTimeoutMsg); ok {
+		if msg.id == m.statusMessageID {
+			m.statusMessage = ""
+		}
+
+		return m, nil
+	}
+
+	if m.searching {
+		return m.updateSearch(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok && m.foldPending {
+		return m, m.updateFold(msg)
+	}
+
 	// TODO: support number-based navigation <29-01-22, yury> //
 	if msg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(msg, DefaultKeyMap.Search) {
+			return m, m.startSearch()
+		}
+
+		// with a query, "n" and "N" cycle through the matches, and "esc"
+		// clears them instead of going back
+		if m.query != "" {
+			switch {
+			case key.Matches(msg, DefaultKeyMap.NextUncovered):
+				return m, m.gotoMatch(1)
+			case key.Matches(msg, DefaultKeyMap.PrevUncovered):
+				return m, m.gotoMatch(-1)
+			case key.Matches(msg, DefaultKeyMap.Back):
+				m.ClearSearch()
+				return m, nil
+			}
+		}
+
 		if key.Matches(msg, DefaultKeyMap.Home) {
 			_ = m.viewport.GotoTop()
 			return m, nil
@@ -94,6 +261,60 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			_ = m.viewport.GotoBottom()
 			return m, nil
 		}
+
+		if key.Matches(msg, DefaultKeyMap.NextUncovered) {
+			return m, m.gotoUncoveredBlock(1)
+		}
+
+		if key.Matches(msg, DefaultKeyMap.PrevUncovered) {
+			return m, m.gotoUncoveredBlock(-1)
+		}
+
+		if key.Matches(msg, DefaultKeyMap.ScrollLeft) {
+			m.scrollHorizontally(-horizontalStep)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.ScrollRight) {
+			m.scrollHorizontally(horizontalStep)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.ScrollReset) {
+			m.scrollHorizontally(-m.xOffset)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.Fold) {
+			m.foldPending = true
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.UncoveredOnly) {
+			m.SetUncoveredOnly(!m.uncoveredOnly)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.GapsOnly) {
+			m.SetGapsOnly(!m.gapsOnly)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.DiffView) {
+			return m, m.toggleDiffView()
+		}
+
+		if key.Matches(msg, DefaultKeyMap.Minimap) {
+			m.SetMinimap(!m.minimap)
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.LineNumbers) {
+			m.showLineNumbers = !m.showLineNumbers
+			m.redrawLines()
+
+			return m, nil
+		}
 	}
 
 	vp, cmd := m.viewport.Update(msg)
@@ -108,6 +329,12 @@ func (m *Model) View() string {
 	footerView := m.footerView()
 	codeView := m.viewport.View()
 
+	if m.minimap {
+		codeView = lipgloss.JoinHorizontal(
+			lipgloss.Top, lipgloss.PlaceHorizontal(m.codeWidth(), lipgloss.Left, codeView), m.minimapView(),
+		)
+	}
+
 	sections := make([]string, 0, 4)
 	sections = append(sections, headerView, codeView, footerView)
 
@@ -122,20 +349,320 @@ func (m *Model) View() string {
 func (m *Model) SetContent(lines []string) {
 	// save the original lines to not lose content in case of window resizing
 	m.lines = lines
-	m.redrawLines()
+	m.statusMessage = ""
+	m.xOffset = 0
+	m.topLine = 0
+	m.ClearSearch()
 	m.viewport.SetYOffset(0)
 }
 
+// UpdateContent replaces the content of the codeview, but unlike SetContent,
+// keeps the current scroll position when possible.
+func (m *Model) UpdateContent(lines []string) {
+	offset := m.viewport.YOffset
+
+	m.lines = lines
+	m.updateMatches()
+	m.redrawLines()
+	m.viewport.SetYOffset(offset)
+}
+
+// NewStatusMessage shows a short message in the footer. The message is hidden
+// after a short period using the returned command.
+func (m *Model) NewStatusMessage(s string) tea.Cmd {
+	m.statusMessage = s
+	m.statusMessageID++
+	id := m.statusMessageID
+
+	return tea.Tick(statusMessageLifetime, func(time.Time) tea.Msg {
+		return statusMessageTimeoutMsg{id: id}
+	})
+}
+
+// SetLegend sets a short explanation of the content, such as the meaning of
+// coverage symbols. It is displayed in the footer, unless there is a status
+// message.
+func (m *Model) SetLegend(legend string) {
+	m.legend = legend
+}
+
+// SetWarning sets a warning displayed under the title until it is cleared
+// with an empty string.
+func (m *Model) SetWarning(warning string) {
+	m.warning = warning
+	m.recalculateSize()
+}
+
+// SetInfo sets the details of the file displayed next to its title, such as
+// its last commit, until they are cleared with an empty string.
+func (m *Model) SetInfo(info string) {
+	m.info = info
+}
+
+// SetCoverage sets the number of covered statements in the file, and the
+// total number of its statements. They are displayed in the footer, unless
+// there is a status message.
+func (m *Model) SetCoverage(covered, total int64) {
+	var percentage float64
+	if total > 0 {
+		percentage = float64(covered) / float64(total) * 100
+	}
+
+	m.coverage = fmt.Sprintf("%d/%d statements covered (%.1f%%)", covered, total, percentage)
+}
+
+// SetDiffCoverage sets the number of covered statements among the changed
+// ones, and the total number of changed statements. They are displayed in
+// the footer instead of the coverage of the whole file.
+func (m *Model) SetDiffCoverage(covered, total int64) {
+	var percentage float64
+	if total > 0 {
+		percentage = float64(covered) / float64(total) * 100
+	}
+
+	m.coverage = fmt.Sprintf("%d/%d changed statements covered (%.1f%%)", covered, total, percentage)
+}
+
+// scrollHorizontally moves the code by the number of columns, to the right
+// for positive numbers. The code doesn't move past the end of its longest
+// line.
+func (m *Model) scrollHorizontally(columns int) {
+	maxOffset := 0
+
+	for _, line := range m.lines {
+		maxOffset = max(maxOffset, lipgloss.Width(m.replaceTabsWithSpaces(line))-m.lineWidth)
+	}
+
+	offset := min(max(0, m.xOffset+columns), maxOffset)
+	if offset == m.xOffset {
+		return
+	}
+
+	m.xOffset = offset
+	m.redrawLines()
+}
+
+// SetUncoveredOnly enables or disables folding of the code around uncovered
+// blocks. Only the uncovered blocks and the lines around them are displayed,
+// unless the lines are filtered, or there are no uncovered blocks. The line
+// at the top of the screen stays there when possible.
+func (m *Model) SetUncoveredOnly(uncoveredOnly bool) {
+	if m.uncoveredOnly == uncoveredOnly {
+		return
+	}
+
+	m.keepTopLine(func() { m.uncoveredOnly = uncoveredOnly })
+}
+
+// SetGapsOnly enables or disables hiding everything but the uncovered lines,
+// which are displayed one per row with their line numbers. It takes
+// precedence over folding and filtering, unless there are no uncovered
+// blocks.
+func (m *Model) SetGapsOnly(gapsOnly bool) {
+	if m.gapsOnly == gapsOnly {
+		return
+	}
+
+	m.keepTopLine(func() { m.gapsOnly = gapsOnly })
+}
+
+// keepTopLine redraws the lines after the change of the display mode, so that
+// the line at the top of the screen, or the next one displayed, stays there.
+func (m *Model) keepTopLine(change func()) {
+	topLine := m.lineAtRow(m.viewport.YOffset)
+	if m.topLine > 0 && m.viewport.YOffset == m.topOffset {
+		topLine = m.topLine
+	}
+
+	change()
+	m.redrawLines()
+
+	for line := topLine; line > 0 && line <= len(m.lines); line++ {
+		if row, ok := m.rows[line]; ok {
+			m.viewport.SetYOffset(row)
+			break
+		}
+	}
+
+	m.topLine, m.topOffset = topLine, m.viewport.YOffset
+}
+
+// SetFoldContext sets the number of lines displayed around uncovered blocks
+// when covered code is folded.
+func (m *Model) SetFoldContext(lines int) {
+	m.foldContext = lines
+	m.redrawLines()
+}
+
+// TopLine returns the line at the top of the screen.
+func (m *Model) TopLine() int {
+	return m.lineAtRow(m.viewport.YOffset)
+}
+
+// lineAtRow returns the first line rendered at or after the row.
+func (m *Model) lineAtRow(row int) int {
+	line := 0
+
+	for l, r := range m.rows {
+		if r >= row && (line == 0 || l < line) {
+			line = l
+		}
+	}
+
+	return line
+}
+
+// foldedRanges returns the ranges of lines displayed when covered code is
+// folded, or nil if nothing is folded.
+func (m *Model) foldedRanges(total int) []LineRange {
+	if !m.uncoveredOnly || len(m.filteredLines.actualLines) > 0 || len(m.uncoveredBlocks) == 0 {
+		return nil
+	}
+
+	ranges := make([]LineRange, 0, len(m.uncoveredBlocks))
+
+	for _, b := range m.uncoveredBlocks {
+		r := LineRange{Start: max(1, b.Start-m.foldContext), End: min(total, b.End+m.foldContext)}
+		if r.Start > r.End {
+			continue
+		}
+
+		if n := len(ranges); n > 0 && r.Start <= ranges[n-1].End+1 {
+			ranges[n-1].End = max(ranges[n-1].End, r.End)
+			continue
+		}
+
+		ranges = append(ranges, r)
+	}
+
+	return ranges
+}
+
+// gapLines returns the uncovered lines in order when only the gaps are
+// displayed, or nil otherwise.
+func (m *Model) gapLines(total int) []int {
+	if !m.gapsOnly || len(m.uncoveredBlocks) == 0 {
+		return nil
+	}
+
+	lines := make([]int, 0, len(m.uncoveredBlocks))
+	next := 1
+
+	for _, b := range m.uncoveredBlocks {
+		for number := max(b.Start, next); number <= min(b.End, total); number++ {
+			lines = append(lines, number)
+		}
+
+		next = max(next, b.End+1)
+	}
+
+	return lines
+}
+
+// gapsView renders the number of uncovered lines from the top of the screen
+// to the end of the file while only the gaps are displayed.
+func (m *Model) gapsView() string {
+	gaps := m.gapLines(len(m.lines))
+	if gaps == nil {
+		return ""
+	}
+
+	remaining := max(0, len(gaps)-m.viewport.YOffset)
+	if remaining == 1 {
+		return "1 uncovered line left"
+	}
+
+	return fmt.Sprintf("%d uncovered lines left", remaining)
+}
+
+// SetCoveredBlocks sets the ranges of lines that are covered, in order. They
+// are used to color the line numbers.
+func (m *Model) SetCoveredBlocks(blocks []LineRange) {
+	m.coveredBlocks = blocks
+	m.redrawLines()
+}
+
+// SetDeltaLines marks the lines that are covered now, but were not covered
+// in another profile, and the ones that are no longer covered.
+func (m *Model) SetDeltaLines(newlyCovered, newlyUncovered []int) {
+	m.deltaLines = make(map[int]bool, len(newlyCovered)+len(newlyUncovered))
+
+	for _, line := range newlyCovered {
+		m.deltaLines[line] = true
+	}
+
+	for _, line := range newlyUncovered {
+		m.deltaLines[line] = false
+	}
+
+	m.redrawLines()
+}
+
+// SetUncoveredBlocks sets the ranges of lines that are not covered, in order.
+// They are used to navigate between coverage gaps.
+func (m *Model) SetUncoveredBlocks(blocks []LineRange) {
+	m.uncoveredBlocks = blocks
+	m.currentUncoveredBlock = -1
+}
+
+// gotoUncoveredBlock scrolls to the next (step > 0) or previous (step < 0)
+// uncovered block, wrapping around at both ends. Blocks that are hidden by
+// the line filter are skipped.
+func (m *Model) gotoUncoveredBlock(step int) tea.Cmd {
+	total := len(m.uncoveredBlocks)
+	if total == 0 {
+		return m.NewStatusMessage("No uncovered blocks")
+	}
+
+	idx := m.currentUncoveredBlock
+
+	// the first step backwards should go to the last block
+	if idx < 0 && step < 0 {
+		idx = 0
+	}
+
+	for i := 0; i < total; i++ {
+		idx = ((idx+step)%total + total) % total
+
+		if row, ok := m.blockRow(m.uncoveredBlocks[idx]); ok {
+			m.currentUncoveredBlock = idx
+			m.viewport.SetYOffset(row)
+
+			return m.NewStatusMessage(fmt.Sprintf("Uncovered block %d of %d", idx+1, total))
+		}
+	}
+
+	return m.NewStatusMessage("No visible uncovered blocks")
+}
+
+// blockRow returns the row of the first rendered line of the block.
+func (m *Model) blockRow(block LineRange) (int, bool) {
+	for line := block.Start; line <= block.End; line++ {
+		if row, ok := m.rows[line]; ok {
+			return row, true
+		}
+	}
+
+	return 0, false
+}
+
+// SetFilterContext sets the number of lines displayed around the filtered
+// lines. It applies to the lines filtered afterwards.
+func (m *Model) SetFilterContext(lines int) {
+	m.filterContext = lines
+}
+
 // SetFilteredLines sets the lines that should be displayed, while all other
 // lines are hidden. If not set, everything is displayed.
 func (m *Model) SetFilteredLines(filteredLines []int) {
-	m.filteredLines = contextifyFilteredLines(filteredLines)
+	m.filteredLines = contextifyFilteredLines(filteredLines, m.filterContext)
 	m.redrawLines()
 	m.viewport.SetYOffset(0)
 }
 
 func (m *Model) redrawLines() {
 	content := m.formatLines(m.lines)
+	m.contentRows = strings.Count(content, newLine) + 1
 	m.viewport.SetContent(content)
 }
 
@@ -149,6 +676,24 @@ func (m *Model) SetHeight(height int) {
 	m.setSize(m.width, height)
 }
 
+// SetSize sets both the width and the height of the codeview, such as when
+// the terminal is resized. The code stays scrolled to the same relative
+// position, so that the top stays at the top and the bottom at the bottom.
+func (m *Model) SetSize(width, height int) {
+	ratio := 0.0
+	if maxOffset := m.maxYOffset(); maxOffset > 0 {
+		ratio = math.Min(float64(m.viewport.YOffset)/float64(maxOffset), 1)
+	}
+
+	m.setSize(width, height)
+	m.viewport.SetYOffset(int(math.Round(ratio * float64(m.maxYOffset()))))
+}
+
+// maxYOffset returns the offset of the viewport scrolled to the bottom.
+func (m *Model) maxYOffset() int {
+	return max(0, m.contentRows-m.viewport.Height)
+}
+
 // Width returns the width of the codeview.
 func (m *Model) Width() int {
 	return m.width
@@ -172,6 +717,7 @@ func (m *Model) ShortHelp() []key.Binding {
 		DefaultKeyMap.Home,
 		DefaultKeyMap.End,
 		DefaultKeyMap.Back,
+		DefaultKeyMap.Help,
 	}
 }
 
@@ -179,8 +725,19 @@ func (m *Model) ShortHelp() []key.Binding {
 func (m *Model) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{DefaultKeyMap.Up, DefaultKeyMap.Down, DefaultKeyMap.Home, DefaultKeyMap.End},
-		{DefaultKeyMap.HalfScreenDown, DefaultKeyMap.HalfScreenUp},
-		{DefaultKeyMap.Back, DefaultKeyMap.Quit},
+		{DefaultKeyMap.PageDown, DefaultKeyMap.PageUp, DefaultKeyMap.HalfScreenDown, DefaultKeyMap.HalfScreenUp},
+		{DefaultKeyMap.ScrollLeft, DefaultKeyMap.ScrollRight, DefaultKeyMap.ScrollReset},
+		{
+			DefaultKeyMap.NextUncovered, DefaultKeyMap.PrevUncovered, DefaultKeyMap.UncoveredOnly,
+			DefaultKeyMap.GapsOnly, DefaultKeyMap.DiffView, DefaultKeyMap.Minimap, DefaultKeyMap.Fold, DefaultKeyMap.Recenter,
+			DefaultKeyMap.LineNumbers, DefaultKeyMap.Syntax, DefaultKeyMap.Heatmap, DefaultKeyMap.Legend,
+		},
+		{DefaultKeyMap.Search, DefaultKeyMap.SearchCase},
+		{
+			DefaultKeyMap.Export, DefaultKeyMap.Funcs, DefaultKeyMap.Blocks, DefaultKeyMap.Tests, DefaultKeyMap.CopyPath,
+			DefaultKeyMap.CopyUncovered, DefaultKeyMap.OpenEditor, DefaultKeyMap.OpenBrowser,
+		},
+		{DefaultKeyMap.Back, DefaultKeyMap.Help, DefaultKeyMap.Quit},
 	}
 }
 
@@ -200,6 +757,7 @@ func (m *Model) setSize(width, height int) {
 	m.height = height
 	m.width = width
 	m.help.Width = width // this is required for full help
+	m.viewport.Width = m.codeWidth()
 	m.recalculateSize()
 	m.redrawLines()
 }
@@ -232,9 +790,21 @@ func (m *Model) formatLines(lines []string) string {
 		printSingleLine = m.linePrinter(&buf, lineNumberStyle)
 	)
 
-	if filterApplied {
+	m.rows = make(map[int]int, len(lines))
+
+	if m.diffView && len(m.hunks) > 0 {
+		m.formatDiffHunks(&buf, lines, lineNumberStyle)
+	} else if gaps := m.gapLines(len(lines)); gaps != nil {
+		for row, number := range gaps {
+			m.rows[number] = row
+			printSingleLine(lines[number-1], number, false)
+		}
+	} else if folded := m.foldedRanges(len(lines)); folded != nil {
+		m.formatFoldedLines(&buf, lines, folded, printSingleLine)
+	} else if filterApplied {
 		lastPrintedLine := 0
-		separator := blankBlockSeparatorStyle.Render(strings.Repeat("─", max(0, m.width)))
+		separator := blankBlockSeparatorStyle.Render(strings.Repeat("─", max(0, m.codeWidth())))
+		row := 0
 
 		for _, thisLineNumber := range m.filteredLines.actualLines {
 			if thisLineNumber > len(lines) {
@@ -244,8 +814,13 @@ func (m *Model) formatLines(lines []string) string {
 			if thisLineNumber-lastPrintedLine > 1 {
 				buf.WriteString(separator)
 				buf.WriteString(newLine)
+
+				row += lipgloss.Height(separator)
 			}
 
+			m.rows[thisLineNumber] = row
+			row++
+
 			drawPlus := false
 			line := lines[thisLineNumber-1]
 
@@ -257,8 +832,19 @@ func (m *Model) formatLines(lines []string) string {
 			lastPrintedLine = thisLineNumber
 		}
 	} else {
-		for i, line := range lines {
-			printSingleLine(line, i+1, false)
+		for number, row := 1, 0; number <= len(lines); number, row = number+1, row+1 {
+			m.rows[number] = row
+			line := lines[number-1]
+
+			// folded blocks are summarized by their first line
+			if end := m.foldedBlockEnd(number); end > 0 {
+				printSingleLine(line+foldSeparatorStyle.Render(fmt.Sprintf("%s %d lines", ellipsis, end-number)), number, false)
+				number = end
+
+				continue
+			}
+
+			printSingleLine(line, number, false)
 		}
 	}
 
@@ -267,23 +853,86 @@ func (m *Model) formatLines(lines []string) string {
 
 type linePrinterFunc func(line string, number int, drawPlus bool)
 
+// formatFoldedLines prints the ranges of lines, and replaces the lines between
+// them with separators.
+func (m *Model) formatFoldedLines(buf *strings.Builder, lines []string, ranges []LineRange, print linePrinterFunc) {
+	row, next := 0, 1
+
+	printSeparator := func(hidden int) {
+		text := fmt.Sprintf("%s %d lines %s", ellipsis, hidden, ellipsis)
+		if hidden == 1 {
+			text = fmt.Sprintf("%s 1 line %s", ellipsis, ellipsis)
+		}
+
+		buf.WriteString(foldSeparatorStyle.Render(text))
+		buf.WriteString(newLine)
+
+		row++
+	}
+
+	for _, r := range ranges {
+		if r.Start > next {
+			printSeparator(r.Start - next)
+		}
+
+		for number := r.Start; number <= r.End; number++ {
+			m.rows[number] = row
+			row++
+
+			print(lines[number-1], number, false)
+		}
+
+		next = r.End + 1
+	}
+
+	if next <= len(lines) {
+		printSeparator(len(lines) - next + 1)
+	}
+}
+
 func (m *Model) linePrinter(buf *strings.Builder, lineNumberStyle lipgloss.Style) linePrinterFunc {
 	filterApplied := len(m.filteredLines.actualLines) > 0
-	lineNumberPlaceholder := lineNumberStyle.Render("1")
-	availableWidth := m.width - lipgloss.Width(lineNumberPlaceholder) - lipgloss.Width(ellipsis)
+	lineNumberPlaceholder := ""
+
+	if m.showLineNumbers {
+		lineNumberPlaceholder = lineNumberStyle.Render("1")
+	}
+
+	availableWidth := m.codeWidth() - lipgloss.Width(lineNumberPlaceholder) - lipgloss.Width(ellipsis)
+	m.lineWidth = availableWidth
 	renderedPlus := styles.CurrentTheme.CoveredLine.Render("+ ")
+	renderedMinus := styles.CurrentTheme.UncoveredLine.Render("- ")
 	renderedSpace := styles.CurrentTheme.NeutralLine.Render("  ")
+	lineColors := m.lineNumberColors()
 
 	return func(line string, number int, drawPlus bool) {
-		line = m.replaceTabsWithSpaces(line)
-		lineNumber := lineNumberStyle.Render(fmt.Sprintf("%d", number))
+		line = cutLeft(m.highlightQuery(m.replaceTabsWithSpaces(line)), m.xOffset)
+		lineNumber := ""
 		prefix := ""
 
-		if filterApplied {
-			if drawPlus {
+		if m.showLineNumbers {
+			style := lineNumberStyle
+
+			if color, ok := lineColors[number]; ok {
+				style = style.Copy().Foreground(color)
+			}
+
+			lineNumber = style.Render(fmt.Sprintf("%d", number))
+		}
+
+		switch {
+		case filterApplied && drawPlus:
+			prefix = renderedPlus
+		case filterApplied:
+			prefix = renderedSpace
+		case len(m.deltaLines) > 0:
+			// lines whose coverage changed are marked like in diffs
+			prefix = renderedSpace
+
+			if covered, ok := m.deltaLines[number]; ok && covered {
 				prefix = renderedPlus
-			} else {
-				prefix = renderedSpace
+			} else if ok {
+				prefix = renderedMinus
 			}
 		}
 
@@ -296,6 +945,32 @@ func (m *Model) linePrinter(buf *strings.Builder, lineNumberStyle lipgloss.Style
 	}
 }
 
+// lineNumberColors returns the colors of the numbers of covered and uncovered
+// lines. Lines that are partially covered have their own color.
+func (m *Model) lineNumberColors() map[int]lipgloss.Color {
+	colors := map[int]lipgloss.Color{}
+	covered := map[int]bool{}
+
+	for _, r := range m.coveredBlocks {
+		for line := r.Start; line <= r.End; line++ {
+			colors[line] = lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+			covered[line] = true
+		}
+	}
+
+	for _, r := range m.uncoveredBlocks {
+		for line := r.Start; line <= r.End; line++ {
+			colors[line] = lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+
+			if covered[line] {
+				colors[line] = lipgloss.Color(styles.CurrentTheme.PartialColor)
+			}
+		}
+	}
+
+	return colors
+}
+
 func (m *Model) replaceTabsWithSpaces(line string) string {
 	return strings.ReplaceAll(line, "\t", "    ")
 }
@@ -310,14 +985,47 @@ func (m *Model) headerView() string {
 	title := fileTitleStyle.Render(truncatedTitle)
 	line := strings.Repeat("─", max(0, m.width-lipgloss.Width(title)))
 
-	return lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+	if available := m.width - lipgloss.Width(title) - 4; m.info != "" && available > 0 {
+		info := statusMessageStyle.Render(truncate.StringWithTail(m.info, uint(available), ellipsis))
+		line = "──" + info + strings.Repeat("─", max(0, m.width-lipgloss.Width(title)-2-lipgloss.Width(info)))
+	}
+
+	header := lipgloss.JoinHorizontal(lipgloss.Center, title, line)
+
+	if m.warning == "" {
+		return header
+	}
+
+	availableWidth := max(0, m.width-warningStyle.GetHorizontalPadding())
+	warning := truncate.StringWithTail(m.warning, uint(availableWidth), ellipsis)
+	style := warningStyle.Copy().Foreground(lipgloss.Color(styles.CurrentTheme.SecondaryColor))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, style.Render(warning))
 }
 
 func (m *Model) footerView() string {
 	info := infoStyle.Render(fmt.Sprintf("%3.f%%", m.viewport.ScrollPercent()*100))
-	line := strings.Repeat("─", max(0, m.width-lipgloss.Width(info)))
 
-	return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
+	message := m.statusMessage
+
+	switch {
+	case m.searching:
+		message = m.searchView()
+	case message == "":
+		message = joinNonEmpty(" • ", m.searchView(), m.gapsView(), m.coverage, m.legend)
+	}
+
+	if message == "" {
+		line := strings.Repeat("─", max(0, m.width-lipgloss.Width(info)))
+		return lipgloss.JoinHorizontal(lipgloss.Center, line, info)
+	}
+
+	prefix := "──"
+	availableWidth := max(0, m.width-lipgloss.Width(info)-lipgloss.Width(prefix)-statusMessageStyle.GetHorizontalPadding())
+	status := statusMessageStyle.Render(truncate.StringWithTail(message, uint(availableWidth), ellipsis))
+	line := strings.Repeat("─", max(0, m.width-lipgloss.Width(info)-lipgloss.Width(prefix)-lipgloss.Width(status)))
+
+	return lipgloss.JoinHorizontal(lipgloss.Center, prefix, status, line, info)
 }
 
 func (m *Model) helpView() (res string) {
@@ -328,54 +1036,88 @@ func (m *Model) helpView() (res string) {
 	return ""
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
+func joinNonEmpty(sep string, elems ...string) string {
+	nonEmpty := make([]string, 0, len(elems))
+
+	for _, e := range elems {
+		if e != "" {
+			nonEmpty = append(nonEmpty, e)
+		}
 	}
 
-	return b
+	return strings.Join(nonEmpty, sep)
 }
 
-func contextifyFilteredLines(input []int) filteredLines {
-	if len(input) == 0 {
-		return filteredLines{
-			actualLines:  input,
-			contextLines: map[int]bool{},
+// cutLeft removes the first columns of the string. Escape sequences are kept,
+// so that the rest of the string is styled the same way.
+func cutLeft(s string, columns int) string {
+	if columns <= 0 {
+		return s
+	}
+
+	var (
+		b      strings.Builder
+		escape bool
+	)
+
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			// ansi sequences end with a letter
+			escape = !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+		case columns > 0:
+			columns -= runewidth.RuneWidth(r)
+			continue
 		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
 	}
 
-	extendedLines := make([]int, 0, len(input)+2)
-	contextLines := make(map[int]bool, 2)
-	lastAddedNumber := input[0]
+	return b
+}
 
-	beforeFirst := lastAddedNumber - 1
-	if beforeFirst > 0 {
-		extendedLines = append(extendedLines, beforeFirst)
-		contextLines[beforeFirst] = true
+func max(a, b int) int {
+	if a > b {
+		return a
 	}
 
+	return b
+}
+
+// contextifyFilteredLines adds the lines around the input lines, up to
+// context lines on each side. The added lines are marked as context lines.
+func contextifyFilteredLines(input []int, context int) filteredLines {
+	extendedLines := make([]int, 0, len(input)+2*context)
+	contextLines := map[int]bool{}
+	isInput := make(map[int]bool, len(input))
+
 	for _, lineNumber := range input {
-		if lineNumber-lastAddedNumber > 1 {
-			afterLast := lastAddedNumber + 1
-			beforeThis := lineNumber - 1
+		isInput[lineNumber] = true
+	}
 
-			extendedLines = append(extendedLines, afterLast)
-			contextLines[afterLast] = true
+	lastAddedNumber := 0
 
-			if afterLast != beforeThis {
-				extendedLines = append(extendedLines, beforeThis)
-				contextLines[beforeThis] = true
+	for _, lineNumber := range input {
+		for l := max(max(1, lineNumber-context), lastAddedNumber+1); l <= lineNumber+context; l++ {
+			if !isInput[l] {
+				contextLines[l] = true
 			}
-		}
 
-		extendedLines = append(extendedLines, lineNumber)
-		lastAddedNumber = lineNumber
+			extendedLines = append(extendedLines, l)
+			lastAddedNumber = l
+		}
 	}
 
-	lineAfterLast := input[len(input)-1] + 1
-	extendedLines = append(extendedLines, lineAfterLast)
-	contextLines[lineAfterLast] = true
-
 	return filteredLines{
 		actualLines:  extendedLines,
 		contextLines: contextLines,
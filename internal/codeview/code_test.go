@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
 	"github.com/stretchr/testify/require"
 )
 
@@ -77,13 +80,29 @@ func TestContextifyFilteredLines(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("%v", test.input), func(t *testing.T) {
-			output := contextifyFilteredLines(test.input)
+			output := contextifyFilteredLines(test.input, defaultFilterContext)
 			require.EqualValues(t, test.expectedActual, output.actualLines)
 			require.EqualValues(t, test.expectedContext, output.contextLines)
 		})
 	}
 }
 
+func TestContextifyFilteredLinesWithContext(t *testing.T) {
+	t.Parallel()
+
+	output := contextifyFilteredLines([]int{2, 10, 14}, 3)
+	require.EqualValues(t, []int{1, 2, 3, 4, 5, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17}, output.actualLines)
+	require.EqualValues(t, map[int]bool{
+		1: true, 3: true, 4: true, 5: true,
+		7: true, 8: true, 9: true, 11: true, 12: true, 13: true,
+		15: true, 16: true, 17: true,
+	}, output.contextLines)
+
+	output = contextifyFilteredLines([]int{2, 3}, 0)
+	require.EqualValues(t, []int{2, 3}, output.actualLines)
+	require.Empty(t, output.contextLines)
+}
+
 func Range(from, to int) []int {
 	result := make([]int, 0, to-from)
 
@@ -93,3 +112,315 @@ func Range(from, to int) []int {
 
 	return result
 }
+
+func TestFooterCoverage(t *testing.T) {
+	t.Parallel()
+
+	m := New(60, 20)
+	m.SetWidth(60)
+	m.SetCoverage(42, 50)
+	require.Contains(t, m.footerView(), "42/50 statements covered (84.0%)")
+
+	m.SetLegend("+ covered")
+	require.Contains(t, m.footerView(), "42/50 statements covered (84.0%) • + covered")
+
+	m.NewStatusMessage("Copied")
+	require.NotContains(t, m.footerView(), "statements covered")
+
+	narrow := New(20, 20)
+	narrow.SetWidth(20)
+	narrow.SetCoverage(42, 50)
+	require.Equal(t, 20, lipgloss.Width(narrow.footerView()))
+	require.Contains(t, narrow.footerView(), "42/50 s…")
+}
+
+func TestLineNumberColors(t *testing.T) {
+	styles.CurrentTheme = styles.Default()
+	require.NoError(t, styles.CurrentTheme.Customize(styles.Customization{PartialColor: "yellow"}))
+
+	t.Cleanup(func() { styles.CurrentTheme = styles.Theme{} })
+
+	m := New(40, 20)
+	m.SetCoveredBlocks([]LineRange{{Start: 1, End: 2}})
+	m.SetUncoveredBlocks([]LineRange{{Start: 2, End: 3}})
+
+	colors := m.lineNumberColors()
+	require.Equal(t, lipgloss.Color("#00ff00"), colors[1])
+	require.Equal(t, lipgloss.Color("3"), colors[2])
+	require.Equal(t, lipgloss.Color("#ff0000"), colors[3])
+}
+
+func TestToggleLineNumbers(t *testing.T) {
+	t.Parallel()
+
+	m := New(40, 20)
+	m.SetWidth(40)
+	m.SetHeight(20)
+	m.SetContent([]string{"package foo", "", "func Foo() {}"})
+	require.Contains(t, m.View(), "│ func Foo() {}")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	require.NotContains(t, m.View(), "│ func Foo() {}")
+	require.Contains(t, m.View(), "\nfunc Foo() {}")
+
+	// the setting is kept for the next file
+	m.SetContent([]string{"package bar"})
+	require.NotContains(t, m.View(), "│ package bar")
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	require.Contains(t, m.View(), "│ package bar")
+}
+
+func TestCutLeft(t *testing.T) {
+	t.Parallel()
+
+	styled := "\x1b[1mabc\x1b[0mdef"
+
+	require.Equal(t, "abcdef", cutLeft("abcdef", 0))
+	require.Equal(t, "def", cutLeft("abcdef", 3))
+	require.Equal(t, "", cutLeft("abc", 5))
+	require.Equal(t, "世界", cutLeft("a世界", 1))
+	require.Equal(t, "\x1b[1mc\x1b[0mdef", cutLeft(styled, 2))
+}
+
+func TestScrollHorizontally(t *testing.T) {
+	t.Parallel()
+
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	reset := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'0'}}
+
+	m := New(20, 20)
+	m.SetWidth(20)
+	m.SetHeight(20)
+	m.SetContent([]string{"short", "\ta very long line of code that ends here"})
+	require.NotContains(t, m.View(), "ends here")
+
+	for i := 0; i < 20; i++ {
+		m, _ = m.Update(right)
+	}
+
+	// the end of the longest line is visible, and the scrolling stops there
+	require.Contains(t, m.View(), "ends here")
+	require.Equal(t, lipgloss.Width("    a very long line of code that ends here")-m.lineWidth, m.xOffset)
+
+	m, _ = m.Update(reset)
+	require.Zero(t, m.xOffset)
+	require.Contains(t, m.View(), "short")
+}
+
+func TestUncoveredOnly(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 20)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	m := New(40, 30)
+	m.SetWidth(40)
+	m.SetHeight(30)
+	m.SetFoldContext(2)
+	m.SetUncoveredBlocks([]LineRange{{Start: 10, End: 10}, {Start: 19, End: 20}})
+	m.SetContent(lines)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+
+	view := m.View()
+	require.Contains(t, view, "… 7 lines …")
+	require.Contains(t, view, "│ line 8\n")
+	require.Contains(t, view, "│ line 12\n")
+	require.Contains(t, view, "… 4 lines …")
+	require.Contains(t, view, "│ line 17\n")
+	require.NotContains(t, view, "line 16\n")
+	require.NotContains(t, view, "line 7\n")
+
+	// real line numbers are kept, and separators take rows
+	require.Equal(t, 1, m.rows[8])
+	require.Equal(t, 7, m.rows[17])
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	require.NotContains(t, m.View(), "lines …")
+	require.Contains(t, m.View(), "│ line 7\n")
+}
+
+func TestFoldCoveredBlocks(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 30)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	m := New(40, 40)
+	m.SetWidth(40)
+	m.SetHeight(40)
+	m.SetCoveredBlocks([]LineRange{{Start: 3, End: 8}, {Start: 12, End: 12}, {Start: 15, End: 20}})
+	m.SetContent(lines)
+
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// the first block below the top of the screen is folded
+	m, _ = m.Update(runes("z"))
+	require.True(t, m.FoldPending())
+	m, _ = m.Update(runes("a"))
+	require.False(t, m.FoldPending())
+
+	view := m.View()
+	require.Contains(t, view, "│ line 3 … 5 lines\n")
+	require.NotContains(t, view, "line 4\n")
+	require.Contains(t, view, "│ line 9\n")
+	require.Equal(t, []int{3}, m.Folds())
+	require.Equal(t, 3, m.rows[9])
+
+	// folds are kept while scrolling
+	m, _ = m.Update(runes("G"))
+	m, _ = m.Update(runes("g"))
+	require.Contains(t, m.View(), "│ line 3 … 5 lines\n")
+
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("a"))
+	require.Empty(t, m.Folds())
+	require.Contains(t, m.View(), "│ line 4\n")
+
+	// single lines are not folded
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("M"))
+	require.Equal(t, []int{3, 15}, m.Folds())
+	require.Contains(t, m.View(), "│ line 15 … 5 lines\n")
+	require.Contains(t, m.View(), "│ line 12\n")
+
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("R"))
+	require.Empty(t, m.Folds())
+
+	// other keys cancel the command
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("x"))
+	require.False(t, m.FoldPending())
+	require.Empty(t, m.Folds())
+
+	m.SetFolds([]int{15})
+	require.Contains(t, m.View(), "│ line 15 … 5 lines\n")
+	require.Contains(t, m.View(), "│ line 4\n")
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 40)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	lines[4] = "func Foo() {"
+	lines[24] = "\x1b[31mcall(foo)\x1b[0m"
+
+	m := New(40, 10)
+	m.SetWidth(40)
+	m.SetHeight(10)
+	m.SetContent(lines)
+
+	typeKeys := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			m, _ = m.Update(k)
+		}
+	}
+
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	typeKeys(runes("/"), runes("f"), runes("o"), runes("o"))
+	require.True(t, m.Searching())
+	require.Contains(t, m.View(), "/foo")
+
+	typeKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	require.False(t, m.Searching())
+	require.Equal(t, []int{5, 25}, m.matches)
+	require.Contains(t, m.View(), "Match 1 of 2")
+	require.Contains(t, m.View(), "func "+matchStart+"Foo"+matchEnd)
+
+	typeKeys(runes("n"))
+	require.Contains(t, m.View(), "Match 2 of 2")
+	require.Equal(t, m.rows[25], m.viewport.YOffset)
+
+	require.Contains(t, m.View(), "call("+matchStart+"foo"+matchEnd+")")
+
+	typeKeys(runes("n"))
+	require.Contains(t, m.View(), "Match 1 of 2")
+
+	typeKeys(runes("/"), tea.KeyMsg{Type: tea.KeyTab}, tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, []int{25}, m.matches)
+
+	typeKeys(tea.KeyMsg{Type: tea.KeyEsc})
+	require.Empty(t, m.SearchQuery())
+	require.NotContains(t, m.View(), matchStart)
+
+	// without a query, "n" moves to uncovered blocks again
+	typeKeys(runes("n"))
+	require.Contains(t, m.View(), "No uncovered blocks")
+}
+
+func TestHighlightQuery(t *testing.T) {
+	t.Parallel()
+
+	m := New(40, 10)
+	m.query = "foo"
+
+	// the inverted colors survive the resets of the styles of the line
+	require.Equal(t,
+		"\x1b[31m"+matchStart+"f\x1b[0m"+matchStart+"\x1b[32m"+matchStart+"oo"+matchEnd+"\x1b[0m bar",
+		m.highlightQuery("\x1b[31mf\x1b[0m\x1b[32moo\x1b[0m bar"),
+	)
+	require.Equal(t, "bar", m.highlightQuery("bar"))
+}
+
+func TestFindMatches(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, [][2]int{{0, 2}, {4, 6}}, findMatches("Ab  ab", "ab", false))
+	require.Equal(t, [][2]int{{4, 6}}, findMatches("Ab  ab", "ab", true))
+	require.Equal(t, [][2]int{{0, 2}, {2, 4}}, findMatches("aaaaa", "aa", false))
+	require.Equal(t, [][2]int{{1, 2}}, findMatches("ñÑ", "Ñ", true))
+	require.Empty(t, findMatches("abc", "", false))
+}
+
+func TestRecenterUncoveredBlock(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 60)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i+1)
+	}
+
+	m := New(40, 10)
+	m.SetWidth(40)
+	m.SetHeight(10)
+	m.SetUncoveredBlocks([]LineRange{{Start: 5, End: 6}, {Start: 30, End: 31}})
+	m.SetContent(lines)
+
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	height := m.viewport.Height
+
+	// without a jump, the first block on the screen is recentered
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("z"))
+	require.False(t, m.FoldPending())
+	require.Equal(t, 0, m.viewport.YOffset)
+
+	m, _ = m.Update(runes("n"))
+	m, _ = m.Update(runes("n"))
+	require.Equal(t, m.rows[30], m.viewport.YOffset)
+
+	m, _ = m.Update(runes("z"))
+	m, _ = m.Update(runes("z"))
+	require.Equal(t, m.rows[30]-(height-2)/2, m.viewport.YOffset)
+
+	// blocks are not looked up off the screen
+	m.currentUncoveredBlock = -1
+	m.viewport.SetYOffset(m.rows[40])
+
+	m, _ = m.Update(runes("z"))
+	m, cmd := m.Update(runes("z"))
+	require.NotNil(t, cmd)
+	require.Contains(t, m.View(), "No uncovered block")
+	require.Equal(t, m.rows[40], m.viewport.YOffset)
+}
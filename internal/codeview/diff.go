@@ -0,0 +1,124 @@
+package codeview
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// DiffLineKind tells whether a line of a diff hunk is added, removed or
+// unchanged.
+type DiffLineKind int
+
+// Kinds of the lines of diff hunks.
+const (
+	DiffUnchanged DiffLineKind = iota
+	DiffAdded
+	DiffRemoved
+)
+
+// DiffLine is a line of a diff hunk. Number is the line in the new version of
+// the file, or in the old one for the removed lines.
+type DiffLine struct {
+	Kind   DiffLineKind
+	Number int
+	Text   string
+}
+
+// DiffHunk is a group of changed lines along with the unchanged lines around
+// them, as in unified diffs.
+type DiffHunk struct {
+	Header string
+	Lines  []DiffLine
+}
+
+var removedLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(lineNumberColor))
+
+// SetDiffHunks sets the hunks of the diff of the file, displayed instead of
+// the whole file in the diff view.
+func (m *Model) SetDiffHunks(hunks []DiffHunk) {
+	m.hunks = hunks
+	m.redrawLines()
+}
+
+// SetDiffView enables or disables the diff view, which displays the hunks of
+// the diff of the file with the coverage of the added lines. Files without
+// hunks are displayed as usual.
+func (m *Model) SetDiffView(diffView bool) {
+	if m.diffView == diffView {
+		return
+	}
+
+	m.keepTopLine(func() { m.diffView = diffView })
+}
+
+// toggleDiffView switches between the diff view and the whole file.
+func (m *Model) toggleDiffView() tea.Cmd {
+	if len(m.hunks) == 0 {
+		return m.NewStatusMessage("No diff of this file")
+	}
+
+	m.SetDiffView(!m.diffView)
+
+	return nil
+}
+
+// formatDiffHunks prints the hunks of the diff, each one after its header.
+// The added lines come from the file, so that they are colored according to
+// their coverage. The removed lines are dimmed.
+func (m *Model) formatDiffHunks(buf *strings.Builder, lines []string, lineNumberStyle lipgloss.Style) {
+	var (
+		lineColors = m.lineNumberColors()
+		signs      = map[DiffLineKind]string{DiffUnchanged: "  ", DiffAdded: "+ ", DiffRemoved: "- "}
+		row        = 0
+	)
+
+	for _, h := range m.hunks {
+		buf.WriteString(foldSeparatorStyle.Render(h.Header))
+		buf.WriteString(newLine)
+
+		row++
+
+		for _, l := range h.Lines {
+			text := styles.CurrentTheme.NeutralLine.Render(l.Text)
+			number := fmt.Sprintf("%d", l.Number)
+			style := lineNumberStyle
+
+			switch {
+			case l.Kind == DiffRemoved:
+				text, number = removedLineStyle.Render(l.Text), ""
+			case l.Number < 1 || l.Number > len(lines):
+				continue
+			case l.Kind == DiffAdded:
+				text = lines[l.Number-1]
+
+				if color, ok := lineColors[l.Number]; ok {
+					style = style.Copy().Foreground(color)
+				}
+
+				fallthrough
+			default:
+				m.rows[l.Number] = row
+			}
+
+			text = cutLeft(m.highlightQuery(m.replaceTabsWithSpaces(text)), m.xOffset)
+			if lipgloss.Width(text) > m.lineWidth {
+				text = truncate.StringWithTail(text, uint(max(0, m.lineWidth)), ellipsis)
+			}
+
+			lineNumber := ""
+			if m.showLineNumbers {
+				lineNumber = style.Render(number)
+			}
+
+			buf.WriteString(lipgloss.JoinHorizontal(lipgloss.Left, styles.CurrentTheme.NeutralLine.Render(signs[l.Kind]), lineNumber, text))
+			buf.WriteString(newLine)
+
+			row++
+		}
+	}
+}
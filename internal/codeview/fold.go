@@ -0,0 +1,198 @@
+package codeview
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Keys pressed after DefaultKeyMap.Fold, like in vim.
+var (
+	foldToggleKey = key.NewBinding(key.WithKeys("a"))
+	foldOpenKey   = key.NewBinding(key.WithKeys("R"))
+	foldCloseKey  = key.NewBinding(key.WithKeys("M"))
+)
+
+// FoldPending reports whether the fold key was pressed, and the next key
+// selects the fold command. All the keys should be passed to the model then.
+func (m *Model) FoldPending() bool {
+	return m.foldPending
+}
+
+// Folds returns the first lines of the folded covered blocks, in order.
+func (m *Model) Folds() []int {
+	folds := make([]int, 0, len(m.folds))
+
+	for line := range m.folds {
+		folds = append(folds, line)
+	}
+
+	sort.Ints(folds)
+
+	return folds
+}
+
+// SetFolds folds the covered blocks starting at the provided lines, and
+// unfolds all the others.
+func (m *Model) SetFolds(lines []int) {
+	m.folds = make(map[int]bool, len(lines))
+
+	for _, line := range lines {
+		m.folds[line] = true
+	}
+
+	m.redrawLines()
+}
+
+// updateFold runs the fold command selected by the key pressed after the
+// fold key. Other keys cancel the command.
+func (m *Model) updateFold(msg tea.KeyMsg) tea.Cmd {
+	m.foldPending = false
+
+	switch {
+	case key.Matches(msg, foldToggleKey):
+		return m.toggleFold()
+	case key.Matches(msg, foldOpenKey):
+		m.setAllFolds(false)
+		return m.NewStatusMessage("Unfolded all blocks")
+	case key.Matches(msg, foldCloseKey):
+		m.setAllFolds(true)
+		return m.NewStatusMessage(fmt.Sprintf("Folded %d covered blocks", len(m.folds)))
+	case key.Matches(msg, DefaultKeyMap.Recenter):
+		return m.recenterUncoveredBlock()
+	default:
+		return nil
+	}
+}
+
+// toggleFold folds or unfolds the covered block at the top of the screen, or
+// the next one below it.
+func (m *Model) toggleFold() tea.Cmd {
+	top := m.lineAtRow(m.viewport.YOffset)
+
+	for _, b := range m.foldableBlocks() {
+		if b.End < top {
+			continue
+		}
+
+		if m.folds == nil {
+			m.folds = map[int]bool{}
+		}
+
+		m.folds[b.Start] = !m.folds[b.Start]
+		if !m.folds[b.Start] {
+			delete(m.folds, b.Start)
+		}
+
+		m.redrawLines()
+
+		if row, ok := m.rows[b.Start]; ok && row < m.viewport.YOffset {
+			m.viewport.SetYOffset(row)
+		}
+
+		return nil
+	}
+
+	return m.NewStatusMessage("No covered blocks to fold")
+}
+
+// recenterUncoveredBlock scrolls so that the current uncovered block, or the
+// first one on the screen if none was jumped to, is in the middle of the
+// screen. Blocks taller than the screen are scrolled to the top.
+func (m *Model) recenterUncoveredBlock() tea.Cmd {
+	idx := m.currentUncoveredBlock
+	if idx < 0 {
+		idx = m.firstVisibleUncoveredBlock()
+	}
+
+	if idx < 0 {
+		return m.NewStatusMessage("No uncovered block on the screen")
+	}
+
+	block := m.uncoveredBlocks[idx]
+
+	start, ok := m.blockRow(block)
+	if !ok {
+		return m.NewStatusMessage("No visible uncovered blocks")
+	}
+
+	end := start
+
+	for line := block.End; line >= block.Start; line-- {
+		if row, ok := m.rows[line]; ok {
+			end = row
+			break
+		}
+	}
+
+	m.currentUncoveredBlock = idx
+	m.viewport.SetYOffset(start - max(0, m.viewport.Height-(end-start+1))/2)
+
+	return nil
+}
+
+// firstVisibleUncoveredBlock returns the index of the first uncovered block
+// starting on the screen, or -1 if there is none.
+func (m *Model) firstVisibleUncoveredBlock() int {
+	for i, block := range m.uncoveredBlocks {
+		row, ok := m.blockRow(block)
+		if ok && row >= m.viewport.YOffset && row < m.viewport.YOffset+m.viewport.Height {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// setAllFolds folds or unfolds all the covered blocks.
+func (m *Model) setAllFolds(folded bool) {
+	top := m.lineAtRow(m.viewport.YOffset)
+	m.folds = map[int]bool{}
+
+	if folded {
+		for _, b := range m.foldableBlocks() {
+			m.folds[b.Start] = true
+		}
+	}
+
+	m.redrawLines()
+
+	// keep the line at the top of the screen, or its fold, at the top
+	for line := top; line > 0; line-- {
+		if row, ok := m.rows[line]; ok {
+			m.viewport.SetYOffset(row)
+			break
+		}
+	}
+}
+
+// foldableBlocks returns the covered blocks of more than one line.
+func (m *Model) foldableBlocks() []LineRange {
+	blocks := make([]LineRange, 0, len(m.coveredBlocks))
+
+	for _, b := range m.coveredBlocks {
+		if b.End > b.Start && b.End <= len(m.lines) {
+			blocks = append(blocks, b)
+		}
+	}
+
+	return blocks
+}
+
+// foldedBlockEnd returns the last line of the folded block starting at the
+// line, or 0 if no fold starts there.
+func (m *Model) foldedBlockEnd(line int) int {
+	if !m.folds[line] {
+		return 0
+	}
+
+	for _, b := range m.foldableBlocks() {
+		if b.Start == line {
+			return b.End
+		}
+	}
+
+	return 0
+}
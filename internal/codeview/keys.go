@@ -10,8 +10,36 @@ type KeyMap struct {
 	End            key.Binding
 	Back           key.Binding
 	Quit           key.Binding
+	PageDown       key.Binding
+	PageUp         key.Binding
 	HalfScreenDown key.Binding
 	HalfScreenUp   key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	ScrollReset    key.Binding
+	Export         key.Binding
+	Funcs          key.Binding
+	Blocks         key.Binding
+	Tests          key.Binding
+	CopyPath       key.Binding
+	CopyUncovered  key.Binding
+	OpenEditor     key.Binding
+	OpenBrowser    key.Binding
+	Help           key.Binding
+	NextUncovered  key.Binding
+	PrevUncovered  key.Binding
+	LineNumbers    key.Binding
+	UncoveredOnly  key.Binding
+	GapsOnly       key.Binding
+	DiffView       key.Binding
+	Minimap        key.Binding
+	Fold           key.Binding
+	Recenter       key.Binding
+	Syntax         key.Binding
+	Heatmap        key.Binding
+	Legend         key.Binding
+	Search         key.Binding
+	SearchCase     key.Binding
 }
 
 // DefaultKeyMap is the default KeyMap used by codeview package.
@@ -40,12 +68,125 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("q", "ctrl+c"),
 		key.WithHelp("q", "quit"),
 	),
+	PageDown: key.NewBinding(
+		key.WithKeys(" ", "pgdown"),
+		key.WithHelp("space/pgdn", "page down"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("b", "pgup"),
+		key.WithHelp("b/pgup", "page up"),
+	),
 	HalfScreenDown: key.NewBinding(
-		key.WithKeys("d"),
+		key.WithKeys("d", "ctrl+d"),
 		key.WithHelp("d", "half screen down"),
 	),
 	HalfScreenUp: key.NewBinding(
-		key.WithKeys("u"),
-		key.WithHelp("u", "half screen down"),
+		key.WithKeys("u", "ctrl+u"),
+		key.WithHelp("u", "half screen up"),
+	),
+	ScrollLeft: key.NewBinding(
+		key.WithKeys("left", "h"),
+		key.WithHelp("←/h", "scroll left"),
+	),
+	ScrollRight: key.NewBinding(
+		key.WithKeys("right", "l"),
+		key.WithHelp("→/l", "scroll right"),
+	),
+	ScrollReset: key.NewBinding(
+		key.WithKeys("0"),
+		key.WithHelp("0", "first column"),
+	),
+	Export: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "export html"),
+	),
+	Funcs: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "functions"),
+	),
+	Blocks: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "profile blocks"),
+	),
+	Tests: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "tests of the package"),
+	),
+	CopyPath: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy path"),
+	),
+	CopyUncovered: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy uncovered lines"),
+	),
+	OpenEditor: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in $EDITOR"),
+	),
+	OpenBrowser: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "open in browser"),
+	),
+	LineNumbers: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "line numbers"),
+	),
+	UncoveredOnly: key.NewBinding(
+		key.WithKeys("U"),
+		key.WithHelp("U", "uncovered only"),
+	),
+	GapsOnly: key.NewBinding(
+		key.WithKeys("X"),
+		key.WithHelp("X", "gaps only"),
+	),
+	DiffView: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "diff hunks"),
+	),
+	Minimap: key.NewBinding(
+		key.WithKeys("|"),
+		key.WithHelp("|", "minimap"),
+	),
+	Fold: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("za/zR/zM", "fold covered"),
+	),
+	// Recenter is pressed after Fold, like zz in vim.
+	Recenter: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("zz", "recenter block"),
+	),
+	Syntax: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "syntax highlighting"),
+	),
+	Heatmap: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "hit count heatmap"),
+	),
+	Legend: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "color legend"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search in file"),
+	),
+	SearchCase: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "toggle case while searching"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	NextUncovered: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next uncovered"),
+	),
+	PrevUncovered: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "previous uncovered"),
 	),
 }
@@ -0,0 +1,108 @@
+package codeview
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// minimapWidth is the number of columns of the minimap: a space separating
+// it from the code, and the strip.
+const minimapWidth = 2
+
+// The rows of the minimap on the screen are wider than the others.
+const (
+	minimapRow        = "▐"
+	minimapVisibleRow = "█"
+)
+
+// SetMinimap shows or hides the minimap, a strip next to the code
+// summarizing the coverage of the whole file, with the lines on the screen
+// highlighted.
+func (m *Model) SetMinimap(minimap bool) {
+	if m.minimap == minimap {
+		return
+	}
+
+	m.minimap = minimap
+	m.viewport.Width = m.codeWidth()
+	m.redrawLines()
+}
+
+// codeWidth returns the number of columns available for the code, which is
+// narrower with the minimap.
+func (m *Model) codeWidth() int {
+	if m.minimap {
+		return max(0, m.width-minimapWidth)
+	}
+
+	return m.width
+}
+
+// minimapView renders the minimap into the height of the viewport. The
+// lines of the file are split evenly among its rows, and every row has the
+// color of the least covered of its lines.
+func (m *Model) minimapView() string {
+	total := len(m.lines)
+	rows := min(total, m.viewport.Height)
+	strip := make([]string, m.viewport.Height)
+	colors := m.lineNumberColors()
+	first, last := m.visibleLines()
+
+	for row := 0; row < rows; row++ {
+		start, end := row*total/rows+1, (row+1)*total/rows
+		color := lipgloss.Color(lineNumberColor)
+
+		for line := start; line <= end; line++ {
+			if c, ok := colors[line]; ok && minimapRank(c) > minimapRank(color) {
+				color = c
+			}
+		}
+
+		glyph := minimapRow
+		if start <= last && end >= first {
+			glyph = minimapVisibleRow
+		}
+
+		strip[row] = " " + lipgloss.NewStyle().Foreground(color).Render(glyph)
+	}
+
+	return strings.Join(strip, "\n")
+}
+
+// minimapRank orders the colors of the lines, so that the uncovered lines
+// of the rows of the minimap stand out.
+func minimapRank(color lipgloss.Color) int {
+	switch string(color) {
+	case styles.CurrentTheme.SecondaryColor:
+		return 3
+	case styles.CurrentTheme.PartialColor:
+		return 2
+	case styles.CurrentTheme.PrimaryColor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// visibleLines returns the first and the last lines rendered on the screen.
+// Both are 0 if there are none.
+func (m *Model) visibleLines() (int, int) {
+	first, last := 0, 0
+	top, bottom := m.viewport.YOffset, m.viewport.YOffset+m.viewport.Height
+
+	for line, row := range m.rows {
+		if row < top || row >= bottom {
+			continue
+		}
+
+		if first == 0 || line < first {
+			first = line
+		}
+
+		last = max(last, line)
+	}
+
+	return first, last
+}
@@ -0,0 +1,286 @@
+package codeview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// Escape sequences that start and stop inverting the colors of matches.
+const (
+	matchStart = "\x1b[7m"
+	matchEnd   = "\x1b[27m"
+)
+
+var searchAcceptKey = key.NewBinding(key.WithKeys("enter"))
+
+func newSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	_ = input.SetCursorMode(textinput.CursorStatic)
+
+	return input
+}
+
+// Searching reports whether the search query is being entered. All the keys
+// should be passed to the model while searching.
+func (m *Model) Searching() bool {
+	return m.searching
+}
+
+// SearchQuery returns the query that is highlighted in the code, if any.
+func (m *Model) SearchQuery() string {
+	return m.query
+}
+
+// startSearch opens the search input, keeping the previous query.
+func (m *Model) startSearch() tea.Cmd {
+	m.searching = true
+	m.searchInput.SetValue(m.query)
+	m.searchInput.CursorEnd()
+
+	return m.searchInput.Focus()
+}
+
+// updateSearch handles the keys while the query is entered. The matches are
+// highlighted as the query changes; "enter" moves to the first match below
+// the top of the screen, and "esc" clears the search.
+func (m *Model) updateSearch(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Back):
+			m.ClearSearch()
+			return *m, nil
+
+		case key.Matches(msg, searchAcceptKey):
+			m.searching = false
+			m.searchInput.Blur()
+
+			if m.query == "" {
+				return *m, nil
+			}
+
+			return *m, m.gotoMatch(1)
+
+		case key.Matches(msg, DefaultKeyMap.SearchCase):
+			m.caseSensitive = !m.caseSensitive
+			m.setQuery(m.query)
+
+			return *m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+
+	m.searchInput, cmd = m.searchInput.Update(msg)
+
+	if value := m.searchInput.Value(); value != m.query {
+		m.setQuery(value)
+	}
+
+	return *m, cmd
+}
+
+// ClearSearch closes the search input and removes the highlighting.
+func (m *Model) ClearSearch() {
+	m.searching = false
+	m.searchInput.Blur()
+	m.searchInput.SetValue("")
+	m.setQuery("")
+}
+
+// setQuery finds the lines matching the query, and highlights the matches.
+func (m *Model) setQuery(query string) {
+	m.query = query
+	m.currentMatch = -1
+	m.updateMatches()
+	m.redrawLines()
+}
+
+func (m *Model) updateMatches() {
+	m.matches = nil
+
+	if m.query == "" {
+		return
+	}
+
+	for i, line := range m.lines {
+		if len(findMatches(stripEscapes(m.replaceTabsWithSpaces(line)), m.query, m.caseSensitive)) > 0 {
+			m.matches = append(m.matches, i+1)
+		}
+	}
+}
+
+// gotoMatch moves the screen to the next match, or to the previous one for
+// negative steps. The first step starts from the top of the screen. Matches
+// that are not displayed, such as folded ones, are skipped.
+func (m *Model) gotoMatch(step int) tea.Cmd {
+	total := len(m.matches)
+	if total == 0 {
+		return m.NewStatusMessage(fmt.Sprintf("No matches for %q", m.query))
+	}
+
+	idx := m.currentMatch
+
+	if idx < 0 {
+		idx = sort.SearchInts(m.matches, m.lineAtRow(m.viewport.YOffset))
+		if step > 0 {
+			idx--
+		}
+	}
+
+	for i := 0; i < total; i++ {
+		idx = ((idx+step)%total + total) % total
+
+		if row, ok := m.rows[m.matches[idx]]; ok {
+			m.currentMatch = idx
+			m.viewport.SetYOffset(row)
+
+			return m.NewStatusMessage(fmt.Sprintf("Match %d of %d", idx+1, total))
+		}
+	}
+
+	return m.NewStatusMessage(fmt.Sprintf("No visible matches for %q", m.query))
+}
+
+// searchView renders the search input, or the highlighted query, for the
+// footer.
+func (m *Model) searchView() string {
+	caseMode := "ignore case"
+	if m.caseSensitive {
+		caseMode = "match case"
+	}
+
+	inactive := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.InactiveColor))
+
+	if m.searching {
+		hint := fmt.Sprintf(" (%s, %s to toggle)", caseMode, DefaultKeyMap.SearchCase.Help().Key)
+		return m.searchInput.View() + inactive.Render(hint)
+	}
+
+	if m.query == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("/%s: %d lines", m.query, len(m.matches))
+}
+
+// highlightQuery inverts the colors of the matches of the query in the line,
+// keeping the rest of its styles.
+func (m *Model) highlightQuery(line string) string {
+	if m.query == "" {
+		return line
+	}
+
+	matches := findMatches(stripEscapes(line), m.query, m.caseSensitive)
+	if len(matches) == 0 {
+		return line
+	}
+
+	var (
+		b       strings.Builder
+		escape  bool
+		inMatch bool
+		idx     int
+	)
+
+	for _, r := range line {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			// ansi sequences end with a letter; styles of the line can reset
+			// the inverted colors, so they are inverted again
+			escape = !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+
+			if !escape && inMatch {
+				b.WriteRune(r)
+				b.WriteString(matchStart)
+
+				continue
+			}
+		default:
+			if len(matches) > 0 && idx == matches[0][0] {
+				b.WriteString(matchStart)
+				inMatch = true
+			}
+
+			b.WriteRune(r)
+			idx++
+
+			if inMatch && idx == matches[0][1] {
+				b.WriteString(matchEnd)
+				inMatch = false
+				matches = matches[1:]
+			}
+
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// findMatches returns the ranges of runes of s that match the query, the end
+// excluded. The ranges don't overlap.
+func findMatches(s, query string, caseSensitive bool) [][2]int {
+	text, pattern := []rune(s), []rune(query)
+	if len(pattern) == 0 {
+		return nil
+	}
+
+	if !caseSensitive {
+		text, pattern = toLower(text), toLower(pattern)
+	}
+
+	var matches [][2]int
+
+	for i := 0; i+len(pattern) <= len(text); i++ {
+		if string(text[i:i+len(pattern)]) == string(pattern) {
+			matches = append(matches, [2]int{i, i + len(pattern)})
+			i += len(pattern) - 1
+		}
+	}
+
+	return matches
+}
+
+func toLower(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	return lower
+}
+
+// stripEscapes removes ansi escape sequences from the string.
+func stripEscapes(s string) string {
+	var (
+		b      strings.Builder
+		escape bool
+	)
+
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			escape = true
+		case escape:
+			escape = !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
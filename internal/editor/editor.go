@@ -0,0 +1,47 @@
+// Package editor builds commands that open files in the editor of the user.
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Fallback is the editor used when $EDITOR is not set.
+const Fallback = "vi"
+
+// editors that support "+N" argument to open a file at line N
+var lineArgEditors = map[string]bool{
+	"vi":          true,
+	"vim":         true,
+	"nvim":        true,
+	"emacs":       true,
+	"emacsclient": true,
+	"nano":        true,
+}
+
+// Command returns a command that opens the file in the editor set in $EDITOR
+// environment variable, at the provided line if the editor supports it. Zero
+// line opens the file at the beginning.
+func Command(file string, line int) *exec.Cmd {
+	args := Args(os.Getenv("EDITOR"), file, line)
+
+	return exec.Command(args[0], args[1:]...) // nolint: gosec
+}
+
+// Args builds arguments of the editor command. The editor may include its
+// own arguments, such as "emacs -nw".
+func Args(editor, file string, line int) []string {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{Fallback}
+	}
+
+	if line > 0 && lineArgEditors[filepath.Base(args[0])] {
+		args = append(args, "+"+strconv.Itoa(line))
+	}
+
+	return append(args, file)
+}
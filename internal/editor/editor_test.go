@@ -0,0 +1,27 @@
+package editor_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/editor"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		args   []string
+	}{
+		{editor: "", line: 7, args: []string{"vi", "+7", "foo.go"}},
+		{editor: "  ", line: 0, args: []string{"vi", "foo.go"}},
+		{editor: "nvim", line: 3, args: []string{"nvim", "+3", "foo.go"}},
+		{editor: "/usr/bin/vim", line: 3, args: []string{"/usr/bin/vim", "+3", "foo.go"}},
+		{editor: "emacs -nw", line: 12, args: []string{"emacs", "-nw", "+12", "foo.go"}},
+		{editor: "code --wait", line: 12, args: []string{"code", "--wait", "foo.go"}},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.args, editor.Args(test.editor, "foo.go", test.line), test.editor)
+	}
+}
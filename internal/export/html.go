@@ -0,0 +1,139 @@
+// Package export renders source code annotated with coverage information into
+// formats that can be viewed outside of the terminal.
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+const (
+	tabWidth = 4
+
+	htmlHeader = `<style>
+.gocovsh { font-family: monospace; }
+.gocovsh .covered { color: #1a7f37; }
+.gocovsh .uncovered { color: #cf222e; }
+</style>
+<pre class="gocovsh">
+`
+	htmlFooter = "</pre>\n"
+)
+
+// HTML writes the provided source code as an HTML fragment into w. Covered
+// and uncovered code blocks are highlighted according to the profile. Tabs
+// are expanded into spaces to keep the indentation consistent regardless of
+// the browser settings.
+func HTML(w io.Writer, profile *cover.Profile, src []byte) error {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.WriteString(htmlHeader); err != nil {
+		return err
+	}
+
+	boundaries := profile.Boundaries(src)
+	spanOpen := false
+	col := 0
+
+	for i := range src {
+		for len(boundaries) > 0 && boundaries[0].Offset == i {
+			b := boundaries[0]
+			boundaries = boundaries[1:]
+
+			if spanOpen {
+				_, _ = bw.WriteString("</span>")
+				spanOpen = false
+			}
+
+			if b.Start {
+				class := "uncovered"
+				if b.Count > 0 {
+					class = "covered"
+				}
+
+				fmt.Fprintf(bw, `<span class="%s">`, class)
+
+				spanOpen = true
+			}
+		}
+
+		switch c := src[i]; c {
+		case '\t':
+			spaces := tabWidth - col%tabWidth
+			_, _ = bw.WriteString(strings.Repeat(" ", spaces))
+			col += spaces
+		case '\n':
+			_ = bw.WriteByte(c)
+			col = 0
+		default:
+			template.HTMLEscape(bw, src[i:i+1])
+
+			// only count the first byte of every utf-8 sequence
+			if c&0xC0 != 0x80 {
+				col++
+			}
+		}
+	}
+
+	if spanOpen {
+		_, _ = bw.WriteString("</span>")
+	}
+
+	if _, err := bw.WriteString(htmlFooter); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// WriteFile renders source code file src as HTML into dst file.
+func WriteFile(dst, src string, profile *cover.Profile) error {
+	content, err := os.ReadFile(src) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
+	}
+
+	f, err := os.Create(dst) // nolint: gosec
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if err := HTML(f, profile, content); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+
+	return f.Close()
+}
+
+// WriteDir renders every profiled file into its own HTML file. The files are
+// created in dir, using the same relative paths as source files in codeRoot.
+func WriteDir(dir, codeRoot string, profiles []*cover.Profile) error {
+	for _, p := range profiles {
+		dst := filepath.Join(dir, p.FileName+".html")
+		src := filepath.Join(codeRoot, p.FileName)
+
+		if err := WriteFile(dst, src, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Filename returns the name of the HTML file to export the source file into,
+// when it is exported on its own.
+func Filename(sourceFilename string) string {
+	return strings.ReplaceAll(filepath.ToSlash(sourceFilename), "/", "_") + ".html"
+}
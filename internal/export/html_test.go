@@ -0,0 +1,54 @@
+package export_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/export"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+const source = "package foo\n\nfunc Foo() int {\n\tif x := 1; x < 2 {\n\t\treturn 1 // a<b\n\t}\n\n\treturn 2\n}\n"
+
+var profile = &cover.Profile{
+	FileName: "foo.go",
+	Mode:     "set",
+	Blocks: []cover.ProfileBlock{
+		{StartLine: 3, StartCol: 16, EndLine: 4, EndCol: 19, NumStmt: 1, Count: 1},
+		{StartLine: 4, StartCol: 19, EndLine: 6, EndCol: 3, NumStmt: 1, Count: 1},
+		{StartLine: 8, StartCol: 2, EndLine: 8, EndCol: 10, NumStmt: 1, Count: 0},
+	},
+}
+
+func TestHTML(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, export.HTML(buf, profile, []byte(source)))
+
+	out := buf.String()
+	require.Contains(t, out, `<span class="covered">{`)
+	require.Contains(t, out, "\n        return 1 // a&lt;b\n")
+	require.Contains(t, out, `<span class="uncovered">return 2</span>`)
+	require.NotContains(t, out, "\t")
+	require.Equal(t, bytes.Count(buf.Bytes(), []byte("<span")), bytes.Count(buf.Bytes(), []byte("</span>")))
+}
+
+func TestWriteDir(t *testing.T) {
+	src := t.TempDir()
+	dst := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(src, "foo.go"), []byte(source), 0o600))
+	require.NoError(t, export.WriteDir(dst, src, []*cover.Profile{profile}))
+
+	bs, err := os.ReadFile(filepath.Join(dst, "foo.go.html"))
+	require.NoError(t, err)
+	require.Contains(t, string(bs), `<pre class="gocovsh">`)
+
+	require.Error(t, export.WriteDir(dst, t.TempDir(), []*cover.Profile{profile}))
+}
+
+func TestFilename(t *testing.T) {
+	require.Equal(t, "internal_model_model.go.html", export.Filename("internal/model/model.go"))
+}
@@ -0,0 +1,199 @@
+// Package funccover attributes coverage profile blocks to the functions they
+// belong to, similar to "go tool cover -func".
+package funccover
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+// Func is the coverage of a single function, method or function literal.
+type Func struct {
+	// Name is the qualified name of the function: methods are prefixed with
+	// their receiver type, such as "(*T).Method", and function literals are
+	// named after the enclosing function, such as "Func.func1".
+	Name      string
+	StartLine int
+	EndLine   int
+	Covered   int64
+	Total     int64
+
+	start, end token.Position
+	literal    bool
+}
+
+// Percentage returns the percentage of covered statements of this function,
+// or 0 if it has no statements.
+func (f *Func) Percentage() float64 {
+	if f.Total == 0 {
+		return 0
+	}
+
+	return float64(f.Covered) / float64(f.Total) * 100
+}
+
+// contains reports whether the provided line and column are within the
+// function.
+func (f *Func) contains(line, col int) bool {
+	afterStart := line > f.start.Line || line == f.start.Line && col >= f.start.Column
+	beforeEnd := line < f.end.Line || line == f.end.Line && col <= f.end.Column
+
+	return afterStart && beforeEnd
+}
+
+// Analyze parses the provided Go source code and attributes the blocks of the
+// profile to the functions declared in it. Every block is attributed to the
+// innermost function it belongs to, so the statements of function literals
+// are not counted in the enclosing function. Functions are ordered by their
+// position in the file.
+func Analyze(filename string, src []byte, profile *cover.Profile) ([]*Func, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	funcs := collectFuncs(fset, file)
+
+	for _, b := range profile.Blocks {
+		var owner *Func
+
+		// functions are ordered by start, so the last match is the innermost
+		for _, f := range funcs {
+			if f.contains(b.StartLine, b.StartCol) {
+				owner = f
+			}
+		}
+
+		if owner == nil {
+			continue
+		}
+
+		owner.Total += int64(b.NumStmt)
+
+		if b.Count > 0 {
+			owner.Covered += int64(b.NumStmt)
+		}
+	}
+
+	return funcs, nil
+}
+
+// collectFuncs returns all function declarations and function literals of
+// the file, ordered by position.
+func collectFuncs(fset *token.FileSet, file *ast.File) []*Func {
+	var (
+		funcs []*Func
+
+		// owners holds the innermost function of every node that is being
+		// visited, if any
+		owners []*Func
+
+		closures    = map[*Func]int{}
+		pkgClosures = 0
+	)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node == nil {
+			owners = owners[:len(owners)-1]
+			return true
+		}
+
+		var parent *Func
+		if len(owners) > 0 {
+			parent = owners[len(owners)-1]
+		}
+
+		owner := parent
+
+		switch n := node.(type) {
+		case *ast.FuncDecl:
+			if n.Body != nil {
+				owner = newFunc(fset, n, funcDeclName(n))
+				funcs = append(funcs, owner)
+			}
+		case *ast.FuncLit:
+			var name string
+
+			switch {
+			case parent == nil:
+				pkgClosures++
+				name = fmt.Sprintf("func%d", pkgClosures)
+			case parent.literal:
+				closures[parent]++
+				name = fmt.Sprintf("%s.%d", parent.Name, closures[parent])
+			default:
+				closures[parent]++
+				name = fmt.Sprintf("%s.func%d", parent.Name, closures[parent])
+			}
+
+			owner = newFunc(fset, n, name)
+			owner.literal = true
+			funcs = append(funcs, owner)
+		}
+
+		owners = append(owners, owner)
+
+		return true
+	})
+
+	return funcs
+}
+
+func newFunc(fset *token.FileSet, node ast.Node, name string) *Func {
+	f := &Func{
+		Name:  name,
+		start: fset.Position(node.Pos()),
+		end:   fset.Position(node.End()),
+	}
+	f.StartLine, f.EndLine = f.start.Line, f.end.Line
+
+	return f
+}
+
+// SortByCoverage sorts the functions by coverage percentage, lowest first.
+// Functions with the same coverage keep their relative order.
+func SortByCoverage(funcs []*Func) {
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].Percentage() < funcs[j].Percentage()
+	})
+}
+
+// SortByCoverageDesc sorts the functions by coverage percentage, highest
+// first. Functions with the same coverage keep their relative order.
+func SortByCoverageDesc(funcs []*Func) {
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].Percentage() > funcs[j].Percentage()
+	})
+}
+
+func funcDeclName(n *ast.FuncDecl) string {
+	if n.Recv == nil || len(n.Recv.List) == 0 {
+		return n.Name.Name
+	}
+
+	return fmt.Sprintf("%s.%s", receiverName(n.Recv.List[0].Type), n.Name.Name)
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return fmt.Sprintf("(*%s)", receiverName(t.X))
+	case *ast.ParenExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return "?"
+	}
+}
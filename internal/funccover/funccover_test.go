@@ -0,0 +1,62 @@
+package funccover_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/funccover"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+const source = `package foo
+
+type T struct{}
+
+func (t *T) Foo() int {
+	f := func() int {
+		return 1
+	}
+
+	return f()
+}
+
+func Bar() {
+	return
+}
+`
+
+var profile = &cover.Profile{
+	FileName: "foo.go",
+	Mode:     "set",
+	Blocks: []cover.ProfileBlock{
+		{StartLine: 5, StartCol: 23, EndLine: 6, EndCol: 19, NumStmt: 1, Count: 1},
+		{StartLine: 6, StartCol: 19, EndLine: 8, EndCol: 3, NumStmt: 1, Count: 0},
+		{StartLine: 10, StartCol: 2, EndLine: 10, EndCol: 12, NumStmt: 1, Count: 1},
+		{StartLine: 13, StartCol: 12, EndLine: 15, EndCol: 2, NumStmt: 1, Count: 0},
+	},
+}
+
+func TestAnalyze(t *testing.T) {
+	funcs, err := funccover.Analyze("foo.go", []byte(source), profile)
+	require.NoError(t, err)
+	require.Len(t, funcs, 3)
+
+	require.Equal(t, "(*T).Foo", funcs[0].Name)
+	require.Equal(t, 5, funcs[0].StartLine)
+	require.Equal(t, int64(2), funcs[0].Covered)
+	require.Equal(t, int64(2), funcs[0].Total)
+
+	require.Equal(t, "(*T).Foo.func1", funcs[1].Name)
+	require.Equal(t, 0.0, funcs[1].Percentage())
+
+	require.Equal(t, "Bar", funcs[2].Name)
+	require.Equal(t, int64(1), funcs[2].Total)
+
+	funccover.SortByCoverage(funcs)
+	require.Equal(t, "(*T).Foo.func1", funcs[0].Name)
+	require.Equal(t, "Bar", funcs[1].Name)
+	require.Equal(t, "(*T).Foo", funcs[2].Name)
+
+	_, err = funccover.Analyze("foo.go", []byte("not go"), profile)
+	require.Error(t, err)
+}
@@ -0,0 +1,228 @@
+// Package funcview provides a bubbletea component for displaying coverage of
+// every function in a file, similar to "go tool cover -func".
+package funcview
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/orlangure/gocovsh/internal/funccover"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+const ellipsis = "…"
+
+var (
+	titleStyle = func() lipgloss.Style {
+		b := lipgloss.RoundedBorder()
+		b.Right = "├"
+		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
+	}()
+
+	rowStyle  = lipgloss.NewStyle().PaddingLeft(2)
+	helpStyle = lipgloss.NewStyle().Padding(0, 0, 1, 4)
+)
+
+// New creates a new funcview model which is rendered into the provided width
+// and height.
+func New(width, height int) Model {
+	return Model{
+		viewport: viewport.New(width, height),
+		help:     help.New(),
+		showHelp: true,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Model is the funcview model. Use New to create a new instance.
+type Model struct {
+	viewport viewport.Model
+	help     help.Model
+	width    int
+	height   int
+	title    string
+	funcs    []*funccover.Func
+	showHelp bool
+}
+
+// Update is used to update the internal model state based on the external
+// events.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(msg, DefaultKeyMap.Home) {
+			_ = m.viewport.GotoTop()
+			return m, nil
+		}
+
+		if key.Matches(msg, DefaultKeyMap.End) {
+			_ = m.viewport.GotoBottom()
+			return m, nil
+		}
+	}
+
+	vp, cmd := m.viewport.Update(msg)
+	m.viewport = vp
+
+	return m, cmd
+}
+
+// View renders the model to be displayed.
+func (m *Model) View() string {
+	sections := []string{m.headerView(), m.viewport.View()}
+
+	if helpView := m.helpView(); helpView != "" {
+		sections = append(sections, helpView)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// SetTitle sets the title of the funcview, usually the file name.
+func (m *Model) SetTitle(title string) {
+	m.title = title
+	m.recalculateSize()
+}
+
+// SetFuncs sets the functions to be displayed, in order.
+func (m *Model) SetFuncs(funcs []*funccover.Func) {
+	m.funcs = funcs
+	m.redrawFuncs()
+	m.viewport.SetYOffset(0)
+}
+
+// SetWidth sets the width of the funcview.
+func (m *Model) SetWidth(width int) {
+	m.setSize(width, m.height)
+}
+
+// SetHeight sets the height of the funcview.
+func (m *Model) SetHeight(height int) {
+	m.setSize(m.width, height)
+}
+
+// ShortHelp implements help.KeyMap interface.
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		DefaultKeyMap.Up,
+		DefaultKeyMap.Down,
+		DefaultKeyMap.Back,
+		DefaultKeyMap.Help,
+	}
+}
+
+// FullHelp implements help.KeyMap interface.
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{DefaultKeyMap.Up, DefaultKeyMap.Down, DefaultKeyMap.Home, DefaultKeyMap.End},
+		{DefaultKeyMap.Back, DefaultKeyMap.Help, DefaultKeyMap.Quit},
+	}
+}
+
+// SetShowHelp allows to hide or show the help section.
+func (m *Model) SetShowHelp(showHelp bool) {
+	m.showHelp = showHelp
+	m.setSize(m.width, m.height)
+}
+
+// SetShowFullHelp allows to view extended help section, if visible.
+func (m *Model) SetShowFullHelp(showFullHelp bool) {
+	m.help.ShowAll = showFullHelp
+	m.setSize(m.width, m.height)
+}
+
+func (m *Model) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.help.Width = width
+	m.viewport.Width = width
+	m.recalculateSize()
+	m.redrawFuncs()
+}
+
+func (m *Model) recalculateSize() {
+	height := m.height
+	height -= lipgloss.Height(m.headerView())
+	height -= lipgloss.Height(m.helpView())
+
+	if height < 1 {
+		height = 1
+	}
+
+	m.viewport.Height = height
+}
+
+func (m *Model) redrawFuncs() {
+	nameWidth := 0
+	for _, f := range m.funcs {
+		if w := lipgloss.Width(f.Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+
+	// line number and percentage columns take up to 17 characters
+	availableWidth := m.width - rowStyle.GetHorizontalPadding() - 17
+	if nameWidth > availableWidth {
+		nameWidth = availableWidth
+	}
+
+	if nameWidth < 1 {
+		nameWidth = 1
+	}
+
+	rows := make([]string, 0, len(m.funcs))
+
+	for _, f := range m.funcs {
+		name := f.Name
+		if lipgloss.Width(name) > nameWidth {
+			name = truncate.StringWithTail(name, uint(nameWidth), ellipsis)
+		}
+
+		row := fmt.Sprintf("%-*s %6d %8.2f%%", nameWidth, name, f.StartLine, f.Percentage())
+		rows = append(rows, rowStyle.Foreground(coverageColor(f)).Render(row))
+	}
+
+	m.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+func coverageColor(f *funccover.Func) lipgloss.Color {
+	switch {
+	case f.Total > 0 && f.Covered == f.Total:
+		return lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+	case f.Total > 0 && f.Covered == 0:
+		return lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+	default:
+		return lipgloss.Color(styles.CurrentTheme.InactiveColor)
+	}
+}
+
+func (m *Model) headerView() string {
+	truncatedTitle := m.title
+
+	if maxWidth := m.width - 5; maxWidth > 0 && len(m.title) > maxWidth {
+		truncatedTitle = fmt.Sprintf("%s%s", ellipsis, m.title[len(m.title)-maxWidth:])
+	}
+
+	title := titleStyle.Render(truncatedTitle)
+
+	lineWidth := m.width - lipgloss.Width(title)
+	if lineWidth < 0 {
+		lineWidth = 0
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, strings.Repeat("─", lineWidth))
+}
+
+func (m *Model) helpView() string {
+	if m.showHelp {
+		return helpStyle.Render(m.help.View(m))
+	}
+
+	return ""
+}
@@ -0,0 +1,305 @@
+// Package gitignore reports whether files are ignored by .gitignore files,
+// following git semantics: patterns of nested .gitignore files take
+// precedence over the ones of their parents, the last matching pattern wins,
+// and files can't be re-included if their parent directory is ignored.
+package gitignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const ignoreFilename = ".gitignore"
+
+// New creates a new Matcher for files relative to the provided root
+// directory. The .gitignore files are looked up from the repository root,
+// which is the closest parent of root containing .git, or root itself if
+// there is no repository.
+func New(root string) (*Matcher, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	return &Matcher{
+		root:     absRoot,
+		repoRoot: findRepoRoot(absRoot),
+		patterns: map[string][]pattern{},
+	}, nil
+}
+
+// Matcher matches files against .gitignore files. Use New to create a new
+// instance.
+type Matcher struct {
+	root     string
+	repoRoot string
+
+	// patterns are cached per directory
+	patterns map[string][]pattern
+}
+
+// Ignored reports whether the file, relative to the root, is ignored.
+func (m *Matcher) Ignored(file string) (bool, error) {
+	rel, err := filepath.Rel(m.repoRoot, filepath.Join(m.root, filepath.FromSlash(file)))
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve %s: %w", file, err)
+	}
+
+	rel = filepath.ToSlash(rel)
+	if rel == "." || strings.HasPrefix(rel, "../") {
+		return false, nil
+	}
+
+	parts := strings.Split(rel, "/")
+
+	for i := range parts {
+		last := i == len(parts)-1
+		isDir := !last || m.isDir(parts)
+
+		ignored, err := m.match(parts[:i+1], isDir)
+		if err != nil {
+			return false, err
+		}
+
+		// files can't be re-included if their parent directory is excluded
+		if ignored || last {
+			return ignored, nil
+		}
+	}
+
+	return false, nil
+}
+
+// match checks the path against the patterns of every .gitignore file in the
+// directories above it, from top to bottom.
+func (m *Matcher) match(parts []string, isDir bool) (bool, error) {
+	ignored := false
+
+	for i := 0; i < len(parts); i++ {
+		dir := filepath.Join(append([]string{m.repoRoot}, parts[:i]...)...)
+
+		patterns, err := m.loadPatterns(dir)
+		if err != nil {
+			return false, err
+		}
+
+		rel := strings.Join(parts[i:], "/")
+
+		for _, p := range patterns {
+			if p.match(rel, isDir) {
+				ignored = !p.negate
+			}
+		}
+	}
+
+	return ignored, nil
+}
+
+// isDir reports whether the path is a directory. Symbolic links are never
+// followed, so a link to a directory is a file for git.
+func (m *Matcher) isDir(parts []string) bool {
+	fi, err := os.Lstat(filepath.Join(append([]string{m.repoRoot}, parts...)...))
+	if err != nil {
+		return false
+	}
+
+	return fi.IsDir()
+}
+
+func (m *Matcher) loadPatterns(dir string) ([]pattern, error) {
+	if patterns, ok := m.patterns[dir]; ok {
+		return patterns, nil
+	}
+
+	patterns, err := readPatterns(filepath.Join(dir, ignoreFilename))
+	if err != nil {
+		return nil, err
+	}
+
+	m.patterns[dir] = patterns
+
+	return patterns, nil
+}
+
+func findRepoRoot(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Lstat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+
+		current = parent
+	}
+}
+
+// nolint: gosec
+func readPatterns(filename string) ([]pattern, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open %s: %w", filename, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	var patterns []pattern
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		if p, ok := parsePattern(scanner.Text()); ok {
+			patterns = append(patterns, p)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	return patterns, nil
+}
+
+type pattern struct {
+	re       *regexp.Regexp
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// match reports whether the path, relative to the directory of the
+// .gitignore file, matches the pattern.
+func (p pattern) match(path string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+
+	if !p.anchored {
+		path = path[strings.LastIndex(path, "/")+1:]
+	}
+
+	return p.re.MatchString(path)
+}
+
+func parsePattern(line string) (pattern, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	line = trimTrailingSpaces(line)
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return pattern{}, false
+	}
+
+	var p pattern
+
+	if strings.HasPrefix(line, "!") {
+		p.negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+
+	if line == "" {
+		return pattern{}, false
+	}
+
+	// a separator at the beginning or in the middle makes the pattern
+	// relative to the .gitignore file
+	p.anchored = strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	re, err := regexp.Compile("^" + globToRegexp(line) + "$")
+	if err != nil {
+		return pattern{}, false
+	}
+
+	p.re = re
+
+	return p, true
+}
+
+// trimTrailingSpaces removes trailing spaces, unless they are escaped with a
+// backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, "\\ ") {
+		line = line[:len(line)-1]
+	}
+
+	return line
+}
+
+// globToRegexp converts the glob into a regular expression. Wildcards don't
+// match the separator, except for "**" used as a whole path component.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+
+		switch c {
+		case '*':
+			if !strings.HasPrefix(glob[i:], "**") {
+				b.WriteString("[^/]*")
+				continue
+			}
+
+			atStart := i == 0 || glob[i-1] == '/'
+			rest := glob[i+2:]
+
+			switch {
+			case atStart && strings.HasPrefix(rest, "/"):
+				b.WriteString("(?:.*/)?")
+				i += 2
+			case atStart && rest == "":
+				b.WriteString(".*")
+				i++
+			default:
+				b.WriteString("[^/]*")
+				i++
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+
+			class := glob[i+1 : i+1+end]
+			i += end + 1
+
+			b.WriteString("[")
+
+			if strings.HasPrefix(class, "!") || strings.HasPrefix(class, "^") {
+				b.WriteString("^")
+				class = class[1:]
+			}
+
+			b.WriteString(strings.ReplaceAll(class, `\`, `\\`))
+			b.WriteString("]")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	return b.String()
+}
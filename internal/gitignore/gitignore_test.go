@@ -0,0 +1,102 @@
+package gitignore_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/gitignore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnored(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, ".git/HEAD", "")
+	writeFile(t, root, ".gitignore", `
+# generated code
+*_gen.go
+!keep_gen.go
+/build/
+out/
+vendor
+docs/**/*.go
+\#literal.go
+`)
+	writeFile(t, root, "pkg/.gitignore", "mock_*.go\n!/mock_keep.go\n")
+	writeFile(t, root, "build/main.go", "")
+	writeFile(t, root, "vendor/keep_gen.go", "")
+	writeFile(t, root, "pkg/sub/build/main.go", "")
+	writeFile(t, root, "links/target/main.go", "")
+	writeFile(t, root, "pkg/out/main.go", "")
+
+	if runtime.GOOS != "windows" {
+		require.NoError(t, os.Symlink("target", filepath.Join(root, "links", "out")))
+	}
+
+	matcher, err := gitignore.New(root)
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"main.go":                false,
+		"api_gen.go":             true,
+		"pkg/api_gen.go":         true,
+		"keep_gen.go":            false,
+		"build/main.go":          true,
+		"pkg/sub/build/main.go":  false,
+		"vendor/keep_gen.go":     true,
+		"docs/a/b/example.go":    true,
+		"docs/example.go":        true,
+		"docs/readme.md":         false,
+		"#literal.go":            true,
+		"pkg/mock_service.go":    true,
+		"pkg/mock_keep.go":       false,
+		"pkg/sub/mock_keep.go":   true,
+		"mock_service.go":        false,
+		"links/target/main.go":   false,
+		"pkg/out/main.go":        true,
+		"../outside/api_gen.go":  false,
+		"pkg/sub/build/other.go": false,
+	}
+
+	for file, expected := range tests {
+		ignored, err := matcher.Ignored(file)
+		require.NoError(t, err)
+		require.Equal(t, expected, ignored, file)
+	}
+
+	if runtime.GOOS != "windows" {
+		// "out/" only matches directories, and links are never followed
+		ignored, err := matcher.Ignored("links/out")
+		require.NoError(t, err)
+		require.False(t, ignored)
+	}
+}
+
+func TestNestedRoot(t *testing.T) {
+	repo := t.TempDir()
+
+	writeFile(t, repo, ".git/HEAD", "")
+	writeFile(t, repo, ".gitignore", "module/generated/\n")
+
+	matcher, err := gitignore.New(filepath.Join(repo, "module"))
+	require.NoError(t, err)
+
+	ignored, err := matcher.Ignored("generated/foo.go")
+	require.NoError(t, err)
+	require.True(t, ignored)
+
+	ignored, err = matcher.Ignored("foo.go")
+	require.NoError(t, err)
+	require.False(t, ignored)
+}
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+
+	filename := filepath.Join(root, filepath.FromSlash(name))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o750))
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+}
@@ -0,0 +1,61 @@
+// Package gitlog finds the last commits changing files, using git log.
+package gitlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotRepository is returned for files outside of git repositories.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Commit is the last commit changing a file.
+type Commit struct {
+	Hash   string
+	Author string
+	Date   time.Time
+}
+
+// String describes the commit in a single line, such as "a1b2c3d by Jane Doe
+// on 2022-05-01".
+func (c Commit) String() string {
+	return fmt.Sprintf("%s by %s on %s", c.Hash, c.Author, c.Date.Format("2006-01-02"))
+}
+
+// Last returns the last commit changing the file, looked up in the repository
+// of its directory. Files that were never committed have no commit, and ok is
+// false.
+func Last(file string) (commit Commit, ok bool, err error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command(
+		"git", "-C", filepath.Dir(file), "log", "-1", "--format=%h%x00%an%x00%aI", "--", filepath.Base(file),
+	)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "not a git repository") {
+			return Commit{}, false, ErrNotRepository
+		}
+
+		return Commit{}, false, fmt.Errorf("failed to run git log: %w", err)
+	}
+
+	fields := strings.Split(strings.TrimSpace(string(out)), "\x00")
+	if len(fields) != 3 {
+		return Commit{}, false, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, fields[2])
+	if err != nil {
+		return Commit{}, false, fmt.Errorf("invalid commit date %q: %w", fields[2], err)
+	}
+
+	return Commit{Hash: fields[0], Author: fields[1], Date: date}, true, nil
+}
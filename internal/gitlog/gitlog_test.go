@@ -0,0 +1,53 @@
+package gitlog_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/gitlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLast(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE=2022-05-01T10:00:00Z",
+			"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE=2022-05-01T10:00:00Z",
+		)
+
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	git("init", "-q")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "pkg"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "a.go"), []byte("package pkg\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pkg", "b.go"), []byte("package pkg\n"), 0o600))
+	git("add", "pkg/a.go")
+	git("commit", "-q", "-m", "add a.go")
+
+	commit, ok, err := gitlog.Last(filepath.Join(dir, "pkg", "a.go"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "Jane Doe", commit.Author)
+	require.Len(t, commit.Hash, 7)
+	require.Equal(t, commit.Hash+" by Jane Doe on 2022-05-01", commit.String())
+
+	_, ok, err = gitlog.Last(filepath.Join(dir, "pkg", "b.go"))
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestLastOutsideRepository(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+	_, _, err := gitlog.Last(filepath.Join(dir, "a.go"))
+	require.ErrorIs(t, err, gitlog.ErrNotRepository)
+}
@@ -0,0 +1,93 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageBars(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	load := func(t *testing.T, mt *modelTest, width int) {
+		t.Helper()
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(width, 20)
+		mt.sendProfilesMsg(initMsg)
+	}
+
+	t.Run("colored by threshold", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+			threshold:       60,
+			bars:            true,
+		}
+
+		load(t, mt, 60)
+
+		g.Assert(t, "bars_threshold", []byte(mt.m.View()))
+	})
+
+	t.Run("adapt to width", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+			bars:            true,
+		}
+
+		load(t, mt, 120)
+
+		g.Assert(t, "bars_wide", []byte(mt.m.View()))
+	})
+
+	t.Run("ascii", func(t *testing.T) {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		t.Cleanup(func() { lipgloss.SetColorProfile(termenv.TrueColor) })
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+			noColor:         true,
+			bars:            true,
+		}
+
+		load(t, mt, 60)
+
+		view := mt.m.View()
+		require.Contains(t, view, "main.go        100.00% #######")
+		require.Contains(t, view, "pkg/a/util.go    0.00% .......")
+		require.Contains(t, view, "pkg/a/a.go      50.00% ####...")
+	})
+
+	t.Run("toggle", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+		}
+
+		load(t, mt, 60)
+		require.NotContains(t, mt.m.View(), "█")
+
+		mm, cmd := mt.sendLetterKey('B')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "█")
+		require.Contains(t, mm.View(), "Bars shown")
+
+		mm, cmd = mt.sendLetterKey('B')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.NotContains(t, mm.View(), "█")
+		require.Contains(t, mm.View(), "Bars hidden")
+	})
+}
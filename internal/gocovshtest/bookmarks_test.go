@@ -0,0 +1,61 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBookmarks(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	t.Run("none", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('\'')
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "No bookmarks")
+	})
+
+	t.Run("toggle", func(t *testing.T) {
+		// main.go and pkg/a/util.go
+		mt.sendLetterKey('M')
+		mt.sendLetterKey('j')
+		mt.sendLetterKey('j')
+		mm, cmd := mt.sendLetterKey('M')
+		require.NotNil(t, cmd)
+		require.Equal(t, []string{"main.go", "pkg/a/util.go"}, mt.m.Bookmarks())
+
+		g.Assert(t, "bookmarks", []byte(mm.View()))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		mt.sendLetterKey('\'')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		require.Equal(t, "main.go", mt.m.OpenedFile())
+		mt.sendFileContentsMsg(cmd())
+
+		// the code view opens the next one
+		_, cmd = mt.sendLetterKey('\'')
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/a/util.go", mt.m.OpenedFile())
+		mt.sendFileContentsMsg(cmd())
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		mt.sendLetterKey('M')
+		require.Equal(t, []string{"main.go"}, mt.m.Bookmarks())
+	})
+}
@@ -0,0 +1,97 @@
+package gocovshtest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBrowser struct {
+	url string
+	err error
+}
+
+func (b *fakeBrowser) Open(url string) error {
+	if b.err != nil {
+		return b.err
+	}
+
+	b.url = url
+
+	return nil
+}
+
+func TestOpenInBrowser(t *testing.T) {
+	const module = "github.com/orlangure/gocovsh/internal/model/testdata/general"
+
+	start := func(t *testing.T, mt *modelTest) {
+		t.Helper()
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(200, 20)
+		mt.sendProfilesMsg(initMsg)
+	}
+
+	t.Run("package docs from the list", func(t *testing.T) {
+		b := &fakeBrowser{}
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general", browser: b}
+		start(t, mt)
+
+		_, cmd := mt.sendLetterKey('w')
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.m.Update(cmd())
+		require.Equal(t, "https://pkg.go.dev/"+module, b.url)
+		require.Contains(t, mm.View(), "Opened https://pkg.go.dev/")
+	})
+
+	t.Run("repository from the code view", func(t *testing.T) {
+		b := &fakeBrowser{}
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			browser:         b,
+			repoURLTemplate: "https://{module}/blob/main/{path}#L{line}",
+		}
+		start(t, mt)
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+
+		_, cmd = mt.sendLetterKey('w')
+		require.NotNil(t, cmd)
+		mt.m.Update(cmd())
+		require.Equal(t, "https://"+module+"/blob/main/covered.go#L1", b.url)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		b := &fakeBrowser{err: errors.New("xdg-open not found")}
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general", browser: b}
+		start(t, mt)
+
+		_, cmd := mt.sendLetterKey('w')
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.m.Update(cmd())
+		require.Contains(t, mm.View(), "Browser failed: xdg-open not found")
+	})
+
+	t.Run("disabled without module", func(t *testing.T) {
+		b := &fakeBrowser{}
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "coverage.xml",
+			format:          parser.FormatCobertura,
+			codeRoot:        "testdata/cobertura",
+			browser:         b,
+		}
+		start(t, mt)
+
+		mt.sendLetterKey('w')
+		require.Empty(t, b.url)
+	})
+}
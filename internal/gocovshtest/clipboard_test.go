@@ -0,0 +1,180 @@
+package gocovshtest
+
+import (
+	"errors"
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClipboard struct {
+	text string
+	err  error
+}
+
+func (c *fakeClipboard) WriteAll(text string) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	c.text = text
+
+	return nil
+}
+
+func TestCopyPath(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "clipboard")))
+	cb := &fakeClipboard{}
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		clipboard:       cb,
+	}
+
+	t.Run("initial setup", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.m.Update(cmd())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+		require.Equal(t, "covered.go", cb.text)
+
+		g.Assert(t, "copy_path_list", []byte(mm.View()))
+	})
+
+	t.Run("code", func(t *testing.T) {
+		_, _ = mt.sendLetterKey('j')
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendLetterKey('y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.m.Update(cmd())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+		require.Equal(t, "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go", cb.text)
+
+		g.Assert(t, "copy_path_code", []byte(mm.View()))
+	})
+
+	t.Run("clipboard not available", func(t *testing.T) {
+		cb.err = errors.New("no clipboard")
+
+		mm, cmd := mt.sendLetterKey('y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.m.Update(cmd())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "copy_path_clipboard_not_available", []byte(mm.View()))
+	})
+}
+
+func TestCopyUncoveredLines(t *testing.T) {
+	cb := &fakeClipboard{}
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/generated",
+		clipboard:       cb,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	t.Run("list", func(t *testing.T) {
+		// api/api.pb.go
+		mm, cmd := mt.sendLetterKey('Y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.m.Update(cmd())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+		require.Equal(t, "3-6,8", cb.text)
+		require.Contains(t, mm.View(), "Copied 3-6,8")
+	})
+
+	t.Run("code", func(t *testing.T) {
+		// service.go
+		_, _ = mt.sendLetterKey('j')
+		_, _ = mt.sendLetterKey('j')
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mt.sendFileContentsMsg(cmd())
+
+		mm, cmd = mt.sendLetterKey('Y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mt.m.Update(cmd())
+		require.Equal(t, "8", cb.text)
+	})
+
+	t.Run("clipboard not available", func(t *testing.T) {
+		cb.err = errors.New("no clipboard")
+
+		mm, cmd := mt.sendLetterKey('Y')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, _ = mt.m.Update(cmd())
+		require.Contains(t, mm.View(), "Clipboard is not available: 8")
+	})
+}
+
+func TestCopyUncoveredLinesFullyCovered(t *testing.T) {
+	cb := &fakeClipboard{}
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		clipboard:       cb,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	// covered.go
+	mm, cmd := mt.sendLetterKey('Y')
+	require.NotNil(t, mm)
+	require.NotNil(t, cmd) // status message timeout
+	require.Contains(t, mm.View(), "No uncovered lines")
+	require.Empty(t, cb.text)
+}
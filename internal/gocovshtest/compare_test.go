@@ -0,0 +1,56 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareProfiles(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "compare")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		compareFilename: "compare/before.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 30)
+	mm, _ := mt.sendProfilesMsg(initMsg)
+
+	// the file missing from the compared profile is new
+	view := mm.View()
+	require.Contains(t, view, "+25.00%")
+	require.Contains(t, view, "new")
+	g.Assert(t, "compare_list", []byte(view))
+
+	t.Run("changed lines", func(t *testing.T) {
+		mt.sendLetterKey('j')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.sendFileContentsMsg(cmd())
+		require.Contains(t, mm.View(), "+3/-3 lines vs before.cover")
+		g.Assert(t, "compare_code", []byte(mm.View()))
+	})
+
+	t.Run("switch profiles", func(t *testing.T) {
+		_, cmd := mt.m.Update(tea.KeyMsg{Type: tea.KeyTab})
+		require.NotNil(t, cmd)
+
+		_, cmd = mt.sendProfilesMsg(cmd())
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.sendEscKey()
+		view := mm.View()
+		require.Contains(t, view, "-25.00%")
+		require.Contains(t, view, "vs profile.cover")
+		require.NotContains(t, view, "covered.go")
+	})
+}
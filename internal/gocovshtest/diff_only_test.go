@@ -0,0 +1,129 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/codeview"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffOnly(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "diff-only")))
+
+	partial := "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"covered.go", partial},
+		filteredLines:   map[string][]int{partial: {8, 12}},
+		diffOnly:        true,
+	}
+
+	t.Run("list", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(80, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "diff_only_list", []byte(mm.View()))
+	})
+
+	t.Run("changed file", func(t *testing.T) {
+		mt.sendLetterKey('j')
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		fileMsg := cmd()
+		require.NotNil(t, fileMsg)
+
+		mm, cmd = mt.sendFileContentsMsg(fileMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "diff_only_changed_file", []byte(mm.View()))
+	})
+}
+
+func TestDiffView(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "diff-only")))
+
+	partial := "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"covered.go", partial},
+		filteredLines:   map[string][]int{partial: {8, 12}},
+		diffHunks: map[string][]codeview.DiffHunk{
+			partial: {
+				{
+					Header: "@@ -7,3 +7,3 @@ func Covered() string {",
+					Lines: []codeview.DiffLine{
+						{Kind: codeview.DiffUnchanged, Number: 7, Text: "func NotCovered() string {"},
+						{Kind: codeview.DiffRemoved, Number: 8, Text: "\treturn \"uncovered\""},
+						{Kind: codeview.DiffAdded, Number: 8, Text: "\treturn \"not covered\""},
+						{Kind: codeview.DiffUnchanged, Number: 9, Text: "}"},
+					},
+				},
+				{
+					Header: "@@ -12,1 +12,1 @@ func NotCovered() string {",
+					Lines: []codeview.DiffLine{
+						{Kind: codeview.DiffRemoved, Number: 12, Text: "\tswitch false {"},
+						{Kind: codeview.DiffAdded, Number: 12, Text: "\tswitch true {"},
+					},
+				},
+			},
+		},
+		diffView: true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	t.Run("hunks", func(t *testing.T) {
+		mt.sendLetterKey('j')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.sendFileContentsMsg(cmd())
+		require.Contains(t, mm.View(), "@@ -12,1 +12,1 @@")
+		require.NotContains(t, mm.View(), "SecondCovered")
+
+		g.Assert(t, "diff_view_hunks", []byte(mm.View()))
+	})
+
+	t.Run("whole file", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('D')
+		require.Nil(t, cmd)
+		require.NotContains(t, mm.View(), "@@")
+		require.Contains(t, mm.View(), "SecondCovered")
+	})
+
+	t.Run("file without diff", func(t *testing.T) {
+		mt.sendEscKey()
+		mt.sendLetterKey('k')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+
+		mm, cmd := mt.sendLetterKey('D')
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "No diff of this file")
+	})
+}
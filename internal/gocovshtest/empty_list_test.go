@@ -0,0 +1,35 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestedFilesNotInProfile(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "empty-list")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"missing.go"},
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 20)
+
+	mm, cmd := mt.sendProfilesMsg(initMsg)
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	view := mm.View()
+	require.Contains(t, view, "None of the requested files are in the coverage profile.")
+	g.Assert(t, "requested_files_not_in_profile", []byte(view))
+
+	mm, cmd = mt.sendLetterKey('q')
+	require.NotNil(t, mm)
+	require.NotNil(t, cmd)
+}
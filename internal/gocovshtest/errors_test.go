@@ -133,11 +133,7 @@ func TestErrorFlows(t *testing.T) {
 		mm, cmd = mt.sendErrorMsg(errMsg)
 		require.NotNil(t, mm)
 		require.Nil(t, cmd)
-		if isWindows() {
-			g.Assert(t, "error_flows_missing_source_file_windows", []byte(mm.View()))
-		} else {
-			g.Assert(t, "error_flows_missing_source_file", []byte(mm.View()))
-		}
+		g.Assert(t, "error_flows_missing_source_file", []byte(mm.View()))
 	})
 
 	t.Run("invalid go.mod", func(t *testing.T) {
@@ -158,4 +154,23 @@ func TestErrorFlows(t *testing.T) {
 		require.Nil(t, cmd)
 		g.Assert(t, "error_flows_invalid_go.mod", []byte(mm.View()))
 	})
+	t.Run("no package matches", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+			packages:        []string{"./pkg/...", "./cmd/..."},
+		}
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendErrorMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+		g.Assert(t, "error_flows_no_package_matches", []byte(mm.View()))
+	})
 }
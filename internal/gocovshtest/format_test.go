@@ -0,0 +1,157 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCobertura(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "cobertura")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "coverage.xml",
+		format:          parser.FormatCobertura,
+		codeRoot:        "testdata/cobertura",
+	}
+
+	t.Run("list", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "cobertura_list", []byte(mm.View()))
+	})
+
+	t.Run("code", func(t *testing.T) {
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "cobertura_code", []byte(mm.View()))
+	})
+}
+
+func TestLCOV(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "lcov")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "lcov.info",
+		format:          parser.FormatLCOV,
+		codeRoot:        "testdata/lcov",
+	}
+
+	t.Run("list", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "lcov_list", []byte(mm.View()))
+	})
+
+	t.Run("code", func(t *testing.T) {
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "lcov_code", []byte(mm.View()))
+	})
+
+	t.Run("no functions", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('f')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "lcov_no_functions", []byte(mm.View()))
+	})
+
+	t.Run("missing source file", func(t *testing.T) {
+		mm, cmd := mt.sendEscKey()
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendLetterKey('j')
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		// the error is displayed in the code view instead of the error view
+		mm, cmd = mt.sendErrorMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "lcov_missing_source_file", []byte(mm.View()))
+	})
+
+	t.Run("back to list", func(t *testing.T) {
+		mm, cmd := mt.sendEscKey()
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "lcov_back_to_list", []byte(mm.View()))
+	})
+}
+
+func TestLCOVModulePaths(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "lcov")))
+
+	// the names are relative to the nearest go.mod, which is the one of this
+	// repository
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "module.info",
+		format:          parser.FormatLCOV,
+		codeRoot:        "testdata/lcov",
+	}
+
+	initCmd := mt.init()
+	initMsg := initCmd()
+
+	mm, cmd := mt.sendWindowSizeMsg(60, 20)
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	mm, cmd = mt.sendProfilesMsg(initMsg)
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	mm, cmd = mt.sendEnterKey()
+	require.NotNil(t, mm)
+	require.NotNil(t, cmd)
+
+	mm, cmd = mt.sendFileContentsMsg(cmd())
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	g.Assert(t, "lcov_module_paths", []byte(mm.View()))
+}
@@ -0,0 +1,41 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGapsOnly(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 16)
+	mt.sendProfilesMsg(initMsg)
+	mt.sendLetterKey('j')
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+	mt.sendFileContentsMsg(cmd())
+
+	full := mt.m.View()
+
+	mm, cmd := mt.sendLetterKey('X')
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+	require.Contains(t, mm.View(), "3 uncovered lines left")
+	require.NotContains(t, mm.View(), "package general")
+
+	g.Assert(t, "gaps_only", []byte(mm.View()))
+
+	mm, _ = mt.sendLetterKey('X')
+	require.Equal(t, full, mm.View())
+}
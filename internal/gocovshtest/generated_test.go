@@ -0,0 +1,60 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedFiles(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "generated")))
+
+	t.Run("muted by default", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/generated",
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		g.Assert(t, "generated_muted", []byte(mt.m.View()))
+	})
+
+	t.Run("hidden", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/generated",
+			hideGenerated:   true,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		view := mt.m.View()
+		require.NotContains(t, view, "api.pb.go")
+		require.NotContains(t, view, "mocks/service.go")
+		g.Assert(t, "generated_hidden", []byte(view))
+
+		t.Run("toggle", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('x')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd)
+
+			view := mm.View()
+			require.Contains(t, view, "Generated shown")
+			require.Contains(t, view, "api.pb.go")
+			require.Contains(t, view, "mocks/service.go")
+
+			mm, _ = mt.sendLetterKey('x')
+			require.Contains(t, mm.View(), "Generated hidden")
+			require.NotContains(t, mm.View(), "api.pb.go")
+		})
+	})
+}
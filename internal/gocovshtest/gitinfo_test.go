@@ -0,0 +1,96 @@
+package gocovshtest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitInfo(t *testing.T) {
+	// a copy of the sources, committed by a known author
+	copySources := func(t *testing.T) string {
+		t.Helper()
+
+		dir := t.TempDir()
+
+		for _, name := range []string{"go.mod", "profile.cover", "covered.go"} {
+			bs, err := os.ReadFile(filepath.Join("testdata", "general", name))
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), bs, 0o600))
+		}
+
+		return dir
+	}
+
+	openCovered := func(mt *modelTest) {
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(100, 20)
+		mt.sendProfilesMsg(initMsg)
+	}
+
+	t.Run("committed", func(t *testing.T) {
+		dir := copySources(t)
+
+		for _, args := range [][]string{{"init", "-q"}, {"add", "."}, {"commit", "-q", "-m", "initial"}} {
+			cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+			cmd.Env = append(os.Environ(),
+				"GIT_AUTHOR_NAME=Jane Doe", "GIT_AUTHOR_EMAIL=jane@example.com", "GIT_AUTHOR_DATE=2022-05-01T10:00:00Z",
+				"GIT_COMMITTER_NAME=Jane Doe", "GIT_COMMITTER_EMAIL=jane@example.com", "GIT_COMMITTER_DATE=2022-05-01T10:00:00Z",
+			)
+
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+		}
+
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: dir, gitInfo: true}
+		openCovered(mt)
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		for _, c := range batchCmds(t, cmd) {
+			mt.m.Update(c())
+		}
+
+		require.Contains(t, mt.m.View(), "by Jane Doe on 2022-05-01")
+
+		// the commit is remembered when the file is opened again
+		mt.sendEscKey()
+
+		_, cmd = mt.sendEnterKey()
+		msg := cmd()
+		require.NotEqual(t, "tea.batchMsg", fmt.Sprintf("%T", msg))
+
+		mm, _ := mt.sendFileContentsMsg(msg)
+		require.Contains(t, mm.View(), "by Jane Doe on 2022-05-01")
+	})
+
+	t.Run("outside of repository", func(t *testing.T) {
+		dir := copySources(t)
+		t.Setenv("GIT_CEILING_DIRECTORIES", filepath.Dir(dir))
+
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: dir, gitInfo: true}
+		openCovered(mt)
+
+		_, cmd := mt.sendEnterKey()
+		for _, c := range batchCmds(t, cmd) {
+			mt.m.Update(c())
+		}
+
+		require.NotContains(t, mt.m.View(), "last commit")
+
+		// git is no longer run
+		mt.sendEscKey()
+
+		_, cmd = mt.sendEnterKey()
+		msg := cmd()
+		require.NotEqual(t, "tea.batchMsg", fmt.Sprintf("%T", msg))
+
+		mm, _ := mt.sendFileContentsMsg(msg)
+		require.Contains(t, mm.View(), "covered.go")
+	})
+}
@@ -2,6 +2,7 @@ package gocovshtest
 
 import (
 	"path"
+	"regexp"
 	"testing"
 
 	"github.com/sebdah/goldie/v2"
@@ -72,6 +73,30 @@ func testHappyFlow(t *testing.T, prefix string, requestedFiles []string, filtere
 		g.Assert(t, "happy_flow_first_file", []byte(mm.View()))
 	})
 
+	t.Run("scroll right", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('l')
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "happy_flow_scroll_right", []byte(mm.View()))
+	})
+
+	t.Run("scroll reset", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('0')
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "happy_flow_first_file", []byte(mm.View()))
+	})
+
+	t.Run("no uncovered blocks", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('n')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "happy_flow_no_uncovered_blocks", []byte(mm.View()))
+	})
+
 	t.Run("back to list", func(t *testing.T) {
 		mm, cmd := mt.sendEscKey()
 		require.NotNil(t, mm)
@@ -91,7 +116,7 @@ func testHappyFlow(t *testing.T, prefix string, requestedFiles []string, filtere
 	t.Run("select second file", func(t *testing.T) {
 		mm, cmd := mt.sendLetterKey('j')
 		require.NotNil(t, mm)
-		require.NotNil(t, cmd) // command is not nit but irrelevant
+		require.Nil(t, cmd)
 
 		g.Assert(t, "happy_flow_select_second_file", []byte(mm.View()))
 	})
@@ -129,6 +154,117 @@ func testHappyFlow(t *testing.T, prefix string, requestedFiles []string, filtere
 			g.Assert(t, "happy_flow_codeview_navigation_top", []byte(mm.View()))
 		})
 
+		t.Run("next uncovered", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('n')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd) // status message timeout
+
+			g.Assert(t, "happy_flow_codeview_navigation_next_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("previous uncovered", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('N')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd) // status message timeout
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("functions", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('f')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd)
+
+			// analyze functions using the returned command
+			funcsMsg := cmd()
+			require.NotNil(t, funcsMsg)
+
+			mm, cmd = mt.sendFileContentsMsg(funcsMsg)
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_functions", []byte(mm.View()))
+		})
+
+		t.Run("functions back", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('f')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("profile blocks", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('R')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_blocks", []byte(mm.View()))
+		})
+
+		t.Run("profile blocks back", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('R')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("open in editor", func(t *testing.T) {
+			// the command suspends the program, so it is not executed
+			mm, cmd := mt.sendLetterKey('o')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd)
+		})
+
+		t.Run("hide line numbers", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('L')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_hide_line_numbers", []byte(mm.View()))
+		})
+
+		t.Run("show line numbers", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('L')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("uncovered only", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('U')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_uncovered_only", []byte(mm.View()))
+		})
+
+		t.Run("full file", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('U')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
+		t.Run("gaps only", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('X')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_gaps_only", []byte(mm.View()))
+		})
+
+		t.Run("all lines", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('X')
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			g.Assert(t, "happy_flow_codeview_navigation_previous_uncovered", []byte(mm.View()))
+		})
+
 		t.Run("back", func(t *testing.T) {
 			mm, cmd := mt.sendEscKey()
 			require.NotNil(t, mm)
@@ -138,29 +274,95 @@ func testHappyFlow(t *testing.T, prefix string, requestedFiles []string, filtere
 		})
 	})
 
-	t.Run("toggle help", func(t *testing.T) {
-		t.Run("full", func(t *testing.T) {
+	t.Run("help overlay", func(t *testing.T) {
+		t.Run("open", func(t *testing.T) {
 			mm, cmd := mt.sendLetterKey('?')
 			require.NotNil(t, mm)
 			require.Nil(t, cmd)
 
-			g.Assert(t, "happy_flow_toggle_help_full", []byte(mm.View()))
+			g.Assert(t, "happy_flow_help_overlay_open", []byte(mm.View()))
 		})
 
-		t.Run("none", func(t *testing.T) {
-			mm, cmd := mt.sendLetterKey('?')
+		t.Run("ignore other keys", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('j')
 			require.NotNil(t, mm)
 			require.Nil(t, cmd)
 
-			g.Assert(t, "happy_flow_toggle_help_none", []byte(mm.View()))
+			g.Assert(t, "happy_flow_help_overlay_open", []byte(mm.View()))
 		})
 
-		t.Run("short", func(t *testing.T) {
-			mm, cmd := mt.sendLetterKey('?')
+		t.Run("close", func(t *testing.T) {
+			mm, cmd := mt.sendEscKey()
 			require.NotNil(t, mm)
 			require.Nil(t, cmd)
 
-			g.Assert(t, "happy_flow_toggle_help_short", []byte(mm.View()))
+			g.Assert(t, "happy_flow_codeview_navigation_back", []byte(mm.View()))
 		})
 	})
 }
+
+func TestFileFilter(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "file-filter")))
+
+	for name, pattern := range map[string]string{
+		"matching":   "^partial",
+		"no_matches": "^internal/service/",
+		"substring":  "covered",
+	} {
+		pattern := pattern
+
+		t.Run(name, func(t *testing.T) {
+			mt := &modelTest{
+				T:               t,
+				profileFilename: "profile.cover",
+				codeRoot:        "testdata/general",
+				fileFilter:      regexp.MustCompile(pattern),
+			}
+
+			initCmd := mt.init()
+			initMsg := initCmd()
+
+			mm, cmd := mt.sendWindowSizeMsg(60, 20)
+			require.NotNil(t, mm)
+			require.Nil(t, cmd)
+
+			mm, _ = mt.sendProfilesMsg(initMsg)
+			require.NotNil(t, mm)
+
+			g.Assert(t, "file_filter_"+name, []byte(mm.View()))
+		})
+	}
+}
+
+func TestGlobs(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "globs")))
+
+	for name, test := range map[string]struct {
+		include []string
+		exclude []string
+	}{
+		"include":            {include: []string{"**/partial_*.go"}},
+		"exclude":            {exclude: []string{"covered.go"}},
+		"exclude_precedence": {include: []string{"*.go"}, exclude: []string{"partial_*", "covered.go"}},
+	} {
+		test := test
+
+		t.Run(name, func(t *testing.T) {
+			mt := &modelTest{
+				T:               t,
+				profileFilename: "profile.cover",
+				codeRoot:        "testdata/general",
+				includeGlobs:    test.include,
+				excludeGlobs:    test.exclude,
+			}
+
+			initMsg := mt.init()()
+			mt.sendWindowSizeMsg(60, 20)
+
+			mm, _ := mt.sendProfilesMsg(initMsg)
+			require.NotNil(t, mm)
+
+			g.Assert(t, "globs_"+name, []byte(mm.View()))
+		})
+	}
+}
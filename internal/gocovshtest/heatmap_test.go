@@ -0,0 +1,68 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatmap(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "heatmap")))
+
+	openFile := func(t *testing.T, profileFilename string) *modelTest {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: profileFilename,
+			codeRoot:        "testdata/general",
+			requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+			heatmap:         true,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(80, 30)
+		mt.sendProfilesMsg(initMsg)
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mt.sendFileContentsMsg(cmd())
+
+		return mt
+	}
+
+	t.Run("count mode", func(t *testing.T) {
+		mt := openFile(t, "heatmap/count.cover")
+
+		g.Assert(t, "heatmap_count", []byte(mt.m.View()))
+
+		t.Run("toggle off", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('H')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd)
+
+			mm, cmd = mt.sendFileContentsMsg(cmd())
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd) // status message timeout
+
+			g.Assert(t, "heatmap_toggle_off", []byte(mm.View()))
+		})
+	})
+
+	t.Run("set mode", func(t *testing.T) {
+		mt := openFile(t, "profile.cover")
+
+		g.Assert(t, "heatmap_set_mode", []byte(mt.m.View()))
+
+		t.Run("toggle is not available", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('H')
+			require.NotNil(t, mm)
+			require.NotNil(t, cmd) // status message timeout
+
+			g.Assert(t, "heatmap_set_mode_toggle", []byte(mm.View()))
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package gocovshtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoredFile(t *testing.T) {
+	load := func(t *testing.T, ignoreComments bool) *modelTest {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/ignored",
+			ignoreComments:  ignoreComments,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		return mt
+	}
+
+	t.Run("listed without ignore comments", func(t *testing.T) {
+		mt := load(t, false)
+		require.Contains(t, mt.m.View(), "ignored.go")
+	})
+
+	t.Run("dropped when all its blocks are ignored", func(t *testing.T) {
+		mt := load(t, true)
+
+		view := mt.m.View()
+		require.NotContains(t, view, "ignored.go")
+		require.Contains(t, view, "1 item")
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+		require.Equal(t, "kept.go", mt.m.OpenedFile())
+		require.NotContains(t, mt.m.View(), "error")
+	})
+}
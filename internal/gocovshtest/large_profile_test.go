@@ -0,0 +1,109 @@
+package gocovshtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLargeProfile writes a go.mod file and a synthetic coverage profile with
+// the provided number of files into a temporary directory, and returns it.
+func writeLargeProfile(tb testing.TB, files int) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+
+	var profile strings.Builder
+
+	profile.WriteString("mode: set\n")
+
+	for i := 0; i < files; i++ {
+		for block := 0; block < 20; block++ {
+			fmt.Fprintf(
+				&profile, "example.com/large/pkg%d/file%d.go:%d.2,%d.10 %d %d\n",
+				i/50, i, block*5+1, block*5+4, block%3+1, (i+block)%2,
+			)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/large\n"), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile.String()), 0o600); err != nil {
+		tb.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestLargeProfile(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        writeLargeProfile(t, 5000),
+	}
+
+	initMsg := mt.init()()
+
+	mm, _ := mt.sendProfilesMsg(initMsg)
+	require.NotNil(t, mm)
+
+	mm, _ = mt.sendWindowSizeMsg(80, 40)
+	require.NotNil(t, mm)
+
+	// the dots of hundreds of pages don't fit, so the page number is shown
+	require.Contains(t, mm.View(), "1/173")
+	require.NotContains(t, mm.View(), "••")
+
+	mm, _ = mt.sendLetterKey('G')
+	require.NotNil(t, mm)
+	require.Contains(t, mm.View(), "173/173")
+}
+
+func BenchmarkLargeProfile(b *testing.B) {
+	dir := writeLargeProfile(b, 5000)
+
+	for _, mode := range []model.SortMode{model.SortByPath, model.SortByCoverageAsc} {
+		mode := mode
+
+		b.Run(string(mode), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				m := model.New(
+					model.WithProfileFilename("profile.cover"),
+					model.WithCodeRoot(dir),
+					model.WithSortMode(mode),
+				)
+
+				m.Update(batchCmds(b, m.Init())[0]())
+				m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+				_ = m.View()
+			}
+		})
+	}
+
+	b.Run("view", func(b *testing.B) {
+		m := model.New(
+			model.WithProfileFilename("profile.cover"),
+			model.WithCodeRoot(dir),
+		)
+
+		m.Update(batchCmds(b, m.Init())[0]())
+		m.Update(tea.WindowSizeMsg{Width: 80, Height: 40})
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			_ = m.View()
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package gocovshtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToggleLegend(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(100, 30)
+	mt.sendProfilesMsg(initMsg)
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+
+	mt.sendFileContentsMsg(cmd())
+	require.Contains(t, mt.m.View(), "■ covered")
+
+	t.Run("hide", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('c')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+		require.Contains(t, mm.View(), "Legend hidden")
+
+		// the legend stays hidden in other files
+		mt.sendEscKey()
+
+		_, cmd = mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mm, _ = mt.sendFileContentsMsg(cmd())
+		require.NotContains(t, mm.View(), "■ covered")
+	})
+
+	t.Run("show", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('c')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "Legend shown")
+	})
+}
@@ -0,0 +1,60 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoading(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	mt.init()
+	cmds := batchCmds(t, mt.m.Init())
+	require.Len(t, cmds, 2)
+
+	mt.sendWindowSizeMsg(60, 20)
+
+	t.Run("spinner while loading", func(t *testing.T) {
+		g.Assert(t, "loading", []byte(mt.m.View()))
+
+		mm, cmd := mt.m.Update(cmds[1]())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // next frame
+	})
+
+	t.Run("spinner stops once loaded", func(t *testing.T) {
+		mt.sendProfilesMsg(cmds[0]())
+		require.NotContains(t, mt.m.View(), "Loading")
+
+		mm, cmd := mt.m.Update(cmds[1]())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+}
+
+func TestLoadingError(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "missing.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	require.Contains(t, mt.m.View(), "Loading coverage profile...")
+
+	mt.sendErrorMsg(initMsg)
+
+	view := mt.m.View()
+	require.NotContains(t, view, "Loading")
+	require.Contains(t, view, "missing.cover")
+}
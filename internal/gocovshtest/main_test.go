@@ -2,13 +2,19 @@ package gocovshtest
 
 import (
 	"os"
+	"reflect"
+	"regexp"
 	"testing"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/termenv"
+	"github.com/orlangure/gocovsh/internal/codeview"
 	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/orlangure/gocovsh/internal/parser"
 	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/internal/targets"
 	"github.com/stretchr/testify/require"
 )
 
@@ -24,25 +30,114 @@ type modelTest struct {
 	*testing.T
 
 	profileFilename string
+	compareFilename string
+	format          parser.Format
 	codeRoot        string
+	sourceRoot      string
 	requestedFiles  []string
 	filteredLines   map[string][]int
+	diffHunks       map[string][]codeview.DiffHunk
+	fileFilter      *regexp.Regexp
+	includeGlobs    []string
+	excludeGlobs    []string
+	packages        []string
+	hideGenerated   bool
+	staleCheck      bool
+	sourceCheck     bool
+	gitInfo         bool
+	minimap         bool
+	ignoreComments  bool
+	bars            bool
+	selectedFile    string
+	selectedMatch   string
+	openSelected    bool
+	openFirst       bool
+	testCommand     string
+	threshold       float64
+	targets         targets.Targets
+	tree            bool
+	diffOnly        bool
+	diffView        bool
+	syntax          bool
+	heatmap         bool
+	noColor         bool
+	patterns        bool
+	watch           time.Duration
+	noConfirmQuit   bool
+	inline          bool
+	typeAhead       time.Duration
+	clipboard       model.Clipboard
+	browser         model.Browser
+	repoURLTemplate string
 
 	m *model.Model
 }
 
 func (t *modelTest) init() tea.Cmd {
-	t.m = model.New(
+	opts := []model.Option{
 		model.WithProfileFilename(t.profileFilename),
+		model.WithCompareProfile(t.compareFilename),
 		model.WithCodeRoot(t.codeRoot),
+		model.WithSourceRoot(t.sourceRoot),
 		model.WithRequestedFiles(t.requestedFiles),
 		model.WithFilteredLines(t.filteredLines),
-	)
+		model.WithFileFilter(t.fileFilter),
+		model.WithIncludeGlobs(t.includeGlobs),
+		model.WithExcludeGlobs(t.excludeGlobs),
+		model.WithPackages(t.packages),
+		model.WithHideGenerated(t.hideGenerated),
+		model.WithStaleCheck(t.staleCheck),
+		model.WithSourceCheck(t.sourceCheck),
+		model.WithGitInfo(t.gitInfo),
+		model.WithMinimap(t.minimap),
+		model.WithIgnoreComments(t.ignoreComments),
+		model.WithBars(t.bars),
+		model.WithSelectedFile(t.selectedFile),
+		model.WithSelectedMatch(t.selectedMatch),
+		model.WithOpenSelected(t.openSelected),
+		model.WithTestCommand(t.testCommand),
+		model.WithThreshold(t.threshold),
+		model.WithTargets(t.targets),
+		model.WithTree(t.tree),
+		model.WithDiffOnly(t.diffOnly),
+		model.WithDiffHunks(t.diffHunks),
+		model.WithDiffView(t.diffView),
+		model.WithSyntax(t.syntax),
+		model.WithHeatmap(t.heatmap),
+		model.WithColor(!t.noColor),
+		model.WithPatterns(t.patterns),
+		model.WithWatch(t.watch),
+		model.WithConfirmQuit(!t.noConfirmQuit),
+		model.WithInline(t.inline),
+	}
+
+	if t.format != "" {
+		opts = append(opts, model.WithFormat(t.format))
+	}
+
+	if t.openFirst {
+		opts = append(opts, model.WithOpenIndex(0))
+	}
+
+	if t.typeAhead != 0 {
+		opts = append(opts, model.WithTypeAheadTimeout(t.typeAhead))
+	}
+
+	if t.clipboard != nil {
+		opts = append(opts, model.WithClipboard(t.clipboard))
+	}
+
+	if t.browser != nil {
+		opts = append(opts, model.WithBrowser(t.browser), model.WithRepoURLTemplate(t.repoURLTemplate))
+	}
+
+	t.m = model.New(opts...)
 
 	initCmd := t.m.Init()
 	require.NotNil(t, initCmd)
 
-	return initCmd
+	// the profile is loaded first, along with the spinner
+	return batchCmds(t.T, initCmd)[0]
 }
 
 // nolint: unparam
@@ -72,9 +167,37 @@ func (t *modelTest) sendEscKey() (tea.Model, tea.Cmd) {
 	return t.m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyEsc}))
 }
 
+func (t *modelTest) sendBackspaceKey() (tea.Model, tea.Cmd) {
+	return t.m.Update(tea.KeyMsg(tea.Key{Type: tea.KeyBackspace}))
+}
+
+func (t *modelTest) sendSpaceKey() (tea.Model, tea.Cmd) {
+	return t.m.Update(tea.KeyMsg(tea.Key{Type: tea.KeySpace, Runes: []rune{' '}}))
+}
+
 func (t *modelTest) sendLetterKey(letter rune) (tea.Model, tea.Cmd) {
 	return t.m.Update(tea.KeyMsg(tea.Key{
 		Type:  tea.KeyRunes,
 		Runes: []rune{letter},
 	}))
 }
+
+func (t *modelTest) sendMouseMsg(eventType tea.MouseEventType, x, y int) (tea.Model, tea.Cmd) {
+	return t.m.Update(tea.MouseMsg(tea.MouseEvent{Type: eventType, X: x, Y: y}))
+}
+
+// batchCmds returns the commands of a tea.Batch, so that they can be run
+// one by one.
+func batchCmds(tb testing.TB, cmd tea.Cmd) []tea.Cmd {
+	tb.Helper()
+
+	msg := reflect.ValueOf(cmd())
+	require.Equal(tb, reflect.Slice, msg.Kind())
+
+	cmds := make([]tea.Cmd, msg.Len())
+	for i := range cmds {
+		cmds[i] = msg.Index(i).Convert(reflect.TypeOf(tea.Cmd(nil))).Interface().(tea.Cmd)
+	}
+
+	return cmds
+}
@@ -0,0 +1,42 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinimap(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		minimap:         true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 16)
+	mt.sendProfilesMsg(initMsg)
+	mt.sendLetterKey('j')
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+	mt.sendFileContentsMsg(cmd())
+
+	require.Contains(t, mt.m.View(), "█")
+
+	g.Assert(t, "minimap", []byte(mt.m.View()))
+
+	// the minimap is toggled, and the code takes its place
+	mm, cmd := mt.sendLetterKey('|')
+	require.Nil(t, cmd)
+	require.NotContains(t, mm.View(), "█")
+	require.NotContains(t, mm.View(), "▐")
+
+	mm, _ = mt.sendLetterKey('|')
+	require.Contains(t, mm.View(), "█")
+}
@@ -0,0 +1,37 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceCheck(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "lcov")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "lcov.info",
+		format:          parser.FormatLCOV,
+		codeRoot:        "testdata/lcov",
+		sourceCheck:     true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+
+	// the missing file is marked before it is opened
+	mm, _ := mt.sendProfilesMsg(initMsg)
+	g.Assert(t, "lcov_source_check", []byte(mm.View()))
+
+	mt.sendLetterKey('j')
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+
+	mm, _ = mt.sendErrorMsg(cmd())
+	require.Contains(t, mm.View(), "Source file not found")
+}
@@ -0,0 +1,78 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMouse(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "mouse")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	t.Run("initial setup", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+
+	t.Run("click outside of items", func(t *testing.T) {
+		mm, cmd := mt.sendMouseMsg(tea.MouseLeft, 10, 1)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendMouseMsg(tea.MouseLeft, 10, 10)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+
+	t.Run("wheel in list", func(t *testing.T) {
+		mm, cmd := mt.sendMouseMsg(tea.MouseWheelDown, 10, 10)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "mouse_wheel_in_list", []byte(mm.View()))
+
+		mm, cmd = mt.sendMouseMsg(tea.MouseWheelUp, 10, 10)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+
+	t.Run("click second file", func(t *testing.T) {
+		mm, cmd := mt.sendMouseMsg(tea.MouseLeft, 10, 5)
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		// load file from the returned command
+		fileMsg := cmd()
+		require.NotNil(t, fileMsg)
+
+		mm, cmd = mt.sendFileContentsMsg(fileMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "mouse_click_second_file", []byte(mm.View()))
+	})
+
+	t.Run("wheel in code", func(t *testing.T) {
+		mm, _ := mt.sendMouseMsg(tea.MouseWheelDown, 10, 10)
+		require.NotNil(t, mm)
+
+		g.Assert(t, "mouse_wheel_in_code", []byte(mm.View()))
+	})
+}
@@ -0,0 +1,89 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoColor(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.TrueColor) })
+
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "no-color")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		threshold:       80,
+		noColor:         true,
+	}
+
+	t.Run("list", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "no_color_list", []byte(mm.View()))
+	})
+
+	t.Run("code", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('j')
+		require.NotNil(t, mm)
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		fileMsg := cmd()
+		require.NotNil(t, fileMsg)
+
+		mm, cmd = mt.sendFileContentsMsg(fileMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "no_color_code", []byte(mm.View()))
+	})
+}
+
+func TestNoColorCustomGlyphs(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.TrueColor) })
+
+	require.NoError(t, styles.CurrentTheme.Customize(styles.Customization{CoveredGlyph: "✓", UncoveredGlyph: "✗"}))
+	t.Cleanup(styles.SetTheme)
+
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "no-color")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+		noColor:         true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 30)
+	mt.sendProfilesMsg(initMsg)
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+
+	mm, _ := mt.sendFileContentsMsg(cmd())
+
+	g.Assert(t, "no_color_custom_glyphs", []byte(mm.View()))
+}
@@ -0,0 +1,70 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackageFilter(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	t.Run("from the list", func(t *testing.T) {
+		mt.sendLetterKey('j')
+
+		mm, cmd := mt.sendLetterKey('P')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		view := mm.View()
+		require.Contains(t, view, "pkg/a/a.go")
+		require.Contains(t, view, "pkg/a/util.go")
+		require.NotContains(t, view, "main.go")
+		require.NotContains(t, view, "pkg/b/b.go")
+		require.Contains(t, view, "pkg/a: 2 of 4")
+		require.Contains(t, view, "package: pkg/a")
+
+		g.Assert(t, "package_filter", []byte(view))
+	})
+
+	t.Run("press again to clear", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('P')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		view := mm.View()
+		require.Contains(t, view, "main.go")
+		require.Contains(t, view, "pkg/b/b.go")
+		require.NotContains(t, view, "package:")
+	})
+
+	t.Run("from the code view", func(t *testing.T) {
+		mt.sendLetterKey('G')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+		require.Equal(t, "pkg/b/b.go", mt.m.OpenedFile())
+
+		mm, cmd := mt.sendLetterKey('P')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		view := mm.View()
+		require.Contains(t, view, "pkg/b: 1 of 4")
+		require.Contains(t, view, "pkg/b/b.go")
+		require.NotContains(t, view, "pkg/a/a.go")
+	})
+}
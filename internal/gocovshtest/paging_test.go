@@ -0,0 +1,58 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeviewPaging(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "paging")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 16)
+	mt.sendProfilesMsg(initMsg)
+
+	mt.sendLetterKey('j')
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+
+	mt.sendFileContentsMsg(cmd())
+
+	top := mt.m.View()
+
+	t.Run("page down", func(t *testing.T) {
+		mm, _ := mt.sendSpaceKey()
+		require.NotNil(t, mm)
+		require.NotEqual(t, top, mm.View())
+
+		g.Assert(t, "page_down", []byte(mm.View()))
+	})
+
+	t.Run("page up", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('b')
+		require.NotNil(t, mm)
+		require.Equal(t, top, mm.View())
+	})
+
+	t.Run("half page down and up", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('d')
+		require.NotNil(t, mm)
+		require.NotEqual(t, top, mm.View())
+
+		g.Assert(t, "half_page_down", []byte(mm.View()))
+
+		mm, _ = mt.sendLetterKey('u')
+		require.NotNil(t, mm)
+		require.Equal(t, top, mm.View())
+	})
+}
@@ -0,0 +1,56 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartiallyCoveredLines(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "partial")))
+
+	openFile := func(t *testing.T, noColor bool) *modelTest {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/partial",
+			noColor:         noColor,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(80, 16)
+		mt.sendProfilesMsg(initMsg)
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mt.sendFileContentsMsg(cmd())
+
+		return mt
+	}
+
+	t.Run("colors", func(t *testing.T) {
+		mt := openFile(t, false)
+
+		g.Assert(t, "partial_colors", []byte(mt.m.View()))
+	})
+
+	t.Run("markers", func(t *testing.T) {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		t.Cleanup(func() { lipgloss.SetColorProfile(termenv.TrueColor) })
+
+		mt := openFile(t, true)
+
+		view := mt.m.View()
+		require.Contains(t, view, "~     if n < 0 { return -1 }")
+		require.Contains(t, view, "+     return 1")
+
+		g.Assert(t, "partial_markers", []byte(view))
+	})
+}
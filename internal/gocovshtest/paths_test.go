@@ -0,0 +1,87 @@
+package gocovshtest
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTogglePaths(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	// pkg/a/a.go
+	mt.sendLetterKey('j')
+
+	t.Run("full paths", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('p')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		g.Assert(t, "paths_full", []byte(mm.View()))
+	})
+
+	t.Run("relative paths", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('p')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		g.Assert(t, "paths_relative", []byte(mm.View()))
+	})
+
+	t.Run("selection is kept", func(t *testing.T) {
+		mt.sendLetterKey('p')
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/a/a.go", mt.m.OpenedFile())
+	})
+}
+
+func TestTogglePathsCollisions(t *testing.T) {
+	dir := t.TempDir()
+
+	// the same file appears both with its import path and with its absolute
+	// path, so both are relative to the module root
+	absPath := filepath.ToSlash(filepath.Join(dir, "pkg", "a.go"))
+	profile := fmt.Sprintf("mode: set\nexample.com/tree/pkg/a.go:3.15,5.2 1 1\n%s:3.15,5.2 1 0\n", absPath)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/tree\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile), 0o600))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        dir,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(200, 20)
+	mm, _ := mt.sendProfilesMsg(initMsg)
+
+	// the shortest suffixes of the full paths that tell the files apart
+	view := mm.View()
+	require.Contains(t, view, " tree/pkg/a.go ")
+	require.Contains(t, view, " "+filepath.Base(dir)+"/pkg/a.go ")
+
+	mm, _ = mt.sendLetterKey('p')
+
+	view = mm.View()
+	require.Contains(t, view, " example.com/tree/pkg/a.go ")
+	require.Contains(t, view, " "+absPath+" ")
+}
@@ -0,0 +1,54 @@
+package gocovshtest
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatterns(t *testing.T) {
+	openFile := func(t *testing.T, noColor bool) string {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+			noColor:         noColor,
+			patterns:        true,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(80, 30)
+		mt.sendProfilesMsg(initMsg)
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.sendFileContentsMsg(cmd())
+
+		return mm.View()
+	}
+
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "patterns")))
+
+	t.Run("colors", func(t *testing.T) {
+		g.Assert(t, "patterns_code", []byte(openFile(t, false)))
+	})
+
+	// the patterns take the place of the markers
+	t.Run("no color", func(t *testing.T) {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		t.Cleanup(func() { lipgloss.SetColorProfile(termenv.TrueColor) })
+
+		view := openFile(t, true)
+		g.Assert(t, "patterns_no_color_code", []byte(view))
+		require.False(t, strings.Contains(view, "+ "), "markers are not displayed along with the patterns")
+	})
+}
@@ -0,0 +1,113 @@
+package gocovshtest
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmQuit(t *testing.T) {
+	start := func(t *testing.T, mt *modelTest) {
+		t.Helper()
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+	}
+
+	isQuit := func(cmd tea.Cmd) bool {
+		return cmd != nil && cmd() == tea.Quit()
+	}
+
+	t.Run("without watch", func(t *testing.T) {
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general"}
+		start(t, mt)
+
+		_, cmd := mt.sendLetterKey('q')
+		require.True(t, isQuit(cmd))
+	})
+
+	t.Run("with watch", func(t *testing.T) {
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general", watch: time.Second}
+		start(t, mt)
+
+		mm, cmd := mt.sendLetterKey('q')
+		require.False(t, isQuit(cmd))
+		require.Contains(t, mm.View(), "Stop watching profile.cover and quit? (y/n)")
+
+		t.Run("cancel", func(t *testing.T) {
+			mm, cmd := mt.sendLetterKey('n')
+			require.False(t, isQuit(cmd))
+			require.NotContains(t, mm.View(), "Stop watching")
+			require.Contains(t, mm.View(), "Available files:")
+		})
+
+		t.Run("other keys are ignored", func(t *testing.T) {
+			mt.sendLetterKey('q')
+
+			mm, cmd := mt.sendLetterKey('j')
+			require.Nil(t, cmd)
+			require.Contains(t, mm.View(), "Stop watching")
+		})
+
+		t.Run("confirm", func(t *testing.T) {
+			_, cmd := mt.sendLetterKey('y')
+			require.True(t, isQuit(cmd))
+		})
+	})
+
+	t.Run("ctrl+c quits right away", func(t *testing.T) {
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general", watch: time.Second}
+		start(t, mt)
+
+		_, cmd := mt.m.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		require.True(t, isQuit(cmd))
+	})
+
+	t.Run("confirmation disabled", func(t *testing.T) {
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			watch:           time.Second,
+			noConfirmQuit:   true,
+		}
+		start(t, mt)
+
+		_, cmd := mt.sendLetterKey('q')
+		require.True(t, isQuit(cmd))
+	})
+}
+
+func TestInlineQuit(t *testing.T) {
+	start := func(t *testing.T, mt *modelTest) {
+		t.Helper()
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+	}
+
+	t.Run("alt screen", func(t *testing.T) {
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general"}
+		start(t, mt)
+
+		mm, cmd := mt.sendLetterKey('q')
+		require.NotNil(t, cmd)
+		require.Equal(t, tea.Quit(), cmd())
+		require.Contains(t, mm.View(), "covered.go")
+	})
+
+	t.Run("inline", func(t *testing.T) {
+		mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: "testdata/general", inline: true}
+		start(t, mt)
+		require.Contains(t, mt.m.View(), "covered.go")
+
+		mm, cmd := mt.sendLetterKey('q')
+		require.NotNil(t, cmd)
+		require.Equal(t, tea.Quit(), cmd())
+		require.Empty(t, mm.View())
+	})
+}
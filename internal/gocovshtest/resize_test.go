@@ -0,0 +1,72 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResize(t *testing.T) {
+	t.Run("list", func(t *testing.T) {
+		g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/tree",
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		// pkg/a/util.go
+		mt.sendLetterKey('j')
+		mt.sendLetterKey('j')
+
+		for _, size := range [][2]int{{30, 8}, {100, 30}, {40, 10}} {
+			mt.sendWindowSizeMsg(size[0], size[1])
+		}
+
+		g.Assert(t, "resize_list", []byte(mt.m.View()))
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/a/util.go", mt.m.OpenedFile())
+	})
+
+	t.Run("code", func(t *testing.T) {
+		g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "paging")))
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(80, 16)
+		mt.sendProfilesMsg(initMsg)
+		mt.sendLetterKey('j')
+
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+
+		// the top stays at the top
+		mt.sendWindowSizeMsg(60, 12)
+		require.Contains(t, mt.m.View(), "package general")
+
+		// and the bottom at the bottom
+		mt.sendLetterKey('G')
+
+		for _, size := range [][2]int{{100, 14}, {50, 10}, {70, 15}} {
+			mt.sendWindowSizeMsg(size[0], size[1])
+			require.Contains(t, mt.m.View(), "type useless struct{}")
+		}
+
+		g.Assert(t, "resize_bottom", []byte(mt.m.View()))
+	})
+}
@@ -0,0 +1,77 @@
+package gocovshtest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTests(t *testing.T) {
+	setup := func(t *testing.T, testCommand string) *modelTest {
+		t.Helper()
+
+		dir := t.TempDir()
+
+		files := map[string]string{
+			"go.mod":        "module example.com/tests\n",
+			"profile.cover": "mode: set\nexample.com/tests/a.go:3.15,5.2 1 0\n",
+			"new.cover":     "mode: set\nexample.com/tests/a.go:3.15,5.2 1 1\n",
+		}
+
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        dir,
+			testCommand:     testCommand,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mm, _ := mt.sendProfilesMsg(initMsg)
+		require.Contains(t, mm.View(), "Total: 0.00%")
+
+		return mt
+	}
+
+	// runTests presses the key and runs the test command, which is the last
+	// command of the batch; the other one clears the status message
+	runTests := func(t *testing.T, mt *modelTest) (tea.Model, tea.Cmd) {
+		t.Helper()
+
+		mm, cmd := mt.sendLetterKey('r')
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "Running ")
+
+		cmds := batchCmds(t, cmd)
+
+		return mt.m.Update(cmds[len(cmds)-1]())
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mt := setup(t, "cp new.cover profile.cover")
+
+		// the profile is reloaded once the tests pass
+		_, cmd := runTests(t, mt)
+		require.NotNil(t, cmd)
+
+		mm, _ := mt.sendProfilesMsg(cmd())
+		require.Contains(t, mm.View(), "Total: 100.00%")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		mt := setup(t, "cp missing.cover profile.cover")
+
+		mm, _ := runTests(t, mt)
+
+		view := mm.View()
+		require.Contains(t, view, "Tests failed")
+		require.Contains(t, view, "Total: 0.00%")
+	})
+}
@@ -0,0 +1,107 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "search")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+	}
+
+	t.Run("open file", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(80, 30)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+	})
+
+	t.Run("type query", func(t *testing.T) {
+		// keys bound to actions, such as "q", are a part of the query
+		mm, _ := mt.sendLetterKey('/')
+		require.NotNil(t, mm)
+
+		for _, r := range "seq" {
+			mm, _ = mt.sendLetterKey(r)
+			require.NotNil(t, mm)
+		}
+
+		g.Assert(t, "search_type_query", []byte(mm.View()))
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "search_no_matches", []byte(mm.View()))
+	})
+
+	t.Run("matches", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('/')
+		require.NotNil(t, mm)
+
+		for i := 0; i < len("seq"); i++ {
+			mm, _ = mt.sendBackspaceKey()
+			require.NotNil(t, mm)
+		}
+
+		for _, r := range "covered" {
+			mm, _ = mt.sendLetterKey(r)
+			require.NotNil(t, mm)
+		}
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "search_matches", []byte(mm.View()))
+	})
+
+	t.Run("next match", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('n')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "search_next_match", []byte(mm.View()))
+	})
+
+	t.Run("clear", func(t *testing.T) {
+		mm, cmd := mt.sendEscKey()
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "search_clear", []byte(mm.View()))
+	})
+
+	t.Run("back", func(t *testing.T) {
+		mm, cmd := mt.sendEscKey()
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		require.NotContains(t, mm.View(), "func Covered")
+	})
+}
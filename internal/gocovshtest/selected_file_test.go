@@ -0,0 +1,183 @@
+package gocovshtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectedFile(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	openSelected := func(t *testing.T, selectedFile string, profilesFirst bool) string {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			selectedFile:    selectedFile,
+		}
+
+		initMsg := mt.init()()
+
+		// the profile can be loaded before the size of the window is known
+		if profilesFirst {
+			mt.sendProfilesMsg(initMsg)
+			mt.sendWindowSizeMsg(60, 20)
+		} else {
+			mt.sendWindowSizeMsg(60, 20)
+			mt.sendProfilesMsg(initMsg)
+		}
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		return mt.m.OpenedFile()
+	}
+
+	t.Run("selected", func(t *testing.T) {
+		require.Equal(t, longName, openSelected(t, longName, false))
+	})
+
+	t.Run("selected before resize", func(t *testing.T) {
+		require.Equal(t, longName, openSelected(t, longName, true))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		require.Equal(t, "covered.go", openSelected(t, "removed.go", false))
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		require.Equal(t, "covered.go", openSelected(t, "", false))
+	})
+}
+
+func TestSelectedMatch(t *testing.T) {
+	selectMatch := func(t *testing.T, match string) *modelTest {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			selectedFile:    "covered.go",
+			selectedMatch:   match,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(100, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		// the file is only selected
+		require.Empty(t, mt.m.OpenedFile())
+
+		return mt
+	}
+
+	t.Run("matched", func(t *testing.T) {
+		mt := selectMatch(t, "long_name")
+		mt.sendEnterKey()
+		require.Equal(t, "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go", mt.m.OpenedFile())
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		mt := selectMatch(t, "removed")
+		require.Contains(t, mt.m.View(), `No files matching "removed"`)
+
+		mt.sendEnterKey()
+		require.Equal(t, "covered.go", mt.m.OpenedFile())
+	})
+}
+
+func TestOpenSelected(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	openSelected := func(t *testing.T, selectedFile string, profilesFirst bool) string {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        "testdata/general",
+			requestedFiles:  []string{"covered.go", longName},
+			selectedFile:    selectedFile,
+			openSelected:    true,
+		}
+
+		initMsg := mt.init()()
+
+		if profilesFirst {
+			mt.sendProfilesMsg(initMsg)
+			mt.sendWindowSizeMsg(60, 20)
+		} else {
+			mt.sendWindowSizeMsg(60, 20)
+			mt.sendProfilesMsg(initMsg)
+		}
+
+		return mt.m.OpenedFile()
+	}
+
+	t.Run("opened", func(t *testing.T) {
+		require.Equal(t, longName, openSelected(t, longName, false))
+	})
+
+	t.Run("opened before resize", func(t *testing.T) {
+		require.Equal(t, longName, openSelected(t, longName, true))
+	})
+
+	// only the list is shown if the file is not in it
+	t.Run("missing file", func(t *testing.T) {
+		require.Empty(t, openSelected(t, "removed.go", false))
+	})
+}
+
+func TestOpenFirst(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+		tree:            true,
+		openFirst:       true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+
+	// directories of the tree are skipped
+	_, cmd := mt.sendProfilesMsg(initMsg)
+	require.NotNil(t, cmd)
+	require.Equal(t, "pkg/a/a.go", mt.m.OpenedFile())
+
+	for _, c := range batchCmds(t, cmd) {
+		if c != nil {
+			mt.m.Update(c())
+		}
+	}
+
+	require.Contains(t, mt.m.View(), "package a")
+
+	// back goes to the list as usual
+	mm, _ := mt.sendEscKey()
+	require.Contains(t, mm.View(), "Available files")
+}
+
+func TestOpenedPath(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	// nothing is selected until a file is opened
+	require.Empty(t, mt.m.OpenedPath())
+
+	mt.sendEnterKey()
+	mt.sendEscKey()
+	require.Equal(t, "testdata/general/covered.go", mt.m.OpenedPath())
+}
@@ -0,0 +1,74 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCycleSortMode(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	// pkg/a/a.go
+	mt.sendLetterKey('j')
+
+	t.Run("by name", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('S')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Equal(t, model.SortByName, mt.m.SortMode())
+
+		g.Assert(t, "sort_by_name", []byte(mm.View()))
+	})
+
+	t.Run("by path", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('S')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Equal(t, model.SortByPath, mt.m.SortMode())
+
+		g.Assert(t, "sort_by_path", []byte(mm.View()))
+	})
+
+	t.Run("selection is kept", func(t *testing.T) {
+		mt.sendLetterKey('S')
+		require.Equal(t, model.SortByCoverageAsc, mt.m.SortMode())
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/a/a.go", mt.m.OpenedFile())
+	})
+
+	t.Run("by uncovered", func(t *testing.T) {
+		mt.sendEscKey()
+
+		// coverage-desc, lines, and uncovered
+		for i := 0; i < 3; i++ {
+			mt.sendLetterKey('S')
+		}
+
+		require.Equal(t, model.SortByUncovered, mt.m.SortMode())
+
+		g.Assert(t, "sort_by_uncovered", []byte(mt.m.View()))
+	})
+
+	t.Run("wraps around", func(t *testing.T) {
+		mt.sendLetterKey('S')
+
+		require.Equal(t, model.SortByName, mt.m.SortMode())
+	})
+}
@@ -0,0 +1,63 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceRoot(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "root")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/root",
+		sourceRoot:      "testdata/general",
+	}
+
+	initCmd := mt.init()
+	initMsg := initCmd()
+
+	mm, cmd := mt.sendWindowSizeMsg(60, 20)
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	mm, cmd = mt.sendProfilesMsg(initMsg)
+	require.NotNil(t, mm)
+	require.Nil(t, cmd)
+
+	t.Run("found under source root", func(t *testing.T) {
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "source_root_found", []byte(mm.View()))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mm, cmd := mt.sendEscKey()
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendLetterKey('j')
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendErrorMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "source_root_not_found", []byte(mm.View()))
+	})
+}
@@ -0,0 +1,87 @@
+package gocovshtest
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleFiles(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "generated")))
+
+	// the modification times of the fixtures depend on the checkout, so they
+	// are copied with known ones
+	root := t.TempDir()
+	profileTime := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for name, modTime := range map[string]time.Time{
+		"go.mod":           profileTime.Add(-time.Hour),
+		"profile.cover":    profileTime,
+		"service.go":       profileTime.Add(time.Hour),
+		"api/api.pb.go":    profileTime.Add(-time.Hour),
+		"mocks/service.go": profileTime,
+	} {
+		bs, err := os.ReadFile(filepath.Join("testdata", "generated", name))
+		require.NoError(t, err)
+
+		dst := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+		require.NoError(t, os.WriteFile(dst, bs, 0o600))
+		require.NoError(t, os.Chtimes(dst, modTime, modTime))
+	}
+
+	load := func(t *testing.T, staleCheck bool) *modelTest {
+		t.Helper()
+
+		mt := &modelTest{
+			T:               t,
+			profileFilename: "profile.cover",
+			codeRoot:        root,
+			staleCheck:      staleCheck,
+		}
+
+		initMsg := mt.init()()
+		mt.sendWindowSizeMsg(60, 20)
+		mt.sendProfilesMsg(initMsg)
+
+		return mt
+	}
+
+	t.Run("marked in the list", func(t *testing.T) {
+		mt := load(t, true)
+
+		g.Assert(t, "stale_list", []byte(mt.m.View()))
+
+		t.Run("warning in the viewer", func(t *testing.T) {
+			mt.sendLetterKey('G')
+
+			_, cmd := mt.sendEnterKey()
+			require.NotNil(t, cmd)
+			mt.sendFileContentsMsg(cmd())
+			require.Equal(t, "service.go", mt.m.OpenedFile())
+
+			g.Assert(t, "stale_viewer", []byte(mt.m.View()))
+		})
+
+		t.Run("no warning for other files", func(t *testing.T) {
+			mt.sendEscKey()
+			mt.sendLetterKey('g')
+
+			_, cmd := mt.sendEnterKey()
+			require.NotNil(t, cmd)
+			mt.sendFileContentsMsg(cmd())
+			require.NotEqual(t, "service.go", mt.m.OpenedFile())
+			require.NotContains(t, mt.m.View(), "Modified after the coverage profile")
+		})
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mt := load(t, false)
+		require.NotContains(t, mt.m.View(), "stale")
+	})
+}
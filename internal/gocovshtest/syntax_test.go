@@ -0,0 +1,56 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntax(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "syntax")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+		syntax:          true,
+	}
+
+	t.Run("highlighted", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(80, 30)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "syntax_highlighted", []byte(mm.View()))
+	})
+
+	t.Run("toggle off", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('s')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		mm, cmd = mt.sendFileContentsMsg(cmd())
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd) // status message timeout
+
+		g.Assert(t, "syntax_toggle_off", []byte(mm.View()))
+	})
+}
@@ -0,0 +1,38 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/targets"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverageTargets(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	tt, err := targets.New(map[string]float64{
+		"pkg/**":     20,
+		"pkg/a/*.go": 40,
+	})
+	require.NoError(t, err)
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+		threshold:       90,
+		targets:         tt,
+		tree:            true,
+		noColor:         true,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	// pkg/a is above the target of pkg/**, but pkg/a/util.go is below the
+	// one of pkg/a/*.go; main.go is only held to the threshold
+	g.Assert(t, "targets_tree", []byte(mt.m.View()))
+}
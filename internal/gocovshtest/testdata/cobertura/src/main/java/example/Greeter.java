@@ -0,0 +1,11 @@
+package example;
+
+public class Greeter {
+    public String greet(String name) {
+        if (name == null) {
+            return "Hello, stranger";
+        }
+
+        return "Hello, " + name;
+    }
+}
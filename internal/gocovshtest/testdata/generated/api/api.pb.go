@@ -0,0 +1,13 @@
+package api
+
+func (x *Request) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+
+	return ""
+}
+
+type Request struct {
+	Name string
+}
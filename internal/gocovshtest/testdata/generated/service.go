@@ -0,0 +1,9 @@
+package generated
+
+func Serve(ok bool) string {
+	if ok {
+		return "ok"
+	}
+
+	return "not ok"
+}
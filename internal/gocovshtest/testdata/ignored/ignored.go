@@ -0,0 +1,8 @@
+package ignored
+
+//gocovsh:ignore-start
+func Ignored() string {
+	return "ignored"
+}
+
+//gocovsh:ignore-end
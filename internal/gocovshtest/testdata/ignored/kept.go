@@ -0,0 +1,5 @@
+package ignored
+
+func Kept() string {
+	return "kept"
+}
@@ -0,0 +1,6 @@
+package partial
+
+func Sign(n int) int {
+	if n < 0 { return -1 }
+	return 1
+}
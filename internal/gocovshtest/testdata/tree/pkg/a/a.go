@@ -0,0 +1,9 @@
+package a
+
+func Covered() {
+	println("covered")
+}
+
+func Uncovered() {
+	println("uncovered")
+}
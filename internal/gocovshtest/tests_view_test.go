@@ -0,0 +1,54 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestsView(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "tests")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/general",
+		requestedFiles:  []string{"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"},
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 14)
+	mt.sendProfilesMsg(initMsg)
+
+	_, cmd := mt.sendEnterKey()
+	require.NotNil(t, cmd)
+	mt.sendFileContentsMsg(cmd())
+
+	// SecondCovered is at the top of the screen
+	for i := 0; i < 10; i++ {
+		mt.sendLetterKey('j')
+	}
+
+	_, cmd = mt.sendLetterKey('T')
+	require.NotNil(t, cmd)
+
+	mm, _ := mt.sendFileContentsMsg(cmd())
+	view := mm.View()
+	require.Contains(t, view, "3 tests, 1 of them call SecondCovered")
+	g.Assert(t, "tests_view", []byte(view))
+
+	t.Run("move cursor", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('j')
+		require.Contains(t, mm.View(), "> TestFull")
+
+		mm, _ = mt.sendLetterKey('G')
+		require.Contains(t, mm.View(), "> TestCovered")
+	})
+
+	t.Run("back", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('T')
+		require.Contains(t, mm.View(), "statements covered")
+	})
+}
@@ -0,0 +1,88 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+		tree:            true,
+	}
+
+	t.Run("initial setup", func(t *testing.T) {
+		initCmd := mt.init()
+		initMsg := initCmd()
+
+		mm, cmd := mt.sendWindowSizeMsg(60, 20)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		mm, cmd = mt.sendProfilesMsg(initMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "tree_initial_setup", []byte(mm.View()))
+	})
+
+	t.Run("collapse", func(t *testing.T) {
+		// pkg/a
+		mt.sendLetterKey('j')
+
+		mm, _ := mt.sendEnterKey()
+		require.NotNil(t, mm)
+
+		g.Assert(t, "tree_collapse", []byte(mm.View()))
+	})
+
+	t.Run("skip collapsed children", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('j')
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "tree_skip_collapsed_children", []byte(mm.View()))
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		mt.sendLetterKey('k')
+
+		mm, _ := mt.sendSpaceKey()
+		require.NotNil(t, mm)
+
+		g.Assert(t, "tree_expand", []byte(mm.View()))
+	})
+
+	t.Run("open file", func(t *testing.T) {
+		mt.sendLetterKey('j')
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		fileMsg := cmd()
+		require.NotNil(t, fileMsg)
+
+		mm, cmd = mt.sendFileContentsMsg(fileMsg)
+		require.NotNil(t, mm)
+		require.Nil(t, cmd)
+
+		g.Assert(t, "tree_open_file", []byte(mm.View()))
+	})
+
+	t.Run("flat list", func(t *testing.T) {
+		mt.sendEscKey()
+
+		mm, _ := mt.sendLetterKey('t')
+		require.NotNil(t, mm)
+
+		g.Assert(t, "tree_flat_list", []byte(mm.View()))
+	})
+}
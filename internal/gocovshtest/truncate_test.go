@@ -0,0 +1,49 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateLongPaths(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+	mt.sendLetterKey('p')
+
+	t.Run("wide", func(t *testing.T) {
+		require.Contains(t, mt.m.View(), "example.com/tree/pkg/a/util.go")
+	})
+
+	t.Run("narrow", func(t *testing.T) {
+		mm, _ := mt.sendWindowSizeMsg(36, 20)
+		require.NotNil(t, mm)
+
+		view := mm.View()
+		require.Contains(t, view, "example.com/…/a/util.go")
+		require.Contains(t, view, "example.com/…/main.go")
+
+		g.Assert(t, "truncate_narrow", []byte(view))
+	})
+
+	t.Run("very narrow", func(t *testing.T) {
+		mm, _ := mt.sendWindowSizeMsg(28, 20)
+		require.NotNil(t, mm)
+
+		view := mm.View()
+		require.Contains(t, view, "…/pkg/a/util.go")
+
+		g.Assert(t, "truncate_very_narrow", []byte(view))
+	})
+}
@@ -0,0 +1,101 @@
+package gocovshtest
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeAhead(t *testing.T) {
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+		typeAhead:       time.Millisecond,
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	selected := func() string {
+		t.Helper()
+
+		mm, cmd := mt.sendEnterKey()
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		name := mt.m.OpenedFile()
+		mt.sendEscKey()
+
+		return name
+	}
+
+	var reset tea.Cmd
+
+	t.Run("unbound letter starts a prefix", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('m')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "Jump: m")
+		require.Equal(t, "main.go", selected())
+
+		reset = batchCmds(t, cmd)[0]
+	})
+
+	t.Run("bound letters extend the prefix", func(t *testing.T) {
+		// "a" is not bound, but "u" is
+		mt.sendLetterKey('a')
+		mm, cmd := mt.sendLetterKey('u')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), `No match for "mau"`)
+		require.Equal(t, "main.go", selected())
+
+		// the reset of the first letter is outdated
+		mt.m.Update(reset())
+
+		_, cmd = mt.sendLetterKey('x')
+		require.NotNil(t, cmd)
+		require.Contains(t, mt.m.View(), `No match for "maux"`)
+
+		reset = batchCmds(t, cmd)[0]
+	})
+
+	t.Run("prefix times out", func(t *testing.T) {
+		mt.m.Update(reset())
+
+		mm, cmd := mt.sendLetterKey('a')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/a/a.go", selected())
+		mt.m.Update(batchCmds(t, cmd)[0]())
+	})
+
+	t.Run("bound letters keep working", func(t *testing.T) {
+		mt.sendLetterKey('g')
+		require.Equal(t, "main.go", selected())
+	})
+
+	t.Run("jump key starts a prefix of bound letters", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey(';')
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "Jump: ")
+
+		// "u" is bound, but follows the jump key
+		mm, cmd = mt.sendLetterKey('u')
+		require.NotNil(t, cmd)
+		require.Contains(t, mm.View(), "Jump: u")
+		require.Equal(t, "pkg/a/util.go", selected())
+		mt.m.Update(batchCmds(t, cmd)[0]())
+
+		mt.sendLetterKey('g')
+		mt.sendLetterKey(';')
+		_, cmd = mt.sendLetterKey('b')
+		require.NotNil(t, cmd)
+		require.Equal(t, "pkg/b/b.go", selected())
+		mt.m.Update(batchCmds(t, cmd)[0]())
+	})
+}
@@ -0,0 +1,151 @@
+package gocovshtest
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchProfile(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "general", "watch")))
+
+	// the profile is modified by the test, so the fixtures are copied
+	root := t.TempDir()
+	profileTime := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, name := range []string{
+		"go.mod",
+		"profile.cover",
+		"covered.go",
+		"partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go",
+	} {
+		bs, err := os.ReadFile(filepath.Join("testdata", "general", name))
+		require.NoError(t, err)
+
+		dst := filepath.Join(root, name)
+		require.NoError(t, os.WriteFile(dst, bs, 0o600))
+		require.NoError(t, os.Chtimes(dst, profileTime, profileTime))
+	}
+
+	profilePath := filepath.Join(root, "profile.cover")
+
+	// updateProfile replaces the old text of the profile, and moves its
+	// modification time forward like a new test run would
+	updateProfile := func(t *testing.T, old, new string) {
+		t.Helper()
+
+		bs, err := os.ReadFile(profilePath)
+		require.NoError(t, err)
+		require.Contains(t, string(bs), old)
+
+		bs = []byte(strings.Replace(string(bs), old, new, 1))
+		require.NoError(t, os.WriteFile(profilePath, bs, 0o600))
+
+		profileTime = profileTime.Add(time.Minute)
+		require.NoError(t, os.Chtimes(profilePath, profileTime, profileTime))
+	}
+
+	mt := &modelTest{T: t, profileFilename: "profile.cover", codeRoot: root, watch: time.Millisecond}
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(80, 14)
+	mt.sendProfilesMsg(initMsg)
+
+	// the profile is checked with the command started along with the
+	// loading, which is run directly instead of waiting for the ticks
+	stat := batchCmds(t, mt.m.Init())[2]
+
+	// the first check only remembers the modification time
+	_, cmd := mt.m.Update(stat())
+	require.NotNil(t, cmd)
+
+	// reload returns the command reloading the profile after its change was
+	// noticed, and sends the reloaded profile to the model
+	reload := func(t *testing.T) tea.Cmd {
+		t.Helper()
+
+		_, cmd := mt.m.Update(stat())
+		require.NotNil(t, cmd)
+
+		cmds := batchCmds(t, cmd)
+		require.Len(t, cmds, 2)
+
+		_, cmd = mt.m.Update(cmds[0]())
+
+		return cmd
+	}
+
+	t.Run("unchanged profile is not reloaded", func(t *testing.T) {
+		_, cmd := mt.m.Update(stat())
+		require.NotNil(t, cmd)
+		require.IsType(t, stat(), cmd())
+	})
+
+	t.Run("selection is kept in the list", func(t *testing.T) {
+		mt.sendLetterKey('j')
+		require.Contains(t, mt.m.View(), "> partial_with")
+
+		updateProfile(t, "covered.go:3.20,5.2 1 1", "covered.go:3.20,5.2 1 0")
+		reload(t)
+
+		view := mt.m.View()
+		require.Contains(t, view, "Coverage profile reloaded")
+		require.Contains(t, view, "> partial_with")
+		g.Assert(t, "reloaded_list", []byte(view))
+	})
+
+	t.Run("open file is rendered again", func(t *testing.T) {
+		_, cmd := mt.sendEnterKey()
+		require.NotNil(t, cmd)
+		mt.sendFileContentsMsg(cmd())
+
+		mt.sendLetterKey('j')
+		mt.sendLetterKey('j')
+		require.NotContains(t, mt.m.View(), "package general")
+		g.Assert(t, "scrolled_file", []byte(mt.m.View()))
+
+		updateProfile(t, "output.go:7.26,9.2 1 0", "output.go:7.26,9.2 1 1")
+		cmds := batchCmds(t, reload(t))
+
+		// the file is colorized again last, after the profile is set
+		mt.sendFileContentsMsg(cmds[len(cmds)-1]())
+
+		view := mt.m.View()
+		require.Contains(t, view, "Coverage profile reloaded")
+		require.NotContains(t, view, "package general")
+		g.Assert(t, "reloaded_file", []byte(view))
+	})
+
+	t.Run("model is not read while it changes", func(t *testing.T) {
+		mt.sendEscKey()
+		updateProfile(t, "covered.go:3.20,5.2 1 0", "covered.go:3.20,5.2 1 1")
+
+		_, cmd := mt.m.Update(stat())
+		require.NotNil(t, cmd)
+
+		// the sort order changes while the profile is read, which go test
+		// -race reports if the command reads the model
+		reload := batchCmds(t, cmd)[0]
+		msgs := make(chan tea.Msg)
+
+		go func() { msgs <- reload() }()
+
+		mt.sendLetterKey('S')
+		mt.m.Update(<-msgs)
+		require.Contains(t, mt.m.View(), "Coverage profile reloaded")
+	})
+
+	t.Run("deleted profile is reported", func(t *testing.T) {
+		require.NoError(t, os.Remove(profilePath))
+
+		_, cmd := mt.m.Update(stat())
+		require.NotNil(t, cmd)
+		require.Contains(t, mt.m.View(), "Coverage profile is not available")
+	})
+}
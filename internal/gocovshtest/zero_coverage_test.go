@@ -0,0 +1,48 @@
+package gocovshtest
+
+import (
+	"path"
+	"testing"
+
+	"github.com/sebdah/goldie/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZeroCoverage(t *testing.T) {
+	g := goldie.New(t, goldie.WithFixtureDir(path.Join("testdata", "tree")))
+
+	mt := &modelTest{
+		T:               t,
+		profileFilename: "profile.cover",
+		codeRoot:        "testdata/tree",
+	}
+
+	initMsg := mt.init()()
+	mt.sendWindowSizeMsg(60, 20)
+	mt.sendProfilesMsg(initMsg)
+
+	t.Run("only files without coverage", func(t *testing.T) {
+		mm, cmd := mt.sendLetterKey('z')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		g.Assert(t, "zero_coverage_only", []byte(mm.View()))
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		mm, _ := mt.sendLetterKey('t')
+		require.NotNil(t, mm)
+
+		g.Assert(t, "zero_coverage_tree", []byte(mm.View()))
+	})
+
+	t.Run("all files", func(t *testing.T) {
+		mt.sendLetterKey('t')
+
+		mm, cmd := mt.sendLetterKey('z')
+		require.NotNil(t, mm)
+		require.NotNil(t, cmd)
+
+		g.Assert(t, "zero_coverage_all", []byte(mm.View()))
+	})
+}
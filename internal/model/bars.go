@@ -0,0 +1,76 @@
+package model
+
+import (
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// Widths of the coverage bars, which take an eighth of the list.
+const (
+	minBarWidth = 5
+	maxBarWidth = 20
+)
+
+// Characters of the covered and uncovered parts of the bars, with and
+// without colors.
+const (
+	barFilled       = "█"
+	barEmpty        = "░"
+	barFilledMarker = "#"
+	barEmptyMarker  = "."
+)
+
+// barWidth returns the width of the coverage bars in the list, or 0 if they
+// are hidden.
+func (m *Model) barWidth() int {
+	if !m.bars || m.list.Width() == 0 {
+		return 0
+	}
+
+	switch w := m.list.Width() / 8; {
+	case w < minBarWidth:
+		return minBarWidth
+	case w > maxBarWidth:
+		return maxBarWidth
+	default:
+		return w
+	}
+}
+
+// toggleBars shows or hides the coverage bars next to the items.
+func (m *Model) toggleBars() tea.Cmd {
+	m.bars = !m.bars
+
+	status := "Bars hidden"
+	if m.bars {
+		status = "Bars shown"
+	}
+
+	m.list.SetDelegate(m.delegate())
+
+	return m.newStatusMessage(status)
+}
+
+// renderBar renders the coverage as a bar of the delegate's width, with the
+// covered part in the color of the percentage. It returns an empty string if
+// bars are hidden.
+func (d coverProfileDelegate) renderBar(pct float64, color lipgloss.TerminalColor) string {
+	if d.barWidth == 0 {
+		return ""
+	}
+
+	filledChar, emptyChar := barFilled, barEmpty
+	if d.markers {
+		filledChar, emptyChar = barFilledMarker, barEmptyMarker
+	}
+
+	filled := int(math.Round(pct / 100 * float64(d.barWidth)))
+	inactive := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.InactiveColor))
+
+	return " " + lipgloss.NewStyle().Foreground(color).Render(strings.Repeat(filledChar, filled)) +
+		inactive.Render(strings.Repeat(emptyChar, d.barWidth-filled))
+}
@@ -0,0 +1,18 @@
+package model
+
+// showBlocks displays the blocks of the profile of the open file as they were
+// parsed, to tell apart the issues of the profile from the ones of the view.
+func (m *Model) showBlocks() {
+	profile := m.openedProfile()
+	if profile == nil {
+		return
+	}
+
+	m.blocks.SetTitle(m.openedFile)
+	m.blocks.SetBlocks(profile.Blocks)
+	m.activeView = activeViewBlocks
+}
+
+func (m *Model) isBlocksView() bool {
+	return m.activeView == activeViewBlocks
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// bookmarkNote follows the coverage of the bookmarked files.
+const bookmarkNote = " ★"
+
+// toggleBookmark bookmarks the open file in the code view, or the selected
+// file of the list, or removes its bookmark.
+func (m *Model) toggleBookmark() tea.Cmd {
+	name := m.openedFile
+	if m.isListView() {
+		f, ok := m.list.SelectedItem().(*coverProfile)
+		if !ok {
+			return m.newStatusMessage("Only files can be bookmarked")
+		}
+
+		name = f.profile.FileName
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	status := "Bookmarked " + name
+
+	if m.bookmarks[name] {
+		delete(m.bookmarks, name)
+
+		status = "Removed bookmark of " + name
+	} else {
+		if m.bookmarks == nil {
+			m.bookmarks = map[string]bool{}
+		}
+
+		m.bookmarks[name] = true
+	}
+
+	m.list.SetDelegate(m.delegate())
+
+	return m.newStatusMessage(status)
+}
+
+// nextBookmark selects the next bookmarked file of the list after the
+// selected one, wrapping around. In the code view, the file is opened.
+func (m *Model) nextBookmark() tea.Cmd {
+	if len(m.bookmarks) == 0 {
+		return m.newStatusMessage("No bookmarks")
+	}
+
+	items := m.list.VisibleItems()
+
+	for i := 1; i <= len(items); i++ {
+		index := (m.list.Index() + i) % len(items)
+
+		f, ok := items[index].(*coverProfile)
+		if !ok || !m.bookmarks[f.profile.FileName] {
+			continue
+		}
+
+		m.list.Select(index)
+
+		if m.isCodeView() {
+			return m.openSelectedFile()
+		}
+
+		return nil
+	}
+
+	return m.newStatusMessage("No bookmarked files in the list")
+}
+
+// renderBookmark renders the note of the bookmarked files.
+func renderBookmark() string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.PrimaryColor)).Render(bookmarkNote)
+}
+
+// hasBookmarks reports whether any of the files of the list is bookmarked.
+func (m *Model) hasBookmarks() bool {
+	for _, item := range m.list.Items() {
+		if f, ok := item.(*coverProfile); ok && m.bookmarks[f.profile.FileName] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Bookmarks returns the names of the bookmarked files in alphabetical order.
+func (m *Model) Bookmarks() []string {
+	names := make([]string, 0, len(m.bookmarks))
+
+	for name := range m.bookmarks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/browser"
+)
+
+// Browser opens web addresses in the browser of the user.
+type Browser interface {
+	Open(url string) error
+}
+
+type systemBrowser struct{}
+
+func (systemBrowser) Open(url string) error {
+	return browser.Command(url).Run()
+}
+
+// openInBrowser opens the address of the file that is open, or of the file
+// or directory selected in the list, built from the template of repository
+// addresses. It needs the path of the module.
+func (m *Model) openInBrowser() tea.Cmd {
+	if m.modulePath == "" {
+		return nil
+	}
+
+	f := browser.File{Module: m.modulePath, Path: m.openedFile}
+
+	if m.isCodeView() {
+		f.Line = m.code.TopLine()
+	} else {
+		switch item := m.list.SelectedItem().(type) {
+		case *coverProfile:
+			f.Path = item.profile.FileName
+		case *dirItem:
+			// directories are opened like the files in them
+			f.Path = item.path + "/"
+		default:
+			return nil
+		}
+	}
+
+	url := browser.URL(m.repoURLTemplate, f)
+	b := m.browser
+
+	return func() tea.Msg {
+		if err := b.Open(url); err != nil {
+			return statusMsg(fmt.Sprintf("Browser failed: %v", err))
+		}
+
+		return statusMsg("Opened " + url)
+	}
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// coverageBucket groups files by how much of them is covered.
+type coverageBucket int
+
+const (
+	// bucketNoStatements holds files without any statements to cover
+	bucketNoStatements coverageBucket = iota
+
+	// bucketZero holds files that were compiled but never executed
+	bucketZero
+
+	bucketPartial
+	bucketFull
+)
+
+const (
+	filesTitle     = "Available files:"
+	zeroFilesTitle = "Files without coverage:"
+)
+
+func (f *coverProfile) bucket() coverageBucket {
+	switch {
+	case f.total == 0:
+		return bucketNoStatements
+	case f.covered == 0:
+		return bucketZero
+	case f.covered < f.total:
+		return bucketPartial
+	default:
+		return bucketFull
+	}
+}
+
+// shownItems returns the files to display: all of them, or only the ones
+// without coverage or of the selected package, except the hidden generated
+// files.
+func (m *Model) shownItems() []list.Item {
+	if !m.zeroOnly && m.packageDir == "" {
+		return m.withoutGenerated(m.items)
+	}
+
+	items := make([]list.Item, 0, len(m.items))
+
+	for _, item := range m.withoutGenerated(m.items) {
+		if f, ok := item.(*coverProfile); ok && (!m.zeroOnly || f.bucket() == bucketZero) && m.inPackage(f) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// toggleZeroOnly shows only the files without coverage, or all the files
+// again.
+func (m *Model) toggleZeroOnly() tea.Cmd {
+	m.zeroOnly = !m.zeroOnly
+
+	m.list.Title = filesTitle
+	status := "Showing all files"
+
+	if m.zeroOnly {
+		m.list.Title = zeroFilesTitle
+		status = fmt.Sprintf("%d of %d", len(m.shownItems()), len(m.items))
+	}
+
+	return tea.Batch(m.refreshList(), m.newStatusMessage(status))
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/codeview"
+)
+
+// Clipboard writes text to the system clipboard.
+type Clipboard interface {
+	WriteAll(text string) error
+}
+
+type systemClipboard struct{}
+
+func (systemClipboard) WriteAll(text string) error {
+	if clipboard.Unsupported {
+		return fmt.Errorf("clipboard is not supported")
+	}
+
+	return clipboard.WriteAll(text)
+}
+
+// copyPath copies the path of the file that is open, or of the file or
+// directory selected in the list. If the clipboard is not available, for
+// example over SSH, the path is displayed instead.
+func (m *Model) copyPath() tea.Cmd {
+	path := m.openedFile
+
+	if m.isListView() {
+		switch item := m.list.SelectedItem().(type) {
+		case *coverProfile:
+			path = item.profile.FileName
+		case *dirItem:
+			path = item.path
+		default:
+			return nil
+		}
+	}
+
+	if path == "" {
+		return nil
+	}
+
+	return m.copyText(path)
+}
+
+// copyUncoveredLines copies the ranges of uncovered lines of the file that
+// is open, or of the file selected in the list, such as 12-15,22,40-41.
+func (m *Model) copyUncoveredLines() tea.Cmd {
+	profile := m.openedProfile()
+
+	if m.isListView() {
+		item, ok := m.list.SelectedItem().(*coverProfile)
+		if !ok {
+			return nil
+		}
+
+		profile = item.profile
+	}
+
+	if profile == nil {
+		return nil
+	}
+
+	ranges := uncoveredBlocks(profile)
+	if len(ranges) == 0 {
+		return m.newStatusMessage("No uncovered lines")
+	}
+
+	return m.copyText(formatLineRanges(ranges))
+}
+
+// copyText copies the text to the clipboard. If the clipboard is not
+// available, for example over SSH, the text is displayed instead.
+func (m *Model) copyText(text string) tea.Cmd {
+	cb := m.clipboard
+
+	return func() tea.Msg {
+		if err := cb.WriteAll(text); err != nil {
+			return statusMsg(fmt.Sprintf("Clipboard is not available: %s", text))
+		}
+
+		return statusMsg(fmt.Sprintf("Copied %s", text))
+	}
+}
+
+// formatLineRanges joins the ranges of lines with commas, writing the ranges
+// of a single line as just its number.
+func formatLineRanges(ranges []codeview.LineRange) string {
+	parts := make([]string, 0, len(ranges))
+
+	for _, r := range ranges {
+		if r.Start == r.End {
+			parts = append(parts, strconv.Itoa(r.Start))
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%d-%d", r.Start, r.End))
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"golang.org/x/tools/cover"
+)
+
+// readCompareProfiles loads the profile the coverage is compared with, keyed
+// by the names of the files relative to the module root. It returns nil if
+// no profile is compared.
+func (m *Model) readCompareProfiles() (map[string]*cover.Profile, error) {
+	if m.compareFilename == "" {
+		return nil, nil
+	}
+
+	pkg, _ := determinePackageName(path.Join(m.codeRoot, "go.mod"))
+
+	profiles, err := m.parseProfileFile(m.resolvePath(m.compareFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errNoCoverageFile{fmt.Errorf("failed to read compared profile: %w", err)}
+		}
+
+		return nil, errInvalidCoverageFile{fmt.Errorf("failed to parse compared profile: %w", err)}
+	}
+
+	compare := make(map[string]*cover.Profile, len(profiles))
+
+	for _, p := range profiles {
+		p.FileName = m.stripPrefix(p.FileName)
+
+		if pkg != "" {
+			p.FileName = strings.TrimPrefix(p.FileName, pkg+"/")
+		}
+
+		m.dropIgnoredBlocks(p)
+
+		compare[p.FileName] = p
+	}
+
+	return compare, nil
+}
+
+// comparison is the coverage of an item in the compared profile.
+type comparison struct {
+	// found is false for the files missing from the compared profile
+	found   bool
+	covered int64
+	total   int64
+}
+
+// delta renders the change of the coverage since the compared profile: the
+// difference of the percentages, or "new" for the files that were not
+// measured.
+func (c comparison) delta(covered, total int64) string {
+	color := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+	text := "new"
+
+	if c.found && c.total > 0 {
+		diff := percentage(covered, total) - percentage(c.covered, c.total)
+
+		switch text = fmt.Sprintf("%+.2f%%", diff); {
+		case text == "+0.00%" || text == "-0.00%":
+			text = "±0%"
+		case diff > 0:
+			color = lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+		default:
+			color = lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+		}
+	}
+
+	return percentageStyle.Copy().Foreground(color).Render(text)
+}
+
+func percentage(covered, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(total) * 100
+}
+
+// compareWith returns the coverage of the file in the compared profile.
+func (m *Model) compareWith(fileName string) comparison {
+	p, ok := m.compareProfiles[fileName]
+	if !ok {
+		return comparison{}
+	}
+
+	covered, total := m.statements(p)
+
+	return comparison{found: true, covered: covered, total: total}
+}
+
+// isComparing reports whether the coverage is compared with another profile.
+func (m *Model) isComparing() bool {
+	return m.compareFilename != ""
+}
+
+// swapProfiles displays the compared profile, and compares it with the one
+// that was displayed.
+func (m *Model) swapProfiles() tea.Cmd {
+	if m.profileContent != nil {
+		return m.newStatusMessage("Profiles can't be switched while reading the profile from stdin")
+	}
+
+	m.profileFilename, m.compareFilename = m.compareFilename, m.profileFilename
+
+	return m.reloadProfiles()
+}
+
+// lineDelta returns the lines of the file that are covered, but were not
+// covered in the compared profile, and the ones that are no longer covered.
+// Lines that were not measured before are in neither.
+func (m *Model) lineDelta(profile *cover.Profile) (newlyCovered, newlyUncovered []int) {
+	previous, ok := m.compareProfiles[profile.FileName]
+	if !ok {
+		return nil, nil
+	}
+
+	before, after := coveredLines(previous), coveredLines(profile)
+
+	for line := 1; line <= maxLine(profile); line++ {
+		was, measured := before[line]
+		if !measured {
+			continue
+		}
+
+		switch is, ok := after[line]; {
+		case ok && is && !was:
+			newlyCovered = append(newlyCovered, line)
+		case ok && !is && was:
+			newlyUncovered = append(newlyUncovered, line)
+		}
+	}
+
+	return newlyCovered, newlyUncovered
+}
+
+// coveredLines reports whether the measured lines of the profile are
+// covered. Lines shared by several blocks are covered if any of them is.
+func coveredLines(p *cover.Profile) map[int]bool {
+	lines := map[int]bool{}
+
+	for line, status := range linesCoverage(p) {
+		lines[line] = status.covered
+	}
+
+	return lines
+}
+
+func maxLine(p *cover.Profile) int {
+	last := 0
+
+	for _, b := range p.Blocks {
+		if b.EndLine > last {
+			last = b.EndLine
+		}
+	}
+
+	return last
+}
+
+// setLineDelta marks the lines of the open file whose coverage changed since
+// the compared profile.
+func (m *Model) setLineDelta(profile *cover.Profile) {
+	if !m.isComparing() {
+		return
+	}
+
+	m.code.SetDeltaLines(m.lineDelta(profile))
+}
+
+// compareLegend summarizes the lines of the open file whose coverage changed.
+func (m *Model) compareLegend(profile *cover.Profile) string {
+	newlyCovered, newlyUncovered := m.lineDelta(profile)
+
+	return fmt.Sprintf("+%d/-%d lines vs %s", len(newlyCovered), len(newlyUncovered), path.Base(m.compareFilename))
+}
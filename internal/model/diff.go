@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/orlangure/gocovsh/internal/report"
+	"golang.org/x/tools/cover"
+)
+
+// statements returns the numbers of covered and total statements the file
+// is measured by: all of them, or only the changed ones in diff-only mode.
+func (m *Model) statements(p *cover.Profile) (covered, total int64) {
+	if m.diffOnly {
+		return report.ChangedStatements(p, m.filteredLinesByFile[p.FileName])
+	}
+
+	return countStatements(p)
+}
+
+// setCodeCoverage displays the coverage of the file in the code view.
+func (m *Model) setCodeCoverage(p *cover.Profile) {
+	if m.diffOnly {
+		m.code.SetDiffCoverage(report.ChangedStatements(p, m.filteredLinesByFile[p.FileName]))
+		return
+	}
+
+	m.code.SetCoverage(countStatements(p))
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/editor"
+)
+
+// editorFinishedMsg is sent when the editor exits and the program resumes.
+type editorFinishedMsg struct{ err error }
+
+// openInEditor suspends the program and opens the current file in the
+// editor, at the first uncovered line if there is one.
+func (m *Model) openInEditor() tea.Cmd {
+	profile := m.openedProfile()
+	if profile == nil {
+		return nil
+	}
+
+	line := 0
+	if blocks := uncoveredBlocks(profile); len(blocks) > 0 {
+		line = blocks[0].Start
+	}
+
+	c := editor.Command(m.sourceFile(profile.FileName), line)
+
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorFinishedMsg{err}
+	})
+}
+
+func (m *Model) onEditorFinished(msg editorFinishedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, m.newStatusMessage(fmt.Sprintf("Editor failed: %v", msg.err))
+	}
+
+	return m, nil
+}
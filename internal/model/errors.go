@@ -27,6 +27,15 @@ Try adding tests and generating the report again.`
 }
 func (e errNoProfiles) OriginalError() error { return nil }
 
+type errNoPackageMatches struct{ error }
+
+func (e errNoPackageMatches) Title() string { return "No files in the requested packages" }
+func (e errNoPackageMatches) Description() string {
+	return `The coverage profile doesn't have files of some of the packages.
+Check the patterns of "--packages" flag, such as "./internal/...".`
+}
+func (e errNoPackageMatches) OriginalError() error { return e }
+
 type errGoModNotFound struct{ error }
 
 func (e errGoModNotFound) Title() string { return "go.mod file is not available" }
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/funccover"
+)
+
+// funcsLoadedMsg is sent when the functions of the open file are analyzed.
+type funcsLoadedMsg []*funccover.Func
+
+// loadFuncs analyzes the coverage of every function in the open file. The
+// functions are ordered by their position, unless files are sorted by
+// coverage.
+func (m *Model) loadFuncs() tea.Cmd {
+	profile := m.openedProfile()
+	if profile == nil {
+		return nil
+	}
+
+	filename := m.sourceFile(profile.FileName)
+	sortMode := m.sortMode
+
+	return func() tea.Msg {
+		src, err := os.ReadFile(filename) // nolint: gosec
+		if err != nil {
+			return statusMsg(fmt.Sprintf("Failed to read %s: %v", filename, err))
+		}
+
+		funcs, err := funccover.Analyze(filename, src, profile)
+		if err != nil {
+			return statusMsg(fmt.Sprintf("Failed to analyze functions: %v", err))
+		}
+
+		switch sortMode {
+		case SortByCoverageAsc:
+			funccover.SortByCoverage(funcs)
+		case SortByCoverageDesc:
+			funccover.SortByCoverageDesc(funcs)
+		}
+
+		return funcsLoadedMsg(funcs)
+	}
+}
+
+func (m *Model) onFuncsLoaded(funcs funcsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.funcs.SetTitle(m.openedFile)
+	m.funcs.SetFuncs(funcs)
+	m.activeView = activeViewFuncs
+
+	return m, nil
+}
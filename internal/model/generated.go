@@ -0,0 +1,100 @@
+package model
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// generatedHeaderLines is the number of lines of a source file searched for
+// the header of generated code. The header comes before the package clause,
+// so it is near the top of the file.
+const generatedHeaderLines = 10
+
+// generatedHeader is the comment marking generated code, see
+// https://go.dev/s/generatedcode.
+var generatedHeader = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// generatedSuffixes are the endings of the names of files that are usually
+// generated.
+var generatedSuffixes = []string{".pb.go", ".pb.gw.go", "_gen.go", ".gen.go", "_generated.go"}
+
+// isGeneratedName reports whether the name of the file suggests it is
+// generated.
+func isGeneratedName(fileName string) bool {
+	if strings.HasPrefix(path.Base(fileName), "zz_generated") {
+		return true
+	}
+
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(fileName, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasGeneratedHeader reports whether the first lines of the file include
+// the header of generated code. Files that can't be read are not generated.
+func hasGeneratedHeader(filePath string) bool {
+	f, err := os.Open(filePath) // nolint: gosec
+	if err != nil {
+		return false
+	}
+
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+
+	for i := 0; i < generatedHeaderLines && scanner.Scan(); i++ {
+		if generatedHeader.MatchString(strings.TrimRight(scanner.Text(), "\r")) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isGenerated reports whether the file of the profile is generated, judging
+// by its name or the header of its source.
+func isGenerated(fileName, source string) bool {
+	return isGeneratedName(fileName) || hasGeneratedHeader(source)
+}
+
+// withoutGenerated drops the generated files from the items if they are
+// hidden.
+func (m *Model) withoutGenerated(items []list.Item) []list.Item {
+	if !m.hideGenerated {
+		return items
+	}
+
+	shown := make([]list.Item, 0, len(items))
+
+	for _, item := range items {
+		if f, ok := item.(*coverProfile); ok && f.generated {
+			continue
+		}
+
+		shown = append(shown, item)
+	}
+
+	return shown
+}
+
+// toggleGenerated hides the generated files, or shows them muted.
+func (m *Model) toggleGenerated() tea.Cmd {
+	m.hideGenerated = !m.hideGenerated
+
+	status := "Generated shown"
+	if m.hideGenerated {
+		status = "Generated hidden"
+	}
+
+	return tea.Batch(m.refreshList(), m.newStatusMessage(status))
+}
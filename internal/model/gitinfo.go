@@ -0,0 +1,75 @@
+package model
+
+import (
+	"errors"
+	"log"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/gitlog"
+)
+
+// gitInfoMsg carries the description of the last commit of the file.
+type gitInfoMsg struct {
+	fileName string
+	info     string
+	err      error
+}
+
+// loadGitInfo displays the last commit of the opened file next to its title,
+// looking it up with git in the background the first time the file is
+// opened.
+func (m *Model) loadGitInfo(fileName string) tea.Cmd {
+	if !m.gitInfo {
+		return nil
+	}
+
+	info, ok := m.gitInfoByFile[fileName]
+	m.code.SetInfo(info)
+
+	if ok {
+		return nil
+	}
+
+	path := m.sourceFile(fileName)
+
+	return func() tea.Msg {
+		commit, ok, err := gitlog.Last(path)
+		if err != nil {
+			return gitInfoMsg{fileName: fileName, err: err}
+		}
+
+		if !ok {
+			return gitInfoMsg{fileName: fileName, info: "not committed"}
+		}
+
+		return gitInfoMsg{fileName: fileName, info: "last commit " + commit.String()}
+	}
+}
+
+// onGitInfo remembers the last commit of the file, and displays it if the
+// file is still open. Outside of git repositories, or without git, the
+// commits are no longer looked up.
+func (m *Model) onGitInfo(msg gitInfoMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		log.Println("failed to find last commit of", msg.fileName, msg.err)
+
+		if errors.Is(msg.err, gitlog.ErrNotRepository) || errors.Is(msg.err, exec.ErrNotFound) {
+			m.gitInfo = false
+		}
+
+		return m, nil
+	}
+
+	if m.gitInfoByFile == nil {
+		m.gitInfoByFile = map[string]string{}
+	}
+
+	m.gitInfoByFile[msg.fileName] = msg.info
+
+	if m.openedFile == msg.fileName {
+		m.code.SetInfo(msg.info)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// hasGlobs reports whether the files are selected using globs.
+func (m *Model) hasGlobs() bool {
+	return len(m.includeGlobs) > 0 || len(m.excludeGlobs) > 0
+}
+
+// matchGlobs reports whether the file should be displayed: it must match any
+// of the included globs, if there are any, and none of the excluded ones.
+// Exclusion takes precedence.
+func (m *Model) matchGlobs(fileName string) bool {
+	for _, glob := range m.excludeGlobs {
+		if matchGlob(glob, fileName) {
+			return false
+		}
+	}
+
+	if len(m.includeGlobs) == 0 {
+		return true
+	}
+
+	for _, glob := range m.includeGlobs {
+		if matchGlob(glob, fileName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchGlob reports whether the path matches the glob. Invalid globs match
+// nothing; they are rejected before the model is created.
+func matchGlob(glob, fileName string) bool {
+	matched, err := doublestar.Match(glob, fileName)
+
+	return err == nil && matched
+}
+
+// isTestFile reports whether the file is a Go test file.
+func isTestFile(fileName string) bool {
+	return strings.HasSuffix(fileName, "_test.go")
+}
@@ -0,0 +1,179 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"golang.org/x/tools/cover"
+)
+
+// heatmap shades covered lines by the number of times they were executed.
+// Levels grow logarithmically, so that a few hot loops don't make all the
+// other lines look the same.
+type heatmap struct {
+	maxCount int
+	levels   []lipgloss.Style
+}
+
+// newHeatmap returns the heatmap of the profile, or nil if the profile has no
+// hit counts, such as in "set" mode.
+func newHeatmap(profile *cover.Profile) *heatmap {
+	if profile.Mode == "set" || len(styles.CurrentTheme.HeatLines) == 0 {
+		return nil
+	}
+
+	h := &heatmap{levels: styles.CurrentTheme.HeatLines}
+
+	for _, b := range profile.Blocks {
+		if b.Count > h.maxCount {
+			h.maxCount = b.Count
+		}
+	}
+
+	if h.maxCount == 0 {
+		return nil
+	}
+
+	return h
+}
+
+// level returns the index of the style of the hit count, which is at least 1.
+func (h *heatmap) level(count int) int {
+	if h.maxCount <= 1 {
+		return len(h.levels) - 1
+	}
+
+	level := int(math.Ceil(float64(len(h.levels))*math.Log1p(float64(count))/math.Log1p(float64(h.maxCount)))) - 1
+	if level < 0 {
+		return 0
+	}
+
+	if level >= len(h.levels) {
+		return len(h.levels) - 1
+	}
+
+	return level
+}
+
+func (h *heatmap) style(count int) lipgloss.Style {
+	return h.levels[h.level(count)]
+}
+
+// legend shows the style of every level, with the lowest hit count in it.
+// Levels without hit counts up to the maximum one are skipped.
+func (h *heatmap) legend() string {
+	parts := []string{"hits"}
+	scale := math.Log1p(float64(h.maxCount)) / float64(len(h.levels))
+
+	for level := range h.levels {
+		// the lowest count of the level, corrected for rounding errors
+		start := int(math.Expm1(float64(level)*scale)) + 1
+		for start <= h.maxCount && h.level(start) < level {
+			start++
+		}
+
+		if start > h.maxCount || h.level(start) != level {
+			continue
+		}
+
+		parts = append(parts, h.levels[level].Render(fmt.Sprintf("■ %d+", start)))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// heatmapEnabled reports whether covered lines are shaded by hit count. The
+// heatmap needs colors and hit counts.
+func (m *Model) heatmapEnabled() bool {
+	return m.heatmap && m.color && m.hasHitCounts()
+}
+
+// hasHitCounts reports whether the profile records how many times the
+// blocks were executed. Profiles in "set" mode only record whether they were.
+func (m *Model) hasHitCounts() bool {
+	return m.coverMode != "set"
+}
+
+// toggleHeatmap enables or disables the heatmap. The open file is colorized
+// again. Profiles without hit counts can't be shaded.
+func (m *Model) toggleHeatmap() tea.Cmd {
+	if !m.hasHitCounts() {
+		return m.newStatusMessage("Heatmap is not available: hit counts aren't recorded in set mode")
+	}
+
+	m.heatmap = !m.heatmap
+
+	status := "Heatmap disabled"
+
+	if m.heatmap {
+		status = "Heatmap enabled"
+
+		if profile := m.openedProfile(); profile != nil && newHeatmap(profile) == nil {
+			status = "Heatmap enabled; this profile has no hit counts"
+		}
+	}
+
+	if profile := m.openedProfile(); profile != nil {
+		m.setLegend(profile)
+	}
+
+	return m.recolorize(status)
+}
+
+// toggleLegend shows or hides the explanation of the colors of the open
+// file.
+func (m *Model) toggleLegend() tea.Cmd {
+	m.legend = !m.legend
+
+	status := "Legend hidden"
+	if m.legend {
+		status = "Legend shown"
+	}
+
+	if profile := m.openedProfile(); profile != nil {
+		m.setLegend(profile)
+	}
+
+	return m.newStatusMessage(status)
+}
+
+// setLegend explains the changes since the compared profile, and the colors
+// or the markers of the open file, unless the legend is hidden.
+func (m *Model) setLegend(profile *cover.Profile) {
+	var legends []string
+
+	// the changes are more specific, so they are truncated last
+	if m.isComparing() {
+		legends = append(legends, m.compareLegend(profile))
+	}
+
+	if m.legend {
+		legends = append(legends, m.colorsLegend(profile))
+	}
+
+	m.code.SetLegend(strings.Join(legends, " • "))
+}
+
+// colorsLegend explains the patterns, the markers without colors, the levels
+// of the heatmap, or the colors of covered and uncovered lines.
+func (m *Model) colorsLegend(profile *cover.Profile) string {
+	if m.patterns {
+		return patternsLegend()
+	}
+
+	if !m.color {
+		return styles.CurrentTheme.MarkersLegend()
+	}
+
+	if m.heatmapEnabled() {
+		if h := newHeatmap(profile); h != nil {
+			return h.legend()
+		}
+	}
+
+	return styles.CurrentTheme.ColorsLegend()
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+var (
+	helpOverlayStyle       = lipgloss.NewStyle().MarginLeft(4)
+	helpOverlayFooterStyle = lipgloss.NewStyle().MarginLeft(4).MarginTop(1)
+)
+
+// helpView renders the help overlay with all key bindings. The groups of
+// bindings are rendered side by side when they fit into the screen, or one
+// below another otherwise.
+func (m *Model) helpView() string {
+	h := help.New()
+	h.Width = math.MaxInt32
+
+	// profiles can only be switched when one is compared with another, the
+	// heatmap needs hit counts, and addresses need the path of the module
+	keys := DefaultKeyMap
+	keys.Compare.SetEnabled(m.isComparing())
+	keys.Heatmap.SetEnabled(m.hasHitCounts())
+	keys.OpenBrowser.SetEnabled(m.modulePath != "")
+
+	groups := keys.FullHelp()
+	content := h.FullHelpView(groups)
+
+	if lipgloss.Width(content)+helpOverlayStyle.GetHorizontalMargins() > m.width {
+		columns := make([]string, 0, len(groups))
+
+		for _, group := range groups {
+			columns = append(columns, h.FullHelpView([][]key.Binding{group}))
+		}
+
+		content = strings.Join(columns, "\n\n")
+	}
+
+	inactiveColor := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+	footer := helpOverlayFooterStyle.Foreground(inactiveColor).Render("Press ? or esc to close")
+
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		m.list.Styles.TitleBar.Render(m.list.Styles.Title.Render("Key bindings:")),
+		helpOverlayStyle.Render(content),
+		footer,
+	)
+}
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"go/scanner"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// ignorePrefix starts the comments excluding lines from the coverage:
+// "//gocovsh:ignore" excludes a single line, and "//gocovsh:ignore-start"
+// with "//gocovsh:ignore-end" exclude the lines between them.
+const ignorePrefix = "//gocovsh:"
+
+const (
+	ignoreDirective      = "ignore"
+	ignoreStartDirective = "ignore-start"
+	ignoreEndDirective   = "ignore-end"
+)
+
+// ignoredLines returns the numbers of the lines of the file excluded from
+// the coverage with comments. Only comments count, not the text of strings.
+// A trailing comment excludes its own line, and a comment on a line of its
+// own excludes the next one. A range without an end excludes nothing. Missing
+// files have no ignored lines.
+func ignoredLines(filePath string) map[int]bool {
+	src, err := os.ReadFile(filePath) // nolint: gosec
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Println("failed to read ignore comments of", filePath, err)
+		}
+
+		return nil
+	}
+
+	// most files have no ignore comments, and don't need to be scanned
+	if !bytes.Contains(src, []byte(ignorePrefix)) {
+		return nil
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(filePath, -1, len(src))
+
+	var s scanner.Scanner
+
+	// the errors of invalid code are not reported: the comments are still
+	// found, and the sources may not even be Go
+	s.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+
+	ignored := map[int]bool{}
+	rangeStart, lastLine := 0, 0
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		line := file.Line(pos)
+		trailing := line == lastLine
+		lastLine = line
+
+		if tok != token.COMMENT || !strings.HasPrefix(lit, ignorePrefix) {
+			continue
+		}
+
+		switch ignoreCommentDirective(lit) {
+		case ignoreStartDirective:
+			if rangeStart == 0 {
+				rangeStart = line
+			}
+		case ignoreEndDirective:
+			if rangeStart == 0 {
+				log.Println("ignore-end without ignore-start at", file.Position(pos))
+				continue
+			}
+
+			for l := rangeStart; l <= line; l++ {
+				ignored[l] = true
+			}
+
+			rangeStart = 0
+		case ignoreDirective:
+			if trailing {
+				ignored[line] = true
+			} else {
+				ignored[line+1] = true
+			}
+		}
+	}
+
+	if rangeStart != 0 {
+		log.Printf("ignore-start without ignore-end at %s:%d, the lines are not ignored", filePath, rangeStart)
+	}
+
+	return ignored
+}
+
+// ignoreCommentDirective returns the directive of the comment, which may be
+// followed by the reason, as in "//gocovsh:ignore unreachable".
+func ignoreCommentDirective(comment string) string {
+	fields := strings.Fields(strings.TrimPrefix(comment, ignorePrefix))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return fields[0]
+}
+
+// dropIgnoredBlocks excludes the blocks starting on the lines ignored with
+// comments from the profile.
+func (m *Model) dropIgnoredBlocks(p *cover.Profile) {
+	if m.ignoreComments {
+		dropBlocks(p, ignoredLines(m.sourcePath(p.FileName)))
+	}
+}
+
+// dropIgnoredProfiles excludes the files whose blocks were all ignored with
+// comments from the profiles, since there is nothing left to show of them.
+func (m *Model) dropIgnoredProfiles(profiles []*cover.Profile) []*cover.Profile {
+	if !m.ignoreComments {
+		return profiles
+	}
+
+	kept := profiles[:0]
+
+	for _, p := range profiles {
+		if len(p.Blocks) == 0 {
+			log.Println("ignoring", p.FileName, "entirely")
+			continue
+		}
+
+		kept = append(kept, p)
+	}
+
+	return kept
+}
+
+// dropBlocks excludes the blocks starting on the ignored lines from the
+// profile, so that they count neither as covered nor as uncovered
+// statements.
+func dropBlocks(p *cover.Profile, ignored map[int]bool) {
+	if len(ignored) == 0 {
+		return
+	}
+
+	blocks := make([]cover.ProfileBlock, 0, len(p.Blocks))
+
+	for _, b := range p.Blocks {
+		if !ignored[b.StartLine] {
+			blocks = append(blocks, b)
+		}
+	}
+
+	if dropped := len(p.Blocks) - len(blocks); dropped > 0 {
+		log.Println("ignoring", dropped, "blocks of", p.FileName)
+	}
+
+	p.Blocks = blocks
+}
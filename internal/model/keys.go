@@ -0,0 +1,205 @@
+package model
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/orlangure/gocovsh/internal/codeview"
+)
+
+var listKeyMap = list.DefaultKeyMap()
+
+// KeyMap includes all key bindings of the program. The bindings of the views
+// are taken from their own key maps, so the help overlay is always in sync
+// with them. New bindings should be added here to appear in the overlay.
+type KeyMap struct {
+	// navigation
+	Up             key.Binding
+	Down           key.Binding
+	Top            key.Binding
+	Bottom         key.Binding
+	NextPage       key.Binding
+	PrevPage       key.Binding
+	PageDown       key.Binding
+	PageUp         key.Binding
+	HalfScreenDown key.Binding
+	HalfScreenUp   key.Binding
+	ScrollLeft     key.Binding
+	ScrollRight    key.Binding
+	ScrollReset    key.Binding
+
+	// files and search
+	Open      key.Binding
+	Back      key.Binding
+	Filter    key.Binding
+	Tree      key.Binding
+	Paths     key.Binding
+	Sort      key.Binding
+	Zero      key.Binding
+	Package   key.Binding
+	Generated key.Binding
+	Bars      key.Binding
+	Expand    key.Binding
+	Jump      key.Binding
+	Search    key.Binding
+	Case      key.Binding
+
+	// bookmarks
+	Bookmark     key.Binding
+	NextBookmark key.Binding
+
+	// coverage
+	NextUncovered key.Binding
+	PrevUncovered key.Binding
+	UncoveredOnly key.Binding
+	GapsOnly      key.Binding
+	DiffView      key.Binding
+	Minimap       key.Binding
+	Fold          key.Binding
+	Recenter      key.Binding
+	LineNumbers   key.Binding
+	Syntax        key.Binding
+	Heatmap       key.Binding
+	Legend        key.Binding
+	Compare       key.Binding
+
+	// views and actions
+	Funcs       key.Binding
+	Blocks      key.Binding
+	Tests       key.Binding
+	Export      key.Binding
+	CopyPath    key.Binding
+	CopyLines   key.Binding
+	OpenEditor  key.Binding
+	OpenBrowser key.Binding
+	RunTests    key.Binding
+
+	// general
+	Help key.Binding
+	Quit key.Binding
+}
+
+// DefaultKeyMap is the default KeyMap used by the model.
+var DefaultKeyMap = KeyMap{
+	Up:             codeview.DefaultKeyMap.Up,
+	Down:           codeview.DefaultKeyMap.Down,
+	Top:            codeview.DefaultKeyMap.Home,
+	Bottom:         codeview.DefaultKeyMap.End,
+	NextPage:       listKeyMap.NextPage,
+	PrevPage:       listKeyMap.PrevPage,
+	PageDown:       codeview.DefaultKeyMap.PageDown,
+	PageUp:         codeview.DefaultKeyMap.PageUp,
+	HalfScreenDown: codeview.DefaultKeyMap.HalfScreenDown,
+	HalfScreenUp:   codeview.DefaultKeyMap.HalfScreenUp,
+	ScrollLeft:     codeview.DefaultKeyMap.ScrollLeft,
+	ScrollRight:    codeview.DefaultKeyMap.ScrollRight,
+	ScrollReset:    codeview.DefaultKeyMap.ScrollReset,
+
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open file"),
+	),
+	Back:   codeview.DefaultKeyMap.Back,
+	Filter: listKeyMap.Filter,
+	Tree: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "toggle tree"),
+	),
+	Paths: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "toggle full paths"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "cycle sort order"),
+	),
+	Zero: key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "files without coverage"),
+	),
+	Package: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "only this package"),
+	),
+	Generated: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "hide/show generated files"),
+	),
+	Bars: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "toggle coverage bars"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "expand/collapse"),
+	),
+	Jump: key.NewBinding(
+		key.WithKeys(";"),
+		key.WithHelp(";", "jump to file by name"),
+	),
+	Search: codeview.DefaultKeyMap.Search,
+	Case:   codeview.DefaultKeyMap.SearchCase,
+
+	Bookmark: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "toggle bookmark"),
+	),
+	NextBookmark: key.NewBinding(
+		key.WithKeys("'"),
+		key.WithHelp("'", "next bookmark"),
+	),
+
+	NextUncovered: codeview.DefaultKeyMap.NextUncovered,
+	PrevUncovered: codeview.DefaultKeyMap.PrevUncovered,
+	UncoveredOnly: codeview.DefaultKeyMap.UncoveredOnly,
+	GapsOnly:      codeview.DefaultKeyMap.GapsOnly,
+	DiffView:      codeview.DefaultKeyMap.DiffView,
+	Minimap:       codeview.DefaultKeyMap.Minimap,
+	Fold:          codeview.DefaultKeyMap.Fold,
+	Recenter:      codeview.DefaultKeyMap.Recenter,
+	LineNumbers:   codeview.DefaultKeyMap.LineNumbers,
+	Syntax:        codeview.DefaultKeyMap.Syntax,
+	Heatmap:       codeview.DefaultKeyMap.Heatmap,
+	Legend:        codeview.DefaultKeyMap.Legend,
+	Compare: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "switch compared profile"),
+	),
+
+	Funcs:       codeview.DefaultKeyMap.Funcs,
+	Blocks:      codeview.DefaultKeyMap.Blocks,
+	Tests:       codeview.DefaultKeyMap.Tests,
+	Export:      codeview.DefaultKeyMap.Export,
+	CopyPath:    codeview.DefaultKeyMap.CopyPath,
+	CopyLines:   codeview.DefaultKeyMap.CopyUncovered,
+	OpenEditor:  codeview.DefaultKeyMap.OpenEditor,
+	OpenBrowser: codeview.DefaultKeyMap.OpenBrowser,
+	RunTests: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "rerun tests"),
+	),
+
+	Help: key.NewBinding(
+		key.WithKeys(codeview.DefaultKeyMap.Help.Keys()...),
+		key.WithHelp(codeview.DefaultKeyMap.Help.Help().Key, "toggle help"),
+	),
+	Quit: codeview.DefaultKeyMap.Quit,
+}
+
+// ShortHelp implements help.KeyMap interface.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Help, k.Quit}
+}
+
+// FullHelp implements help.KeyMap interface.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom, k.NextPage, k.PrevPage, k.PageDown, k.PageUp},
+		{k.HalfScreenDown, k.HalfScreenUp},
+		{k.ScrollLeft, k.ScrollRight, k.ScrollReset},
+		{k.Open, k.Back, k.Filter, k.Tree, k.Paths, k.Sort, k.Zero, k.Package, k.Generated, k.Bars, k.Expand, k.Jump, k.Search, k.Case},
+		{k.Bookmark, k.NextBookmark},
+		{k.NextUncovered, k.PrevUncovered, k.UncoveredOnly, k.GapsOnly, k.DiffView, k.Minimap, k.Fold, k.Recenter, k.LineNumbers, k.Syntax, k.Heatmap, k.Legend},
+		{k.Funcs, k.Blocks, k.Tests, k.Export, k.CopyPath, k.CopyLines, k.OpenEditor, k.OpenBrowser, k.RunTests, k.Compare},
+		{k.Help, k.Quit},
+	}
+}
@@ -3,11 +3,15 @@ package model
 import (
 	"fmt"
 	"io"
+	"path"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/paginator"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/internal/targets"
 	"golang.org/x/tools/cover"
 )
 
@@ -19,40 +23,426 @@ var (
 	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
 	statusBarStyle    = lipgloss.NewStyle().MarginLeft(4)
 	percentageStyle   = lipgloss.NewStyle().PaddingLeft(1)
+	emptyListStyle    = lipgloss.NewStyle().Align(lipgloss.Center)
+	headerStyle       = lipgloss.NewStyle().MarginLeft(4)
 )
 
+// staleNote follows the coverage of the files modified after the profile.
+const staleNote = " stale"
+
+// headerHeight is the number of lines rendered above the list.
+const headerHeight = 1
+
 type coverProfile struct {
 	profile    *cover.Profile
 	percentage float64
+
+	// name is displayed in the list: either the relative or the full path
+	name string
+
+	// fullName is the path of the file as it appears in the profile
+	fullName string
+
+	// source is the path of the source of the file, resolved when the
+	// profile is loaded
+	source string
+
+	// depth is the nesting level of the file in the tree
+	depth int
+
+	// covered and total statements, for the aggregate coverage
+	covered int64
+	total   int64
+
+	// compare is the coverage of the file in the compared profile
+	compare comparison
+
+	// generated files are muted in the list
+	generated bool
+
+	// stale files were modified after the profile
+	stale bool
+
+	// missing files have no source to open
+	missing bool
+}
+
+func (f *coverProfile) FilterValue() string { return f.name }
+
+// itemFullName returns the path of the file in the profile.
+func (f *coverProfile) itemFullName() string {
+	if f.fullName == "" {
+		return f.profile.FileName
+	}
+
+	return f.fullName
 }
 
-func (f *coverProfile) FilterValue() string { return f.profile.FileName }
+type coverProfileDelegate struct {
+	threshold float64
 
-type coverProfileDelegate struct{}
+	// targets override the threshold of the matching files and directories
+	targets targets.Targets
+
+	// markers prefix files below the threshold with a symbol, for
+	// rendering without colors
+	markers bool
+
+	// tree renders the files nested under their directories
+	tree bool
+
+	// diffOnly marks the items without changed statements
+	diffOnly bool
+
+	// compare adds the change of coverage since the compared profile
+	compare bool
+
+	// nameWidth is the width the names are truncated and padded to, so that
+	// the coverage is aligned; 0 leaves them as is
+	nameWidth int
+
+	// barWidth is the width of the bars following the coverage; 0 hides them
+	barWidth int
+
+	// bookmarks are the names of the bookmarked files
+	bookmarks map[string]bool
+}
+
+// delegate returns the delegate rendering the items of the list according to
+// the current settings.
+func (m *Model) delegate() coverProfileDelegate {
+	return coverProfileDelegate{
+		threshold: m.threshold,
+		targets:   m.targets,
+		markers:   !m.color,
+		tree:      m.tree,
+		diffOnly:  m.diffOnly,
+		compare:   m.isComparing(),
+		nameWidth: m.nameWidth(),
+		barWidth:  m.barWidth(),
+		bookmarks: m.bookmarks,
+	}
+}
 
 func (d coverProfileDelegate) Height() int                               { return 1 }
 func (d coverProfileDelegate) Spacing() int                              { return 0 }
 func (d coverProfileDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd { return nil }
 func (d coverProfileDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
-	profile, ok := listItem.(*coverProfile)
-	if !ok {
+	var (
+		percentage float64
+		covered    int64
+		total      int64
+		compare    comparison
+		generated  bool
+		stale      bool
+		missing    bool
+		bookmarked bool
+	)
+
+	switch item := listItem.(type) {
+	case *coverProfile:
+		percentage, covered, total, compare = item.percentage, item.covered, item.total, item.compare
+		generated, stale, missing = item.generated, item.stale, item.missing
+		bookmarked = d.bookmarks[item.profile.FileName]
+	case *dirItem:
+		percentage, covered, total, compare = item.percentage, item.covered, item.total, item.compare
+	default:
 		return
 	}
 
-	line := d.renderBaseLine(profile)
+	name, indent := itemLabel(listItem, d.tree)
+	d.threshold = d.thresholdOf(listItem)
+
+	// matches are offsets in the full path, but only the last element of
+	// it is displayed in the tree
+	matches := shiftMatches(m.MatchesForItem(index), len(listItem.FilterValue())-len(strings.TrimSuffix(name, "/")))
+
+	if d.nameWidth > 0 {
+		name, matches = fitName(name, matches, d.nameWidth-lipgloss.Width(indent))
+	}
+
+	render := d.renderBaseLine
+	if d.diffOnly && total == 0 {
+		render = d.renderUnchangedLine
+	}
+
+	base := lipgloss.NewStyle()
+	if generated {
+		render = d.renderGeneratedLine
+		base = base.Foreground(lipgloss.Color(styles.CurrentTheme.InactiveColor))
+	}
+
+	delta := ""
+	if d.compare {
+		delta = compare.delta(covered, total)
+	}
+
+	if stale {
+		staleStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.SecondaryColor))
+		delta = staleStyle.Render(staleNote) + delta
+	}
+
+	if missing {
+		missingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.SecondaryColor))
+		delta = missingStyle.Render(missingNote) + delta
+	}
+
+	if bookmarked {
+		delta = renderBookmark() + delta
+	}
 
 	if index == m.Index() {
-		line = selectedItemStyle.Foreground(lipgloss.Color(styles.CurrentTheme.PrimaryColor)).Render("> " + line)
-	} else {
-		line = itemStyle.Render(line)
+		color := lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+		line := render(name, percentage, matches, lipgloss.NewStyle().Foreground(color))
+		fmt.Fprint(w, selectedItemStyle.Foreground(color).Render("> "+indent+line)+delta)
+
+		return
 	}
 
+	line := itemStyle.Render(indent+render(name, percentage, matches, base)) + delta
+
 	fmt.Fprint(w, line)
 }
 
-func (d coverProfileDelegate) renderBaseLine(p *coverProfile) string {
+// thresholdOf returns the coverage target of the file or directory, or the
+// threshold of all the files if no target matches it.
+func (d coverProfileDelegate) thresholdOf(listItem list.Item) float64 {
+	key := strings.TrimSuffix(selectedKey(listItem), "/")
+
+	if target, ok := d.targets.For(key); ok {
+		return target
+	}
+
+	return d.threshold
+}
+
+// hasThresholds reports whether files below a threshold or their targets are
+// highlighted.
+func (m *Model) hasThresholds() bool {
+	return m.threshold > 0 || !m.targets.Empty()
+}
+
+// Prefixes of the rows of the tree.
+const (
+	treeIndent          = "  "
+	treeExpandedMarker  = "▾ "
+	treeCollapsedMarker = "▸ "
+	treeFileMarker      = "  "
+)
+
+// renderBaseLine renders the name and its coverage. The name is rendered
+// using the base style, unless a threshold is set.
+func (d coverProfileDelegate) renderBaseLine(name string, pct float64, matches []int, base lipgloss.Style) string {
+	if d.threshold > 0 {
+		color := lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+		if pct < d.threshold {
+			color = lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+		}
+
+		style := lipgloss.NewStyle().Foreground(color)
+		percentage := percentageStyle.Foreground(color).Render(fmt.Sprintf("%6.2f%%", pct))
+
+		fileName := highlightMatches(name, matches, style)
+
+		if d.markers {
+			marker := styles.CurrentTheme.CoveredMarker
+			if pct < d.threshold {
+				marker = styles.CurrentTheme.UncoveredMarker
+			}
+
+			fileName = marker + fileName
+		}
+
+		return fmt.Sprintf("%s %s%s", style.Render(fileName), percentage, d.renderBar(pct, color))
+	}
+
+	fileName := highlightMatches(name, matches, base)
+
+	inactiveColor := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+	percentage := percentageStyle.Foreground(inactiveColor).Render(fmt.Sprintf("%6.2f%%", pct))
+
+	return fmt.Sprintf("%s %s%s", fileName, percentage, d.renderBar(pct, inactiveColor))
+}
+
+// renderUnchangedLine renders the name of an item without changed
+// statements, which has no diff coverage.
+func (d coverProfileDelegate) renderUnchangedLine(name string, _ float64, matches []int, base lipgloss.Style) string {
+	inactiveColor := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+	note := percentageStyle.Foreground(inactiveColor).Render("no changed statements")
+
+	return fmt.Sprintf("%s %s", highlightMatches(name, matches, base), note)
+}
+
+// renderGeneratedLine renders the name of a generated file muted, whatever
+// its coverage.
+func (d coverProfileDelegate) renderGeneratedLine(name string, pct float64, matches []int, base lipgloss.Style) string {
 	inactiveColor := lipgloss.Color(styles.CurrentTheme.InactiveColor)
-	percentage := percentageStyle.Foreground(inactiveColor).Render(fmt.Sprintf("%.2f%%", p.percentage))
+	percentage := percentageStyle.Foreground(inactiveColor).Render(fmt.Sprintf("%6.2f%%", pct))
+	note := lipgloss.NewStyle().Foreground(inactiveColor).Render(" generated")
+
+	bar := d.renderBar(pct, inactiveColor)
+
+	return fmt.Sprintf("%s %s%s%s", base.Render(highlightMatches(name, matches, base)), percentage, bar, note)
+}
+
+// shiftMatches moves the offsets of matches back by n bytes, dropping the
+// ones before the beginning.
+func shiftMatches(matches []int, n int) []int {
+	if n == 0 {
+		return matches
+	}
+
+	shifted := make([]int, 0, len(matches))
+
+	for _, i := range matches {
+		if i >= n {
+			shifted = append(shifted, i-n)
+		}
+	}
+
+	return shifted
+}
+
+// highlightMatches underlines the characters of s at the provided byte
+// offsets, usually the ones that matched the current filter. Other characters
+// are rendered using the base style. Without matches, s is returned as-is.
+func highlightMatches(s string, matches []int, base lipgloss.Style) string {
+	if len(matches) == 0 {
+		return s
+	}
+
+	matched := make(map[int]bool, len(matches))
+	for _, i := range matches {
+		matched[i] = true
+	}
+
+	var b strings.Builder
+
+	matchStyle := base.Copy().Underline(true)
+
+	for i, r := range s {
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+		} else {
+			b.WriteString(base.Render(string(r)))
+		}
+	}
+
+	return b.String()
+}
+
+// headerView renders the weighted coverage of all the files in the list, or
+// of their changed statements in diff-only mode. When only some of the files
+// are displayed, the coverage is of them only, and the header says so.
+func (m *Model) headerView() string {
+	var (
+		covered, total int64
+		compare        = comparison{found: true}
+	)
+
+	// collapsed files of the tree aren't in the list, but still count
+	items := m.shownItems()
+	if m.list.FilterState() != list.Unfiltered {
+		items = m.list.VisibleItems()
+	}
+
+	for _, item := range items {
+		if p, ok := item.(*coverProfile); ok {
+			covered += p.covered
+			total += p.total
+			compare.covered += p.compare.covered
+			compare.total += p.compare.total
+		}
+	}
+
+	var percentage float64
+	if total > 0 {
+		percentage = float64(covered) / float64(total) * 100
+	}
+
+	color := lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+	if m.threshold > 0 && percentage < m.threshold {
+		color = lipgloss.Color(styles.CurrentTheme.SecondaryColor)
+	}
+
+	inactive := lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.InactiveColor))
+	label, unit := "Total", "statements"
+	if m.diffOnly {
+		label, unit = "Diff", "changed statements"
+	}
+
+	header := lipgloss.NewStyle().Bold(true).Foreground(color).Render(fmt.Sprintf("%s: %.2f%%", label, percentage))
+	header += inactive.Render(fmt.Sprintf(" (%d/%d %s)", covered, total, unit))
+
+	if m.isComparing() {
+		header += compare.delta(covered, total) + inactive.Render(" vs "+path.Base(m.compareFilename))
+	}
+
+	switch {
+	case m.excludedFiles > 0:
+		header += inactive.Render(fmt.Sprintf(" • filtered, %d excluded by globs", m.excludedFiles))
+	case m.isFiltered():
+		header += inactive.Render(" • filtered")
+	}
+
+	// the mode is the least important part, so it is left out if it doesn't
+	// fit
+	if mode := inactive.Render(" • mode: " + m.coverMode); m.coverMode != "" &&
+		lipgloss.Width(header+mode)+headerStyle.GetHorizontalMargins() <= m.width {
+		header += mode
+	}
+
+	return headerStyle.Render(header)
+}
+
+// updatePages runs an update of the list that can change the number of its
+// pages. The list renders the dots of all the pages to measure them, which
+// takes quadratic time, and profiles with thousands of files have thousands
+// of pages until the height of the list is known. Page numbers are used
+// during the update, and the dots are restored if they fit.
+func (m *Model) updatePages(update func()) {
+	m.list.Paginator.Type = paginator.Arabic
+
+	update()
+
+	// the names are fitted to the new items and width
+	m.list.SetDelegate(m.delegate())
+
+	if m.list.Paginator.TotalPages <= m.width {
+		m.list.Paginator.Type = paginator.Dots
+	}
+}
+
+// isFiltered reports whether only some of the files of the profile are
+// displayed in the list.
+func (m *Model) isFiltered() bool {
+	return m.requestedFiles != nil || m.fileFilter != nil || len(m.packages) > 0 || m.hasGlobs() ||
+		m.packageDir != "" || m.list.FilterState() != list.Unfiltered
+}
+
+// emptyListView explains why there are no files to display, and how to
+// display some, in the middle of the screen.
+func (m *Model) emptyListView() string {
+	var message string
+
+	switch {
+	case m.fileFilter != nil:
+		message = fmt.Sprintf("No files match the filter %q.\nTry a different -filter pattern.", m.fileFilter.String())
+	case m.hasGlobs():
+		message = "No files match the -include and -exclude globs.\nTry different globs."
+	case m.requestedFiles != nil:
+		message = "None of the requested files are in the coverage profile.\n" +
+			"Check the files piped to stdin or passed to -files."
+	default:
+		message = "No files of the coverage profile are displayed.\nCheck the flags that select the files."
+	}
+
+	title := m.list.Styles.TitleBar.Render(m.list.Styles.Title.Render(m.list.Title))
+	body := emptyListStyle.Foreground(lipgloss.Color(styles.CurrentTheme.InactiveColor)).Render(message + "\nPress q to exit.")
 
-	return fmt.Sprintf("%s %s", p.profile.FileName, percentage)
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		title,
+		lipgloss.Place(m.width, m.height-lipgloss.Height(title), lipgloss.Center, lipgloss.Center, body),
+	)
 }
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+var loadingStyle = lipgloss.NewStyle().Margin(1, 0, 0, 4)
+
+// newSpinner returns the spinner displayed while the profile is parsed.
+func newSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color(styles.CurrentTheme.PrimaryColor))
+
+	return s
+}
+
+// onSpinnerTick animates the spinner until the profile is loaded.
+func (m *Model) onSpinnerTick(msg spinner.TickMsg) (tea.Model, tea.Cmd) {
+	if !m.loading {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+
+	m.spinner, cmd = m.spinner.Update(msg)
+
+	return m, cmd
+}
+
+// loadingView renders the spinner while the profile is parsed. Large
+// profiles take a while, and the screen would be blank otherwise.
+func (m *Model) loadingView() string {
+	return loadingStyle.Render(m.spinner.View() + "Loading coverage profile...")
+}
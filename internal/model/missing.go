@@ -0,0 +1,80 @@
+package model
+
+import (
+	"log"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"golang.org/x/tools/cover"
+)
+
+// missingNote follows the coverage of the files whose sources are not found.
+const missingNote = " missing"
+
+// missingLegend suggests the flags resolving the sources in the footer.
+const missingLegend = "source not found: check -root or -strip-prefix"
+
+// missingFiles returns the names of the files whose sources are not found,
+// once the profile is loaded. Without the check, they are only found out when
+// they are opened.
+func (m *Model) missingFiles(profiles []*cover.Profile, sources map[string]string) map[string]bool {
+	if !m.sourceCheck {
+		return nil
+	}
+
+	found := make([]bool, len(profiles))
+
+	m.forEachSource(profiles, sources, func(i int, source string) {
+		found[i] = fileExists(source)
+	})
+
+	missing := map[string]bool{}
+
+	for i, p := range profiles {
+		if !found[i] {
+			log.Println("missing source of", p.FileName)
+
+			missing[p.FileName] = true
+		}
+	}
+
+	return missing
+}
+
+// setMissing marks the file in the list as missing its source, or found.
+func (m *Model) setMissing(fileName string, missing bool) {
+	if m.missing == nil {
+		m.missing = map[string]bool{}
+	}
+
+	m.missing[fileName] = missing
+
+	for _, item := range m.items {
+		if f, ok := item.(*coverProfile); ok && f.profile.FileName == fileName {
+			f.missing = missing
+		}
+	}
+
+	// the names make room for the note
+	m.list.SetDelegate(m.delegate())
+}
+
+// onSourceNotFound displays a placeholder instead of the opened file, and
+// marks it in the list.
+func (m *Model) onSourceNotFound() (tea.Model, tea.Cmd) {
+	m.setMissing(m.openedFile, true)
+
+	m.code.SetUncoveredBlocks(nil)
+	m.code.SetCoveredBlocks(nil)
+	m.code.SetFilteredLines(nil)
+	m.code.SetLegend(missingLegend)
+	m.code.SetContent([]string{
+		styles.CurrentTheme.UncoveredLine.Render("Source file not found: " + m.sourceFile(m.openedFile)),
+		"",
+		styles.CurrentTheme.NeutralLine.Render("Use -root to look up source files in another directory,"),
+		styles.CurrentTheme.NeutralLine.Render("or -strip-prefix to remove a prefix of their paths."),
+	})
+	m.activeView = activeViewCode
+
+	return m, nil
+}
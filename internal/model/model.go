@@ -4,21 +4,36 @@ package model
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path"
+	"path/filepath"
 	"regexp"
-	"sort"
+	"runtime"
 	"strings"
+	"time"
 
+	"github.com/alecthomas/chroma/v2"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/orlangure/gocovsh/internal/blockview"
+	"github.com/orlangure/gocovsh/internal/browser"
 	"github.com/orlangure/gocovsh/internal/codeview"
 	"github.com/orlangure/gocovsh/internal/errorview"
+	"github.com/orlangure/gocovsh/internal/export"
+	"github.com/orlangure/gocovsh/internal/funcview"
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/orlangure/gocovsh/internal/pkgpattern"
+	"github.com/orlangure/gocovsh/internal/report"
 	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/internal/targets"
+	"github.com/orlangure/gocovsh/internal/testview"
 	"golang.org/x/tools/cover"
 )
 
@@ -27,29 +42,38 @@ var modulePattern = regexp.MustCompile(`module\s+(.+)`)
 type viewName string
 
 const (
-	activeViewList  viewName = "list"
-	activeViewCode  viewName = "code"
-	activeViewError viewName = "error"
-)
-
-type helpState int
-
-const (
-	helpStateHidden helpState = iota
-	helpStateShort
-	helpStateFull
+	activeViewList   viewName = "list"
+	activeViewCode   viewName = "code"
+	activeViewFuncs  viewName = "funcs"
+	activeViewBlocks viewName = "blocks"
+	activeViewTests  viewName = "tests"
+	activeViewError  viewName = "error"
 )
 
 // New create a new model that can be used directly in the tea framework.
 func New(opts ...Option) *Model {
 	m := &Model{
-		activeView: activeViewList,
-		helpState:  helpStateShort,
-		codeRoot:   ".",
-		list:       list.New([]list.Item{}, coverProfileDelegate{}, 0, 0),
+		activeView:       activeViewList,
+		codeRoot:         ".",
+		format:           parser.FormatGo,
+		foldContext:      codeview.DefaultFoldContext,
+		syntaxTheme:      DefaultSyntaxTheme,
+		color:            true,
+		legend:           true,
+		confirmQuit:      true,
+		typeAheadTimeout: DefaultTypeAheadTimeout,
+		clipboard:        systemClipboard{},
+		browser:          systemBrowser{},
+		repoURLTemplate:  browser.DefaultTemplate,
+		list:             list.New([]list.Item{}, coverProfileDelegate{}, 0, 0),
+		loading:          true,
+		spinner:          newSpinner(),
+		staleCheck:       true,
+		workers:          runtime.NumCPU(),
+		openIndex:        -1,
 	}
 
-	m.list.Title = "Available files:"
+	m.list.Title = filesTitle
 	m.list.SetShowStatusBar(true)
 	m.list.SetFilteringEnabled(true)
 	m.list.Styles.Title = titleStyle
@@ -62,6 +86,28 @@ func New(opts ...Option) *Model {
 		opt(m)
 	}
 
+	// the module is read by both the commands loading the profiles and the
+	// updates opening the files, so it is only looked up here
+	m.module = findModule(m.codeRoot)
+
+	// "?" opens the help overlay instead of the full help of the list
+	m.list.KeyMap.ShowFullHelp = codeview.DefaultKeyMap.Help
+
+	m.list.SetDelegate(m.delegate())
+
+	// show the current sort order and package filter, and explain the markers
+	// of files below the threshold in the help
+	var legend []key.Binding
+
+	if !m.color && m.hasThresholds() {
+		marker := strings.TrimSpace(styles.CurrentTheme.UncoveredMarker)
+		legend = append(legend, key.NewBinding(key.WithKeys(marker), key.WithHelp(marker, "below threshold")))
+	}
+
+	m.list.AdditionalShortHelpKeys = func() []key.Binding {
+		return append(append(m.sortIndicator(), m.packageIndicator()...), legend...)
+	}
+
 	return m
 }
 
@@ -70,25 +116,109 @@ type Model struct {
 	list  list.Model
 	items []list.Item
 
-	code codeview.Model
+	code   codeview.Model
+	funcs  funcview.Model
+	blocks blockview.Model
+	tests  testview.Model
 
 	codeRoot            string
+	sourceRoot          string
+	stripPrefixes       []string
+	module              module
 	profileFilename     string
-	sortByCoverage      bool
-	detectedPackageName string
+	compareFilename     string
+	compareProfiles     map[string]*cover.Profile
+	profileContent      []byte
+	profileModTime      time.Time
+	format              parser.Format
+	mode                string
+	parser              parser.Parser
+	watchInterval       time.Duration
+	confirmQuit         bool
+	inline              bool
+	quitting            bool
+	confirmingQuit      bool
+	openedFile          string
+	testsDir            string
+	selectedFile        string
+	selectedMatch       string
+	openSelected        bool
+	openIndex           int
+	sortMode            SortMode
+	limit               int
+	threshold           float64
+	targets             targets.Targets
+	tree                bool
+	fullPaths           bool
+	zeroOnly            bool
+	packageDir          string
+	typeAhead           string
+	typeAheadActive     bool
+	typeAheadID         int
+	typeAheadTimeout    time.Duration
+	hideGenerated       bool
+	excludeTestFiles    bool
+	staleCheck          bool
+	ignoreComments      bool
+	workers             int
+	stale               map[string]bool
+	sourceCheck         bool
+	missing             map[string]bool
+	gitInfo             bool
+	minimap             bool
+	gitInfoByFile       map[string]string
+	bookmarks           map[string]bool
+	bars                bool
+	collapsedDirs       map[string]bool
+	syntax              bool
+	syntaxTheme         string
+	heatmap             bool
+	patterns            bool
+	legend              bool
+	uncoveredOnly       bool
+	diffOnly            bool
+	diffView            bool
+	foldContext         int
+	color               bool
+	clipboard           Clipboard
+	browser             Browser
+	repoURLTemplate     string
+	modulePath          string
 	requestedFiles      map[string]bool
+	fileFilter          *regexp.Regexp
+	includeGlobs        []string
+	excludeGlobs        []string
+	excludedFiles       int
+	coverMode           string
+	packages            []string
+	testCommand         string
+	testsRunning        bool
 	filteredLinesByFile map[string][]int
+	diffHunksByFile     map[string][]codeview.DiffHunk
+	foldsByFile         map[string][]int
 
 	activeView viewName
-	helpState  helpState
+	showHelp   bool
+	width      int
+	height     int
 	ready      bool
 
+	// loading is set until the profile is parsed
+	loading bool
+	spinner spinner.Model
+
 	err errorview.Model
 }
 
 // Init implements tea.Model.
+// The profile is parsed in the background, and a spinner is displayed until
+// it is loaded.
 func (m *Model) Init() tea.Cmd {
-	return m.loadProfiles(m.codeRoot, m.profileFilename)
+	if m.watchInterval > 0 {
+		return tea.Batch(m.loadProfiles(), m.spinner.Tick, m.statProfile())
+	}
+
+	return tea.Batch(m.loadProfiles(), m.spinner.Tick)
 }
 
 // Update implements tea.Model.
@@ -97,14 +227,58 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		return m.updateWindowSize(msg.Width, msg.Height)
 
-	case []*cover.Profile:
+	case profilesLoadedMsg:
 		return m.onProfilesLoaded(msg)
 
+	case spinner.TickMsg:
+		return m.onSpinnerTick(msg)
+
 	case fileContents:
 		return m.onFileContentLoaded(msg)
 
+	case funcsLoadedMsg:
+		return m.onFuncsLoaded(msg)
+
+	case testsLoadedMsg:
+		return m.onTestsLoaded(msg)
+
+	case editorFinishedMsg:
+		return m.onEditorFinished(msg)
+
+	case testsFinishedMsg:
+		return m.onTestsFinished(msg)
+
+	case profileStatMsg:
+		return m.onProfileStat(msg)
+
+	case profilesReloadedMsg:
+		return m.onProfilesReloaded(msg)
+
+	case fileReloadedMsg:
+		return m.onFileReloaded(msg)
+
+	case recolorizedMsg:
+		return m.onRecolorized(msg)
+
+	case gitInfoMsg:
+		return m.onGitInfo(msg)
+
+	case statusMsg:
+		return m, m.newStatusMessage(string(msg))
+
+	case typeAheadResetMsg:
+		return m.onTypeAheadReset(msg)
+
+	case reloadFailedMsg:
+		return m, m.newStatusMessage(fmt.Sprintf("Reload failed: %v", msg.error))
+
 	case tea.KeyMsg:
-		if m, cmd := m.onKeyPressed(msg.String()); m != nil {
+		if m, cmd := m.onKeyPressed(msg); m != nil {
+			return m, cmd
+		}
+
+	case tea.MouseMsg:
+		if m, cmd := m.onMouse(msg); m != nil {
 			return m, cmd
 		}
 
@@ -116,9 +290,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch m.activeView {
 	case activeViewList:
-		m.list, cmd = m.list.Update(msg)
+		m.updatePages(func() { m.list, cmd = m.list.Update(msg) })
 	case activeViewCode:
 		m.code, cmd = m.code.Update(msg)
+	case activeViewFuncs:
+		m.funcs, cmd = m.funcs.Update(msg)
+	case activeViewBlocks:
+		m.blocks, cmd = m.blocks.Update(msg)
+	case activeViewTests:
+		m.tests, cmd = m.tests.Update(msg)
 	case activeViewError:
 		m.err, cmd = m.err.Update(msg)
 	}
@@ -128,6 +308,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View implements tea.Model.
 func (m *Model) View() string {
+	if m.loading {
+		return m.loadingView()
+	}
+
 	if !m.ready {
 		return "Initializing..."
 	}
@@ -136,12 +320,41 @@ func (m *Model) View() string {
 		return m.err.View()
 	}
 
+	// the last frame would otherwise stay in the scrollback
+	if m.inline && m.quitting {
+		return ""
+	}
+
+	if m.confirmingQuit {
+		return m.confirmQuitView()
+	}
+
+	if m.showHelp {
+		return m.helpView()
+	}
+
 	if m.isCodeView() {
 		return m.code.View()
 	}
 
+	if m.isFuncsView() {
+		return m.funcs.View()
+	}
+
+	if m.isBlocksView() {
+		return m.blocks.View()
+	}
+
+	if m.isTestsView() {
+		return m.tests.View()
+	}
+
 	if m.isListView() {
-		return m.list.View()
+		if len(m.items) == 0 {
+			return m.emptyListView()
+		}
+
+		return lipgloss.JoinVertical(lipgloss.Left, m.headerView(), m.list.View())
 	}
 
 	return "Unknown view"
@@ -151,6 +364,14 @@ func (m *Model) isCodeView() bool {
 	return m.activeView == activeViewCode
 }
 
+func (m *Model) isFuncsView() bool {
+	return m.activeView == activeViewFuncs
+}
+
+func (m *Model) isTestsView() bool {
+	return m.activeView == activeViewTests
+}
+
 func (m *Model) isListView() bool {
 	return m.activeView == activeViewList
 }
@@ -160,75 +381,273 @@ func (m *Model) isErrorView() bool {
 }
 
 func (m *Model) updateWindowSize(width, height int) (tea.Model, tea.Cmd) {
+	m.width = width
+	m.height = height
+
 	if !m.ready {
 		m.code = codeview.New(width, height)
+		m.code.SetFoldContext(m.foldContext)
+		m.code.SetUncoveredOnly(m.uncoveredOnly)
+		m.code.SetDiffView(m.diffView)
+		m.code.SetMinimap(m.minimap)
+
+		if m.diffOnly {
+			m.code.SetFilterContext(m.foldContext)
+		}
+
+		m.funcs = funcview.New(width, height)
+		m.blocks = blockview.New(width, height)
+		m.tests = testview.New(width, height)
 		m.ready = true
 	}
 
-	m.code.SetWidth(width)
-	m.code.SetHeight(height)
+	m.code.SetSize(width, height)
 
-	m.list.SetWidth(width)
-	m.list.SetHeight(height - 1)
+	m.funcs.SetWidth(width)
+	m.funcs.SetHeight(height)
 
-	return m, nil
+	m.blocks.SetWidth(width)
+	m.blocks.SetHeight(height)
+
+	m.tests.SetWidth(width)
+	m.tests.SetHeight(height)
+
+	m.updatePages(func() {
+		m.list.SetWidth(width)
+		m.list.SetHeight(height - 1 - headerHeight)
+	})
+	return m, m.selectRequestedFile()
 }
 
 func (m *Model) onError(err error) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	// missing source files don't stop the program, other files might be
+	// available
+	var notFound errSourceFileNotFound
+	if errors.As(err, &notFound) {
+		return m.onSourceNotFound()
+	}
+
 	m.err.SetError(err)
 	m.activeView = activeViewError
 
 	return m, nil
 }
 
-func (m *Model) onProfilesLoaded(profiles []*cover.Profile) (tea.Model, tea.Cmd) {
-	if len(profiles) == 0 {
+func (m *Model) onProfilesLoaded(msg profilesLoadedMsg) (tea.Model, tea.Cmd) {
+	m.loading = false
+
+	// when files are skipped, the list explains why none are left
+	if msg.parsed == 0 {
 		return m.onError(errNoProfiles{})
 	}
 
-	if m.sortByCoverage {
-		sort.Slice(profiles, func(i, j int) bool {
-			return percentCovered(profiles[i]) < percentCovered(profiles[j])
-		})
+	cmd := m.setProfiles(msg)
+
+	return m, tea.Batch(cmd, m.selectRequestedFile())
+}
+
+// selectRequestedFile selects the file set using WithSelectedFile, or the one
+// matching WithSelectedMatch, once both the files and the size of the list
+// are known. With WithOpenSelected, it returns the command opening the file.
+// Otherwise, the file set using WithOpenIndex is opened, if any.
+func (m *Model) selectRequestedFile() tea.Cmd {
+	if !m.ready || len(m.items) == 0 {
+		return nil
+	}
+
+	if index := m.openIndex; index >= 0 && !(m.openSelected && m.selectedFile != "") {
+		m.openIndex, m.selectedFile = -1, ""
+		return m.openFileAt(index)
+	}
+
+	if m.selectedMatch != "" {
+		match := m.selectedMatch
+		m.selectedMatch, m.selectedFile = "", ""
+
+		return m.selectMatch(match)
+	}
+
+	if m.selectedFile == "" {
+		return nil
+	}
+
+	selected := m.selectedFile
+	m.selectItem(selected)
+	m.selectedFile = ""
+
+	if !m.openSelected || selectedKey(m.list.SelectedItem()) != selected {
+		return nil
+	}
+
+	return m.openSelectedFile()
+}
+
+// selectMatch selects the first file of the list whose name includes the
+// substring, without opening it. Without such files, the top of the list is
+// selected.
+func (m *Model) selectMatch(substring string) tea.Cmd {
+	for i, item := range m.list.Items() {
+		if f, ok := item.(*coverProfile); ok && strings.Contains(f.profile.FileName, substring) {
+			m.list.Select(i)
+			return nil
+		}
+	}
+
+	m.list.Select(0)
+
+	return m.list.NewStatusMessage(fmt.Sprintf("No files matching %q", substring))
+}
+
+// openFileAt opens the file at the index of the displayed files, skipping the
+// directories of the tree.
+func (m *Model) openFileAt(index int) tea.Cmd {
+	for i, item := range m.list.VisibleItems() {
+		if _, ok := item.(*coverProfile); !ok {
+			continue
+		}
+
+		if index == 0 {
+			m.list.Select(i)
+			return m.openSelectedFile()
+		}
+
+		index--
+	}
+
+	return nil
+}
+
+func (m *Model) setProfiles(msg profilesLoadedMsg) tea.Cmd {
+	m.items = make([]list.Item, len(msg.profiles))
+	m.compareProfiles = msg.compare
+	m.excludedFiles = msg.excluded
+	m.stale = msg.stale
+
+	if msg.missing != nil {
+		m.missing = msg.missing
 	}
+	m.modulePath = msg.module
+	m.coverMode = report.Mode(msg.profiles)
+
+	for i, p := range msg.profiles {
+		covered, total := m.statements(p)
 
-	m.items = make([]list.Item, len(profiles))
+		var percentage float64
+		if total > 0 {
+			percentage = float64(covered) / float64(total) * 100
+		}
 
-	for i, p := range profiles {
-		// package name should already be set
-		p.FileName = strings.TrimPrefix(p.FileName, m.detectedPackageName+"/")
 		m.items[i] = &coverProfile{
 			profile:    p,
-			percentage: percentCovered(p),
+			percentage: percentage,
+			covered:    covered,
+			total:      total,
+			fullName:   msg.fullNames[p.FileName],
+			source:     msg.sources[p.FileName],
+			compare:    m.compareWith(p.FileName),
+			generated:  msg.generated[p.FileName],
+			stale:      msg.stale[p.FileName],
+			missing:    m.missing[p.FileName],
 		}
 	}
 
-	return m, m.list.SetItems(m.items)
+	m.setItemNames()
+
+	var cmd tea.Cmd
+
+	m.updatePages(func() { cmd = m.list.SetItems(m.listItems()) })
+
+	if m.isModeOverridden(msg.profiles) {
+		status := fmt.Sprintf("Profile mode %s overrides -mode %s", m.coverMode, m.mode)
+		cmd = tea.Batch(cmd, m.newStatusMessage(status))
+	}
+
+	return cmd
+}
+
+// isModeOverridden reports whether the mode line of the profile differs
+// from the mode assumed for profiles without one.
+func (m *Model) isModeOverridden(profiles []*cover.Profile) bool {
+	mode := report.Mode(profiles)
+
+	return m.mode != "" && mode != "" && mode != m.mode
 }
 
 func (m *Model) onFileContentLoaded(content []string) (tea.Model, tea.Cmd) {
+	if m.missing[m.openedFile] {
+		m.setMissing(m.openedFile, false)
+	}
+
 	m.code.SetContent(content)
 	m.activeView = activeViewCode
 
 	return m, nil
 }
 
-func (m *Model) onKeyPressed(key string) (tea.Model, tea.Cmd) {
+func (m *Model) onKeyPressed(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	keys := DefaultKeyMap
+
+	// addresses are built from the path of the module
+	keys.OpenBrowser.SetEnabled(m.modulePath != "")
+
 	// allow error model to process the keys
 	if m.isErrorView() {
 		return nil, nil
 	}
 
-	// don't match any of the keys below if we're actively filtering.
+	if m.confirmingQuit {
+		return m.onConfirmQuitKey(msg)
+	}
+
+	// the help overlay hides everything else, so it only handles its own keys
+	if m.showHelp {
+		switch {
+		case key.Matches(msg, keys.Quit):
+			return m, m.quit(msg)
+		case key.Matches(msg, keys.Help), key.Matches(msg, keys.Back):
+			m.showHelp = false
+		}
+
+		return m, nil
+	}
+
+	// don't match any of the keys below if we're actively filtering, except
+	// for "enter": it accepts the filter and opens the top match right away.
 	if m.list.FilterState() == list.Filtering {
+		if !key.Matches(msg, keys.Open) {
+			return nil, nil
+		}
+
+		var cmd tea.Cmd
+
+		m.updatePages(func() { m.list, cmd = m.list.Update(msg) })
+
+		return m, tea.Batch(cmd, m.activateSelected())
+	}
+
+	// the code view handles all the keys while searching or after the fold
+	// key, and "esc" clears the search before going back
+	if m.isCodeView() && (m.code.Searching() || m.code.FoldPending() ||
+		m.code.SearchQuery() != "" && key.Matches(msg, keys.Back)) {
 		return nil, nil
 	}
 
-	switch key {
-	case "ctrl+c", "q":
-		return m, tea.Quit
+	if cmd, ok := m.onTypeAhead(msg); ok {
+		return m, cmd
+	}
+
+	switch {
+	case key.Matches(msg, keys.Quit):
+		return m, m.quit(msg)
+
+	case key.Matches(msg, keys.Back):
+		if m.isFuncsView() || m.isBlocksView() || m.isTestsView() {
+			m.activeView = activeViewCode
+			return m, nil
+		}
 
-	case "esc":
 		if m.isCodeView() {
 			m.activeView = activeViewList
 			return m, nil
@@ -241,100 +660,479 @@ func (m *Model) onKeyPressed(key string) (tea.Model, tea.Cmd) {
 			}
 		}
 
-	case "enter":
-		item, ok := m.list.SelectedItem().(*coverProfile)
-		if ok {
-			m.code.SetTitle(item.profile.FileName)
+	case key.Matches(msg, keys.Open):
+		if m.isTestsView() {
+			return m, m.openTest()
+		}
 
-			filteredInFile := m.filteredLinesByFile[item.profile.FileName]
-			m.code.SetFilteredLines(filteredInFile)
+		return m, m.activateSelected()
 
-			adjustedFileName := path.Join(m.codeRoot, item.profile.FileName)
+	case key.Matches(msg, keys.Expand):
+		if m.isListView() {
+			if d, ok := m.list.SelectedItem().(*dirItem); ok {
+				return m, m.toggleDir(d)
+			}
 
-			return m, loadFile(adjustedFileName, item.profile)
+			return m, nil
 		}
 
-		return m, nil
+	case key.Matches(msg, keys.Tree):
+		if m.isListView() {
+			m.tree = !m.tree
+			m.list.SetDelegate(m.delegate())
+
+			return m, m.refreshList()
+		}
+
+	case key.Matches(msg, keys.Paths):
+		if m.isListView() {
+			return m, m.togglePaths()
+		}
+
+	case key.Matches(msg, keys.Sort):
+		if m.isListView() {
+			return m, m.cycleSortMode()
+		}
+
+	case key.Matches(msg, keys.Zero):
+		if m.isListView() {
+			return m, m.toggleZeroOnly()
+		}
+
+	case key.Matches(msg, keys.Generated):
+		if m.isListView() {
+			return m, m.toggleGenerated()
+		}
+
+	case key.Matches(msg, keys.Package):
+		if m.isListView() || m.isCodeView() {
+			return m, m.togglePackageFilter()
+		}
+
+	case key.Matches(msg, keys.Bars):
+		if m.isListView() {
+			return m, m.toggleBars()
+		}
 
-	case "?":
-		m.toggleHelp()
+	case key.Matches(msg, keys.Bookmark):
+		if m.isListView() || m.isCodeView() {
+			return m, m.toggleBookmark()
+		}
+
+	case key.Matches(msg, keys.NextBookmark):
+		if m.isListView() || m.isCodeView() {
+			return m, m.nextBookmark()
+		}
+
+	case key.Matches(msg, keys.Help):
+		m.showHelp = true
 		return m, nil
+
+	case key.Matches(msg, keys.Compare):
+		if m.isComparing() && (m.isListView() || m.isCodeView()) {
+			return m, m.swapProfiles()
+		}
+
+	case key.Matches(msg, keys.Export):
+		if m.isCodeView() {
+			return m, m.exportOpenedFile()
+		}
+
+	case key.Matches(msg, keys.Syntax):
+		if m.isCodeView() || m.isListView() {
+			return m, m.toggleSyntax()
+		}
+
+	case key.Matches(msg, keys.Heatmap):
+		if m.isCodeView() || m.isListView() {
+			return m, m.toggleHeatmap()
+		}
+
+	case key.Matches(msg, keys.Legend):
+		if m.isCodeView() {
+			return m, m.toggleLegend()
+		}
+
+	case key.Matches(msg, keys.CopyPath):
+		return m, m.copyPath()
+
+	case key.Matches(msg, keys.CopyLines):
+		if m.isCodeView() || m.isListView() {
+			return m, m.copyUncoveredLines()
+		}
+
+	case key.Matches(msg, keys.OpenEditor):
+		if m.isCodeView() {
+			return m, m.openInEditor()
+		}
+
+	case key.Matches(msg, keys.OpenBrowser):
+		if m.isListView() || m.isCodeView() {
+			return m, m.openInBrowser()
+		}
+
+	case key.Matches(msg, keys.RunTests):
+		if m.isListView() || m.isCodeView() {
+			return m, m.runTests()
+		}
+
+	case key.Matches(msg, keys.Tests):
+		if m.isCodeView() {
+			if m.format != parser.FormatGo {
+				return m, m.newStatusMessage("Tests are only available for Go code")
+			}
+
+			return m, m.loadTests()
+		}
+
+		if m.isTestsView() {
+			m.activeView = activeViewCode
+			return m, nil
+		}
+
+	case key.Matches(msg, keys.Blocks):
+		if m.isCodeView() {
+			m.showBlocks()
+			return m, nil
+		}
+
+		if m.isBlocksView() {
+			m.activeView = activeViewCode
+			return m, nil
+		}
+
+	case key.Matches(msg, keys.Funcs):
+		if m.isCodeView() {
+			// functions are only known in Go code
+			if m.format != parser.FormatGo {
+				return m, m.newStatusMessage("Functions are only available for Go code")
+			}
+
+			return m, m.loadFuncs()
+		}
+
+		if m.isFuncsView() {
+			m.activeView = activeViewCode
+			return m, nil
+		}
 	}
 
 	return nil, nil
 }
 
-func (m *Model) toggleHelp() {
-	// manage help state globally: allow to extend or hide completely
-	switch m.helpState {
-	case helpStateHidden:
-		m.helpState = helpStateShort
+func (m *Model) openSelectedFile() tea.Cmd {
+	item, ok := m.list.SelectedItem().(*coverProfile)
+	if !ok {
+		return nil
+	}
+
+	// folds are remembered for every file until the program exits
+	if m.openedFile != "" {
+		if m.foldsByFile == nil {
+			m.foldsByFile = map[string][]int{}
+		}
+
+		m.foldsByFile[m.openedFile] = m.code.Folds()
+	}
+
+	m.openedFile = item.profile.FileName
+	m.code.SetTitle(item.profile.FileName)
+	m.code.SetFolds(m.foldsByFile[item.profile.FileName])
 
-		m.list.Help.ShowAll = false
-		m.list.SetShowHelp(true)
+	m.code.SetDiffHunks(m.diffHunksByFile[item.profile.FileName])
 
-		m.code.SetShowFullHelp(false)
-		m.code.SetShowHelp(true)
-	case helpStateShort:
-		m.helpState = helpStateFull
+	filteredInFile := m.filteredLinesByFile[item.profile.FileName]
+	m.code.SetFilteredLines(filteredInFile)
+	m.code.SetUncoveredBlocks(uncoveredBlocks(item.profile))
+	m.code.SetCoveredBlocks(coveredBlocks(item.profile))
+	m.setCodeCoverage(item.profile)
+	m.setLineDelta(item.profile)
+	m.setLegend(item.profile)
+	m.setStaleWarning(item.profile)
 
-		m.list.Help.ShowAll = true
-		m.list.SetShowHelp(true)
+	if cmd := m.loadGitInfo(item.profile.FileName); cmd != nil {
+		return tea.Batch(m.loadFile(item.profile), cmd)
+	}
 
-		m.code.SetShowFullHelp(true)
-		m.code.SetShowHelp(true)
-	case helpStateFull:
-		m.helpState = helpStateHidden
+	return m.loadFile(item.profile)
+}
 
-		m.list.Help.ShowAll = false
-		m.list.SetShowHelp(false)
+// loadFile loads the source of the profile, colorized according to the
+// current settings.
+func (m *Model) loadFile(profile *cover.Profile) tea.Cmd {
+	return loadFile(m.sourceFile(profile.FileName), profile, m.lineMarks(), m.syntaxStyle(), m.heatmapFor(profile))
+}
 
-		m.code.SetShowFullHelp(false)
-		m.code.SetShowHelp(false)
+// heatmapFor returns the heatmap of the profile if it is enabled.
+func (m *Model) heatmapFor(profile *cover.Profile) *heatmap {
+	if !m.heatmapEnabled() {
+		return nil
 	}
+
+	return newHeatmap(profile)
 }
 
-func (m *Model) loadProfiles(codeRoot, profileFilename string) tea.Cmd {
+func (m *Model) loadProfiles() tea.Cmd {
+	loader := m.loader()
+
 	return func() tea.Msg {
-		gomodFile := path.Join(codeRoot, "go.mod")
+		msg, err := loader.readProfiles()
+		if err != nil {
+			return err
+		}
 
-		profilesFile := path.Join(codeRoot, profileFilename)
-		if strings.HasPrefix(profileFilename, "/") {
-			profilesFile = profileFilename
+		if msg.compare, err = loader.readCompareProfiles(); err != nil {
+			return err
 		}
 
-		pkg, err := determinePackageName(gomodFile)
-		if err != nil {
-			return fmt.Errorf("failed to determine package name: %w", err)
+		return msg
+	}
+}
+
+// loader returns a copy of the model for the commands reading the profiles.
+// They run on another goroutine while Update keeps changing the model, for
+// example its sort order, so they only read the settings from the copy.
+func (m *Model) loader() *Model {
+	loader := *m
+
+	return &loader
+}
+
+// OpenedFile returns the name of the last file opened in the code view, or an
+// empty string if no file was opened.
+func (m *Model) OpenedFile() string {
+	return m.openedFile
+}
+
+// OpenedPath returns the path of the source of the last file opened in the
+// code view, or an empty string if no file was opened.
+func (m *Model) OpenedPath() string {
+	if m.openedFile == "" {
+		return ""
+	}
+
+	return m.sourceFile(m.openedFile)
+}
+
+// LoadProfiles reads and parses the coverage profile configured for this
+// model. Only the requested files are returned, if any were requested, in the
+// order they should be displayed. File names are relative to the module root.
+func (m *Model) LoadProfiles() ([]*cover.Profile, error) {
+	msg, err := m.readProfiles()
+	if err != nil || !m.hideGenerated {
+		return msg.profiles, err
+	}
+
+	profiles := make([]*cover.Profile, 0, len(msg.profiles))
+
+	for _, p := range msg.profiles {
+		if !msg.generated[p.FileName] {
+			profiles = append(profiles, p)
 		}
+	}
 
-		profiles, err := cover.ParseProfiles(profilesFile)
-		if err != nil {
-			if errors.Is(err, os.ErrNotExist) {
-				return errNoCoverageFile{err}
-			}
+	return profiles, nil
+}
+
+// readProfiles loads the profiles like LoadProfiles, and also returns the
+// names of the files as they appear in the profile, keyed by the names
+// relative to the module root, and the number of files excluded by globs.
+func (m *Model) readProfiles() (profilesLoadedMsg, error) {
+	gomodFile := path.Join(m.codeRoot, "go.mod")
+	profilesFile := m.profilePath()
+
+	pkg, err := determinePackageName(gomodFile)
+	if err != nil {
+		// reports in other formats can come from projects without go.mod
+		var notFound errGoModNotFound
+		if m.format == parser.FormatGo || !errors.As(err, &notFound) {
+			return profilesLoadedMsg{}, fmt.Errorf("failed to determine package name: %w", err)
+		}
+	}
+
+	profiles, err := m.parseProfiles(profilesFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return profilesLoadedMsg{}, errNoCoverageFile{err}
+		}
+
+		return profilesLoadedMsg{}, errInvalidCoverageFile{err}
+	}
+
+	if m.isModeOverridden(profiles) {
+		log.Printf("profile mode %q overrides -mode %q", report.Mode(profiles), m.mode)
+	}
+
+	finalProfiles := make([]*cover.Profile, 0, len(profiles))
+	fullNames := make(map[string]string, len(profiles))
+	allFilesRequested := m.requestedFiles == nil
+	excluded := 0
+
+	patterns := make([]pkgpattern.Pattern, len(m.packages))
+	matchedPatterns := make([]bool, len(m.packages))
+
+	for i, pattern := range m.packages {
+		patterns[i] = pkgpattern.New(pattern, pkg)
+	}
+
+	for _, p := range profiles {
+		p.FileName = m.stripPrefix(p.FileName)
 
-			return errInvalidCoverageFile{err}
+		if len(patterns) > 0 && !matchPackage(patterns, matchedPatterns, path.Dir(p.FileName)) {
+			log.Println("skipping package of", p.FileName)
+			continue
 		}
 
-		finalProfiles := make([]*cover.Profile, 0, len(profiles))
-		allFilesRequested := len(m.requestedFiles) == 0
+		fullName := p.FileName
 
-		for _, p := range profiles {
+		if pkg != "" {
 			p.FileName = strings.TrimPrefix(p.FileName, pkg+"/")
+		}
 
-			if !allFilesRequested {
-				if _, ok := m.requestedFiles[p.FileName]; !ok {
-					log.Println("skipping", p.FileName)
-					continue
-				}
+		if !allFilesRequested {
+			if _, ok := m.requestedFiles[p.FileName]; !ok {
+				log.Println("skipping", p.FileName)
+				continue
 			}
+		}
+
+		if m.fileFilter != nil && !m.fileFilter.MatchString(p.FileName) {
+			log.Println("filtering out", p.FileName)
+			continue
+		}
+
+		if m.excludeTestFiles && isTestFile(p.FileName) {
+			log.Println("skipping test file", p.FileName)
+			continue
+		}
+
+		if !m.matchGlobs(p.FileName) {
+			log.Println("excluding", p.FileName)
+
+			excluded++
+
+			continue
+		}
+
+		fullNames[p.FileName] = fullName
+		finalProfiles = append(finalProfiles, p)
+	}
+
+	for i, matched := range matchedPatterns {
+		if !matched {
+			return profilesLoadedMsg{}, errNoPackageMatches{fmt.Errorf("package pattern %q matches no files", m.packages[i])}
+		}
+	}
+
+	sources := m.resolveSources(finalProfiles)
+	generated := m.analyzeSources(finalProfiles, sources)
+	finalProfiles = m.dropIgnoredProfiles(finalProfiles)
+
+	sortProfiles(finalProfiles, m.sortMode)
+
+	if m.limit > 0 && len(finalProfiles) > m.limit {
+		log.Println("limiting to the first", m.limit, "files")
+
+		finalProfiles = finalProfiles[:m.limit]
+	}
+
+	return profilesLoadedMsg{
+		module:    pkg,
+		stale:     m.staleFiles(profilesFile, finalProfiles, sources),
+		missing:   m.missingFiles(finalProfiles, sources),
+		profiles:  finalProfiles,
+		fullNames: fullNames,
+		sources:   sources,
+		excluded:  excluded,
+		parsed:    len(profiles),
+		generated: generated,
+	}, nil
+}
+
+// matchPackage reports whether the import path matches any of the patterns,
+// marking all the patterns that match it.
+func matchPackage(patterns []pkgpattern.Pattern, matched []bool, importPath string) bool {
+	found := false
+
+	for i, pattern := range patterns {
+		if pattern.Match(importPath) {
+			matched[i] = true
+			found = true
+		}
+	}
+
+	return found
+}
+
+func (m *Model) profilePath() string {
+	return m.resolvePath(m.profileFilename)
+}
+
+// resolvePath returns the path of the profile relative to the code root,
+// unless it is absolute.
+func (m *Model) resolvePath(filename string) string {
+	return ResolvePath(m.codeRoot, filename)
+}
+
+// ResolvePath returns the path of the profile relative to the code root,
+// unless it is absolute.
+func ResolvePath(codeRoot, filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+
+	return filepath.Join(codeRoot, filename)
+}
+
+// newParser returns the parser set using WithParser, or the one of the
+// format.
+func (m *Model) newParser() (parser.Parser, error) {
+	if m.parser != nil {
+		return m.parser, nil
+	}
+
+	return parser.New(m.format, parser.WithMode(m.mode))
+}
+
+func (m *Model) parseProfiles(profilesFile string) ([]*cover.Profile, error) {
+	p, err := m.newParser()
+	if err != nil {
+		return nil, err
+	}
 
-			finalProfiles = append(finalProfiles, p)
+	if m.profileContent != nil {
+		r, err := parser.Decompress(bytes.NewReader(m.profileContent), "")
+		if err != nil {
+			return nil, err
 		}
 
-		return finalProfiles
+		return p.Parse(r)
 	}
+
+	return m.parseProfileFile(profilesFile)
+}
+
+// parseProfileFile parses the profile in the file, even if the content of
+// the profile is set.
+func (m *Model) parseProfileFile(profilesFile string) ([]*cover.Profile, error) {
+	p, err := m.newParser()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(profilesFile) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	r, err := parser.Decompress(f, profilesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Parse(r)
 }
 
 func determinePackageName(gomodFile string) (string, error) {
@@ -355,8 +1153,72 @@ func determinePackageName(gomodFile string) (string, error) {
 
 type fileContents []string
 
+// profilesLoadedMsg is sent when the coverage profile is loaded. The names of
+// the files in the profile are kept to display full paths.
+type profilesLoadedMsg struct {
+	profiles  []*cover.Profile
+	fullNames map[string]string
+
+	// sources are the resolved paths of the sources of the files
+	sources map[string]string
+
+	// compare is the profile the coverage is compared with, if any
+	compare map[string]*cover.Profile
+
+	// excluded is the number of files that don't match the globs
+	excluded int
+
+	// parsed is the number of files in the profile, before any of them are
+	// skipped
+	parsed int
+
+	// generated are the names of the generated files
+	generated map[string]bool
+
+	// stale are the names of the files modified after the profile
+	stale map[string]bool
+
+	// missing are the names of the files whose sources are not found, if
+	// they are checked
+	missing map[string]bool
+
+	// module is the path of the module of the files, if known
+	module string
+}
+
+// statusMsg is a short message to be displayed in the active view.
+type statusMsg string
+
+func (m *Model) openedProfile() *cover.Profile {
+	for _, item := range m.items {
+		if p, ok := item.(*coverProfile); ok && p.profile.FileName == m.openedFile {
+			return p.profile
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) exportOpenedFile() tea.Cmd {
+	profile := m.openedProfile()
+	if profile == nil {
+		return nil
+	}
+
+	src := m.sourceFile(profile.FileName)
+	dst := path.Join(m.codeRoot, export.Filename(profile.FileName))
+
+	return func() tea.Msg {
+		if err := export.WriteFile(dst, src, profile); err != nil {
+			return statusMsg(fmt.Sprintf("Export failed: %v", err))
+		}
+
+		return statusMsg(fmt.Sprintf("Exported to %s", dst))
+	}
+}
+
 // nolint: gosec
-func loadFile(filename string, profile *cover.Profile) tea.Cmd {
+func loadFile(filename string, profile *cover.Profile, marks *lineMarks, syntax *chroma.Style, heat *heatmap) tea.Cmd {
 	return func() tea.Msg {
 		f, err := os.Open(filename)
 		if err != nil {
@@ -377,7 +1239,7 @@ func loadFile(filename string, profile *cover.Profile) tea.Cmd {
 			lines = append(lines, scanner.Text())
 		}
 
-		highlightedText, err := colorize(lines, profile)
+		highlightedText, err := colorize(lines, profile, marks, highlightSyntax(filename, lines, syntax), heat)
 		if err != nil {
 			return errMismatchingProfile{fmt.Errorf("could not colorize file %s: %w", filename, err)}
 		}
@@ -386,7 +1248,16 @@ func loadFile(filename string, profile *cover.Profile) tea.Cmd {
 	}
 }
 
-func colorize(lines []string, profile *cover.Profile) (contents fileContents, err error) {
+// colorize highlights covered and uncovered lines, and the lines where some
+// blocks were executed and others were not. With marks, every line is also
+// prefixed with a symbol or a pattern, so that coverage is visible without
+// colors.
+// With syntax segments, covered code is highlighted using them instead of a
+// single color, while uncovered code keeps its color to stay readable. With a
+// heatmap, covered code is shaded by hit count instead.
+func colorize(
+	lines []string, profile *cover.Profile, marks *lineMarks, syntax [][]segment, heat *heatmap,
+) (contents fileContents, err error) {
 	defer func() {
 		if rr := recover(); rr != nil {
 			err = fmt.Errorf("%s", rr)
@@ -395,27 +1266,48 @@ func colorize(lines []string, profile *cover.Profile) (contents fileContents, er
 
 	buf := make(fileContents, 0, len(lines))
 
+	if marks == nil {
+		marks = &lineMarks{}
+	}
+
+	coverage := linesCoverage(profile)
+
 	for lineIdx, blockIdx := 0, 0; lineIdx < len(lines); lineIdx++ {
 		line, block := lines[lineIdx], profile.Blocks[blockIdx]
+		partial := coverage[lineIdx+1].partial()
+
+		coverageStyle, coverageMark := styles.CurrentTheme.UncoveredLine, marks.uncovered
+
+		switch {
+		case partial:
+			coverageStyle, coverageMark = styles.CurrentTheme.PartialLine, marks.partial
+		case block.Count > 0:
+			coverageStyle, coverageMark = styles.CurrentTheme.CoveredLine, marks.covered
+
+			if heat != nil {
+				coverageStyle = heat.style(block.Count)
+			}
+		}
 
-		coverageStyle := styles.CurrentTheme.UncoveredLine
-		if block.Count > 0 {
-			coverageStyle = styles.CurrentTheme.CoveredLine
+		render := func(from int) string { return coverageStyle.Render(line[from:]) }
+		if block.Count > 0 && !partial && heat == nil && lineIdx < len(syntax) {
+			segments := syntax[lineIdx]
+			render = func(from int) string { return renderSegments(segments, from) }
 		}
 
 		adjustedStartLine, adjustedEndLine := block.StartLine-1, block.EndLine-1
 
 		// before the first block - not covered
 		if lineIdx < adjustedStartLine {
-			buf = append(buf, styles.CurrentTheme.NeutralLine.Render(line))
+			buf = append(buf, marks.neutral+styles.CurrentTheme.NeutralLine.Render(line))
 			continue
 		}
 
 		// first line - highlight from the start col
 		if lineIdx == adjustedStartLine {
 			uncoveredPart := styles.CurrentTheme.NeutralLine.Render(line[:block.StartCol-1])
-			coveredPart := coverageStyle.Render(line[block.StartCol-1:])
-			buf = append(buf, fmt.Sprintf("%s%s", uncoveredPart, coveredPart))
+			coveredPart := render(block.StartCol - 1)
+			buf = append(buf, fmt.Sprintf("%s%s%s", coverageMark, uncoveredPart, coveredPart))
 
 			continue
 		}
@@ -424,9 +1316,9 @@ func colorize(lines []string, profile *cover.Profile) (contents fileContents, er
 		if lineIdx >= adjustedStartLine && lineIdx <= adjustedEndLine {
 			// TODO: support end column as well
 			if block.NumStmt > 0 {
-				buf = append(buf, coverageStyle.Render(line))
+				buf = append(buf, coverageMark+render(0))
 			} else {
-				buf = append(buf, styles.CurrentTheme.NeutralLine.Render(line))
+				buf = append(buf, marks.neutral+styles.CurrentTheme.NeutralLine.Render(line))
 			}
 
 			continue
@@ -439,7 +1331,7 @@ func colorize(lines []string, profile *cover.Profile) (contents fileContents, er
 				blockIdx++
 				lineIdx--
 			} else {
-				buf = append(buf, styles.CurrentTheme.NeutralLine.Render(line))
+				buf = append(buf, marks.neutral+styles.CurrentTheme.NeutralLine.Render(line))
 			}
 		}
 	}
@@ -447,6 +1339,43 @@ func colorize(lines []string, profile *cover.Profile) (contents fileContents, er
 	return buf, nil
 }
 
+// uncoveredBlocks returns the ranges of lines with statements that were not
+// executed, in order. Overlapping and adjacent ranges are merged. Lines that
+// are only partially covered are included.
+func uncoveredBlocks(profile *cover.Profile) []codeview.LineRange {
+	return mergeBlocks(profile, func(b cover.ProfileBlock) bool { return b.Count == 0 && b.NumStmt > 0 })
+}
+
+// coveredBlocks returns the ranges of lines with statements that were
+// executed, in order, merged the same way as uncoveredBlocks.
+func coveredBlocks(profile *cover.Profile) []codeview.LineRange {
+	return mergeBlocks(profile, func(b cover.ProfileBlock) bool { return b.Count > 0 && b.NumStmt > 0 })
+}
+
+// mergeBlocks returns the merged ranges of lines of the blocks matching the
+// predicate.
+func mergeBlocks(profile *cover.Profile, match func(cover.ProfileBlock) bool) []codeview.LineRange {
+	blocks := make([]codeview.LineRange, 0, len(profile.Blocks))
+
+	for _, b := range profile.Blocks {
+		if !match(b) {
+			continue
+		}
+
+		if n := len(blocks); n > 0 && b.StartLine <= blocks[n-1].End+1 {
+			if b.EndLine > blocks[n-1].End {
+				blocks[n-1].End = b.EndLine
+			}
+
+			continue
+		}
+
+		blocks = append(blocks, codeview.LineRange{Start: b.StartLine, End: b.EndLine})
+	}
+
+	return blocks
+}
+
 // percentCovered returns, as a percentage, the fraction of the statements in
 // the profile covered by the test run.
 // In effect, it reports the coverage of a given source file.
@@ -454,8 +1383,18 @@ func colorize(lines []string, profile *cover.Profile) (contents fileContents, er
 // Taken from golang/tools repo.
 // https://github.com/golang/tools/blob/master/cmd/cover/html.go
 func percentCovered(p *cover.Profile) float64 {
-	var total, covered int64
+	covered, total := countStatements(p)
 
+	if total == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(total) * 100
+}
+
+// countStatements returns the number of covered statements in the profile,
+// and the total number of its statements.
+func countStatements(p *cover.Profile) (covered, total int64) {
 	for _, b := range p.Blocks {
 		total += int64(b.NumStmt)
 
@@ -464,9 +1403,5 @@ func percentCovered(p *cover.Profile) float64 {
 		}
 	}
 
-	if total == 0 {
-		return 0
-	}
-
-	return float64(covered) / float64(total) * 100
+	return covered, total
 }
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// onMouse handles mouse events in the list view: clicking a file opens it,
+// clicking a directory of the tree expands or collapses it, and the wheel
+// moves the selection. Other views handle mouse events on their own, for
+// example to scroll the code with the wheel.
+func (m *Model) onMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.showHelp {
+		return m, nil
+	}
+
+	if !m.isListView() {
+		return nil, nil
+	}
+
+	// the filter input is shown instead of the title while filtering
+	if m.list.FilterState() == list.Filtering {
+		return m, nil
+	}
+
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		m.list.CursorUp()
+	case tea.MouseWheelDown:
+		m.list.CursorDown()
+	case tea.MouseLeft:
+		if index, ok := m.listItemAt(msg.Y); ok {
+			m.list.Select(index)
+			return m, m.activateSelected()
+		}
+	}
+
+	return m, nil
+}
+
+// listItemAt returns the index of the visible item rendered at the provided
+// row of the list view.
+func (m *Model) listItemAt(y int) (int, bool) {
+	top := headerHeight
+
+	if m.list.ShowTitle() {
+		top += lipgloss.Height(m.list.Styles.TitleBar.Render(m.list.Styles.Title.Render(m.list.Title)))
+	}
+
+	if m.list.ShowStatusBar() {
+		top += lipgloss.Height(m.list.Styles.StatusBar.Render(""))
+	}
+
+	row := y - top
+	itemsOnPage := m.list.Paginator.ItemsOnPage(len(m.list.VisibleItems()))
+
+	if row < 0 || row >= itemsOnPage {
+		return 0, false
+	}
+
+	return m.list.Paginator.Page*m.list.Paginator.PerPage + row, true
+}
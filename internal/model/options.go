@@ -1,5 +1,14 @@
 package model
 
+import (
+	"regexp"
+	"time"
+
+	"github.com/orlangure/gocovsh/internal/codeview"
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/orlangure/gocovsh/internal/targets"
+)
+
 // Option is a function that can be used to modify the model.
 type Option func(*Model)
 
@@ -10,10 +19,342 @@ func WithProfileFilename(name string) Option {
 	}
 }
 
-// WithCoverageSorting asks for the profiles to be sorted by coverage percent instead of alphabetically.
-func WithCoverageSorting(sortByCoverage bool) Option {
+// WithProfileContent sets the contents of the coverage report to be used
+// instead of reading it from a file. When set, the profile filename is
+// ignored.
+func WithProfileContent(content []byte) Option {
+	return func(m *Model) {
+		m.profileContent = content
+	}
+}
+
+// WithCompareProfile sets the filename of the coverage report that the
+// loaded one is compared with. The change of coverage is displayed for every
+// file, and the lines whose coverage changed are marked in the code view.
+func WithCompareProfile(name string) Option {
+	return func(m *Model) {
+		m.compareFilename = name
+	}
+}
+
+// WithFormat sets the format of the coverage report. By default, it is a Go
+// coverage profile.
+func WithFormat(format parser.Format) Option {
+	return func(m *Model) {
+		m.format = format
+	}
+}
+
+// WithParser reads the coverage reports using the parser instead of the
+// built-in one of the format, for formats gocovsh doesn't know. The parser
+// is reused for every report, such as the compared one or a reloaded one.
+// Features specific to Go code still depend on the format.
+func WithParser(p parser.Parser) Option {
+	return func(m *Model) {
+		m.parser = p
+	}
+}
+
+// WithMode sets the mode assumed for Go coverage profiles without a mode
+// line. The mode line of a profile takes precedence.
+func WithMode(mode string) Option {
+	return func(m *Model) {
+		m.mode = mode
+	}
+}
+
+// WithSortMode sets the order of files in the list. By default, files are
+// sorted by path.
+func WithSortMode(mode SortMode) Option {
+	return func(m *Model) {
+		m.sortMode = mode
+	}
+}
+
+// WithLimit only keeps the first files once they are sorted, such as the
+// ones with the lowest coverage. Zero keeps all of them.
+func WithLimit(limit int) Option {
+	return func(m *Model) {
+		m.limit = limit
+	}
+}
+
+// WithThreshold sets the minimum coverage percentage. Files below it are
+// highlighted in the list. Zero disables highlighting.
+func WithThreshold(threshold float64) Option {
+	return func(m *Model) {
+		m.threshold = threshold
+	}
+}
+
+// WithTargets sets the minimum coverage percentages of the files and
+// directories matching globs. They take precedence over the threshold set
+// using WithThreshold.
+func WithTargets(t targets.Targets) Option {
+	return func(m *Model) {
+		m.targets = t
+	}
+}
+
+// WithTree nests the files of the list under their directories, displaying
+// the coverage of every directory. By default, the list is flat.
+func WithTree(tree bool) Option {
+	return func(m *Model) {
+		m.tree = tree
+	}
+}
+
+// WithHideGenerated hides the generated files, detected by their names and
+// their "Code generated ... DO NOT EDIT." headers. They can be shown again,
+// muted, from the list.
+func WithHideGenerated(hideGenerated bool) Option {
+	return func(m *Model) {
+		m.hideGenerated = hideGenerated
+	}
+}
+
+// WithBars shows a bar of the coverage next to every item of the list. They
+// can be toggled from the list.
+func WithBars(bars bool) Option {
+	return func(m *Model) {
+		m.bars = bars
+	}
+}
+
+// WithSourceCheck marks the files whose sources are not found as soon as the
+// profile is loaded. Otherwise, they are marked once they are opened.
+func WithSourceCheck(sourceCheck bool) Option {
 	return func(m *Model) {
-		m.sortByCoverage = sortByCoverage
+		m.sourceCheck = sourceCheck
+	}
+}
+
+// WithStaleCheck marks the files modified after the coverage profile was
+// written, whose coverage may no longer match their lines. It is enabled by
+// default.
+func WithStaleCheck(staleCheck bool) Option {
+	return func(m *Model) {
+		m.staleCheck = staleCheck
+	}
+}
+
+// WithIgnoreComments excludes the lines marked with //gocovsh:ignore comments,
+// or placed between //gocovsh:ignore-start and //gocovsh:ignore-end, from the
+// coverage. Every source file is read when the profile is loaded, so it is
+// disabled by default.
+func WithIgnoreComments(ignoreComments bool) Option {
+	return func(m *Model) {
+		m.ignoreComments = ignoreComments
+	}
+}
+
+// WithWorkers sets the number of the source files read at once when the
+// profile is loaded. It is the number of CPUs by default.
+func WithWorkers(workers int) Option {
+	return func(m *Model) {
+		m.workers = workers
+	}
+}
+
+// WithRepoURLTemplate sets the template of the addresses opened in the
+// browser, such as https://{module}/blob/main/{path}#L{line}. By default, the
+// package of the file is opened on pkg.go.dev.
+func WithRepoURLTemplate(template string) Option {
+	return func(m *Model) {
+		if template != "" {
+			m.repoURLTemplate = template
+		}
+	}
+}
+
+// WithBrowser sets the browser used to open the addresses of files. By
+// default, the browser of the system is used.
+func WithBrowser(b Browser) Option {
+	return func(m *Model) {
+		m.browser = b
+	}
+}
+
+// WithSelectedFile selects the file in the list once the profile is loaded.
+// If the file is not in the list, the top of the list is selected.
+func WithSelectedFile(name string) Option {
+	return func(m *Model) {
+		m.selectedFile = name
+	}
+}
+
+// WithSelectedMatch selects the first file of the list whose name includes
+// the substring once the profile is loaded, without opening it. It takes
+// precedence over WithSelectedFile.
+func WithSelectedMatch(substring string) Option {
+	return func(m *Model) {
+		m.selectedMatch = substring
+	}
+}
+
+// WithOpenIndex opens the file at the index of the list once the profile is
+// loaded, after the files are sorted and filtered: 0 opens the first one.
+// Directories of the tree are not counted. Negative indexes open nothing.
+func WithOpenIndex(index int) Option {
+	return func(m *Model) {
+		m.openIndex = index
+	}
+}
+
+// WithOpenSelected opens the file selected using WithSelectedFile right away,
+// instead of only selecting it in the list.
+func WithOpenSelected(open bool) Option {
+	return func(m *Model) {
+		m.openSelected = open
+	}
+}
+
+// WithHeatmap shades covered lines by hit count, for profiles in "count" and
+// "atomic" modes.
+func WithHeatmap(heatmap bool) Option {
+	return func(m *Model) {
+		m.heatmap = heatmap
+	}
+}
+
+// WithLegend shows the meaning of the colors of the open file in the footer,
+// or of the markers when colors are disabled.
+func WithLegend(legend bool) Option {
+	return func(m *Model) {
+		m.legend = legend
+	}
+}
+
+// WithSyntax enables syntax highlighting of covered code.
+func WithSyntax(syntax bool) Option {
+	return func(m *Model) {
+		m.syntax = syntax
+	}
+}
+
+// WithSyntaxTheme sets the name of the chroma style used for syntax
+// highlighting. By default, it is DefaultSyntaxTheme.
+func WithSyntaxTheme(name string) Option {
+	return func(m *Model) {
+		m.syntaxTheme = name
+	}
+}
+
+// WithUncoveredOnly folds the covered code in the code view, so that only the
+// uncovered blocks and the lines around them are displayed.
+func WithUncoveredOnly(uncoveredOnly bool) Option {
+	return func(m *Model) {
+		m.uncoveredOnly = uncoveredOnly
+	}
+}
+
+// WithDiffOnly measures the coverage of the changed lines, set using
+// WithFilteredLines, instead of the coverage of whole files. The changed
+// lines are displayed with the context set using WithFoldContext.
+func WithDiffOnly(diffOnly bool) Option {
+	return func(m *Model) {
+		m.diffOnly = diffOnly
+	}
+}
+
+// WithDiffHunks sets the hunks of the diff of every file, displayed in the
+// diff view.
+func WithDiffHunks(files map[string][]codeview.DiffHunk) Option {
+	return func(m *Model) {
+		m.diffHunksByFile = files
+	}
+}
+
+// WithDiffView displays the hunks set using WithDiffHunks instead of whole
+// files, with the coverage of the added lines.
+func WithDiffView(diffView bool) Option {
+	return func(m *Model) {
+		m.diffView = diffView
+	}
+}
+
+// WithMinimap shows a strip next to the code summarizing the coverage of the
+// whole file, with the lines on the screen highlighted.
+func WithMinimap(minimap bool) Option {
+	return func(m *Model) {
+		m.minimap = minimap
+	}
+}
+
+// WithFoldContext sets the number of lines displayed around uncovered blocks
+// when covered code is folded.
+func WithFoldContext(lines int) Option {
+	return func(m *Model) {
+		m.foldContext = lines
+	}
+}
+
+// WithColor enables or disables colors. Without colors, coverage is marked
+// using symbols instead: "+" for covered lines and "-" for uncovered ones.
+func WithColor(enabled bool) Option {
+	return func(m *Model) {
+		m.color = enabled
+	}
+}
+
+// WithPatterns prefixes the lines of the code view with glyphs of different
+// shapes for covered, uncovered and partially covered code, so that coverage
+// doesn't depend on colors. Without colors, they replace the markers.
+func WithPatterns(patterns bool) Option {
+	return func(m *Model) {
+		m.patterns = patterns
+	}
+}
+
+// WithClipboard sets the clipboard used to copy file paths. By default, the
+// system clipboard is used.
+func WithClipboard(c Clipboard) Option {
+	return func(m *Model) {
+		m.clipboard = c
+	}
+}
+
+// WithWatch enables reloading of the coverage profile when it changes. The
+// profile is checked for changes every interval. Zero interval disables
+// watching.
+func WithWatch(interval time.Duration) Option {
+	return func(m *Model) {
+		m.watchInterval = interval
+	}
+}
+
+// WithConfirmQuit asks to confirm quitting while the coverage profile is
+// watched, so that the session isn't lost by accident.
+func WithConfirmQuit(confirm bool) Option {
+	return func(m *Model) {
+		m.confirmQuit = confirm
+	}
+}
+
+// WithInline adapts the model to being rendered in the scrollback of the
+// terminal instead of the alternate screen: the UI is cleared on quit rather
+// than left behind.
+func WithInline(inline bool) Option {
+	return func(m *Model) {
+		m.inline = inline
+	}
+}
+
+// WithTypeAheadTimeout sets the time after the last letter typed in the list
+// when the next one starts a new prefix to jump to. Zero disables jumping to
+// the typed prefix.
+func WithTypeAheadTimeout(timeout time.Duration) Option {
+	return func(m *Model) {
+		m.typeAheadTimeout = timeout
+	}
+}
+
+// WithTestCommand sets the command that regenerates the coverage profile.
+// The arguments are separated by spaces. An empty command runs go test for
+// the packages of the module.
+func WithTestCommand(command string) Option {
+	return func(m *Model) {
+		m.testCommand = command
 	}
 }
 
@@ -24,10 +365,96 @@ func WithCodeRoot(root string) Option {
 	}
 }
 
-// WithRequestedFiles sets the list of files to be displayed.
+// WithSourceRoot sets the directory to look up source files in, when they are
+// not found in the code root.
+func WithSourceRoot(root string) Option {
+	return func(m *Model) {
+		m.sourceRoot = root
+	}
+}
+
+// WithStripPrefixes removes the prefixes from the paths of the files in the
+// profile, in order, before they are displayed or looked up. It normalizes
+// paths of build systems, such as "_/home/runner/work/project/".
+func WithStripPrefixes(prefixes []string) Option {
+	return func(m *Model) {
+		m.stripPrefixes = prefixes
+	}
+}
+
+// WithFileFilter restricts the displayed files to the ones with paths
+// matching the pattern. It narrows down the requested files, if any.
+func WithFileFilter(pattern *regexp.Regexp) Option {
+	return func(m *Model) {
+		m.fileFilter = pattern
+	}
+}
+
+// WithIncludeGlobs restricts the displayed files to the ones with paths
+// matching any of the globs, such as "internal/**/*.go". Paths are relative
+// to the module root. It narrows down the requested files, if any.
+func WithIncludeGlobs(globs []string) Option {
+	return func(m *Model) {
+		m.includeGlobs = globs
+	}
+}
+
+// WithExcludeGlobs hides the files with paths matching any of the globs, even
+// if they match the included ones.
+func WithExcludeGlobs(globs []string) Option {
+	return func(m *Model) {
+		m.excludeGlobs = globs
+	}
+}
+
+// WithExcludeTestFiles hides the Go test files, such as helpers covered by
+// the tests of other packages. It applies along with the globs.
+func WithExcludeTestFiles(exclude bool) Option {
+	return func(m *Model) {
+		m.excludeTestFiles = exclude
+	}
+}
+
+// WithGitInfo displays the last commit of the opened file next to its title.
+// It is looked up with git, once for every file.
+func WithGitInfo(gitInfo bool) Option {
+	return func(m *Model) {
+		m.gitInfo = gitInfo
+	}
+}
+
+// WithBookmarks bookmarks the files, such as the ones bookmarked in a
+// previous session.
+func WithBookmarks(names []string) Option {
+	return func(m *Model) {
+		m.bookmarks = make(map[string]bool, len(names))
+
+		for _, name := range names {
+			m.bookmarks[name] = true
+		}
+	}
+}
+
+// WithPackages restricts the displayed files to the ones of the packages
+// matching any of the patterns, such as "./internal/...". It narrows down the
+// requested files, if any. Every pattern must match some files of the
+// profile.
+func WithPackages(patterns []string) Option {
+	return func(m *Model) {
+		m.packages = patterns
+	}
+}
+
+// WithRequestedFiles sets the list of files to be displayed. A nil list means
+// that all files are displayed, while an empty list means that none are.
 func WithRequestedFiles(files []string) Option {
 	return func(m *Model) {
-		m.requestedFiles = make(map[string]bool)
+		if files == nil {
+			m.requestedFiles = nil
+			return
+		}
+
+		m.requestedFiles = make(map[string]bool, len(files))
 
 		for _, v := range files {
 			m.requestedFiles[v] = true
@@ -40,9 +467,9 @@ func WithRequestedFiles(files []string) Option {
 func WithFilteredLines(files map[string][]int) Option {
 	return func(m *Model) {
 		m.filteredLinesByFile = make(map[string][]int, len(files))
-		uniqueLines := map[int]interface{}{}
 
 		for file, lines := range files {
+			uniqueLines := map[int]interface{}{}
 			linesWithContext := make([]int, 0, len(lines))
 
 			for _, line := range lines {
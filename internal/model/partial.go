@@ -0,0 +1,41 @@
+package model
+
+import "golang.org/x/tools/cover"
+
+// lineStatus is the coverage of all the blocks with statements on a line.
+type lineStatus struct {
+	covered   bool
+	uncovered bool
+}
+
+// partial reports whether some blocks of the line were executed, and others
+// were not, for example in short-circuit conditions.
+func (s lineStatus) partial() bool {
+	return s.covered && s.uncovered
+}
+
+// linesCoverage returns the coverage of the measured lines of the profile,
+// keyed by line number. Blocks without statements are not measured.
+func linesCoverage(p *cover.Profile) map[int]lineStatus {
+	lines := map[int]lineStatus{}
+
+	for _, b := range p.Blocks {
+		if b.NumStmt == 0 {
+			continue
+		}
+
+		for line := b.StartLine; line <= b.EndLine; line++ {
+			status := lines[line]
+
+			if b.Count > 0 {
+				status.covered = true
+			} else {
+				status.uncovered = true
+			}
+
+			lines[line] = status
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// togglePaths switches the list between the full paths of the files, as they
+// appear in the profile, and the paths relative to the module root. The
+// selected file stays selected.
+func (m *Model) togglePaths() tea.Cmd {
+	m.fullPaths = !m.fullPaths
+	m.setItemNames()
+
+	status := "Showing relative paths"
+	if m.fullPaths {
+		status = "Showing full paths"
+	}
+
+	return tea.Batch(m.refreshList(), m.newStatusMessage(status))
+}
+
+// setItemNames sets the displayed names of the files according to the
+// current path mode.
+func (m *Model) setItemNames() {
+	files := make([]*coverProfile, 0, len(m.items))
+
+	for _, item := range m.items {
+		if f, ok := item.(*coverProfile); ok {
+			files = append(files, f)
+		}
+	}
+
+	if m.fullPaths {
+		for _, f := range files {
+			f.name = f.itemFullName()
+		}
+
+		return
+	}
+
+	m.setRelativeNames(files)
+}
+
+// setRelativeNames names the files relative to the module root, or to the
+// code root for absolute paths. Files that end up with the same name are
+// given the shortest suffixes of their full paths that tell them apart.
+func (m *Model) setRelativeNames(files []*coverProfile) {
+	root, err := filepath.Abs(m.codeRoot)
+	if err != nil {
+		root = ""
+	}
+
+	byName := make(map[string][]*coverProfile, len(files))
+
+	for _, f := range files {
+		f.name = relativeName(f.profile.FileName, root)
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	for _, group := range byName {
+		if len(group) > 1 {
+			disambiguate(group)
+		}
+	}
+}
+
+// relativeName trims the root from absolute file names.
+func relativeName(name, root string) string {
+	if root == "" || !filepath.IsAbs(name) {
+		return name
+	}
+
+	if rel, err := filepath.Rel(root, name); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+
+	return name
+}
+
+// disambiguate names each of the files with the same relative name using the
+// shortest suffix of its full path that no other file of the group has.
+func disambiguate(group []*coverProfile) {
+	for _, f := range group {
+		f.name = f.itemFullName()
+
+		for i := len(f.name) - 1; i > 0; i-- {
+			if f.name[i-1] != '/' {
+				continue
+			}
+
+			if suffix := f.name[i:]; isUniqueSuffix(suffix, f, group) {
+				f.name = suffix
+				break
+			}
+		}
+	}
+}
+
+func isUniqueSuffix(suffix string, f *coverProfile, group []*coverProfile) bool {
+	for _, other := range group {
+		if other != f && strings.HasSuffix("/"+other.itemFullName(), "/"+suffix) {
+			return false
+		}
+	}
+
+	return true
+}
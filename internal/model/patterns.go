@@ -0,0 +1,47 @@
+package model
+
+import "github.com/orlangure/gocovsh/internal/styles"
+
+// lineMarks prefix the lines of the code view, so that coverage is visible
+// without relying on colors. They have the same width.
+type lineMarks struct {
+	covered   string
+	uncovered string
+	partial   string
+	neutral   string
+}
+
+// patternMarks border the lines with glyphs of different shapes, which are
+// told apart in monochrome terminals and by colorblind users.
+var patternMarks = lineMarks{
+	covered:   "▌ ",
+	uncovered: "░ ",
+	partial:   "┆ ",
+	neutral:   "  ",
+}
+
+// lineMarks returns the marks of the lines of the code view, or nil if the
+// lines are only colored. Patterns take the place of the markers used
+// without colors.
+func (m *Model) lineMarks() *lineMarks {
+	if m.patterns {
+		return &patternMarks
+	}
+
+	if !m.color {
+		return &lineMarks{
+			covered:   styles.CurrentTheme.CoveredMarker,
+			uncovered: styles.CurrentTheme.UncoveredMarker,
+			partial:   styles.CurrentTheme.PartialMarker,
+			neutral:   styles.CurrentTheme.NeutralMarker,
+		}
+	}
+
+	return nil
+}
+
+// patternsLegend explains the patterns.
+func patternsLegend() string {
+	return patternMarks.covered + "covered • " + patternMarks.uncovered + "not covered • " +
+		patternMarks.partial + "partial"
+}
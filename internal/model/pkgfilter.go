@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// togglePackageFilter shows only the files of the package of the open or
+// selected file, or all the files again if the list is already narrowed to
+// a package. From the code view, it goes back to the narrowed list.
+func (m *Model) togglePackageFilter() tea.Cmd {
+	if m.packageDir != "" {
+		m.packageDir = ""
+		return tea.Batch(m.refreshList(), m.newStatusMessage("Showing all packages"))
+	}
+
+	dir := m.currentDir()
+	if dir == "" {
+		return nil
+	}
+
+	m.packageDir = dir
+
+	if m.isCodeView() {
+		m.activeView = activeViewList
+	}
+
+	cmd := m.refreshList()
+
+	if m.openedFile != "" && m.isListView() {
+		m.selectItem(m.openedFile)
+	}
+
+	status := fmt.Sprintf("%s: %d of %d", dir, len(m.shownItems()), len(m.items))
+
+	return tea.Batch(cmd, m.newStatusMessage(status))
+}
+
+// currentDir returns the directory of the open file in the code view, or of
+// the selected file or directory of the list.
+func (m *Model) currentDir() string {
+	if m.isCodeView() {
+		return parentDir(m.openedFile)
+	}
+
+	switch item := m.list.SelectedItem().(type) {
+	case *coverProfile:
+		return parentDir(item.profile.FileName)
+	case *dirItem:
+		return item.path
+	default:
+		return ""
+	}
+}
+
+// inPackage reports whether the file is shown by the package filter.
+func (m *Model) inPackage(f *coverProfile) bool {
+	return m.packageDir == "" || parentDir(f.profile.FileName) == m.packageDir
+}
+
+// packageIndicator shows the package the list is narrowed to in the help.
+func (m *Model) packageIndicator() []key.Binding {
+	if m.packageDir == "" {
+		return nil
+	}
+
+	pkgKey := DefaultKeyMap.Package.Help().Key
+
+	return []key.Binding{key.NewBinding(key.WithKeys(pkgKey), key.WithHelp(pkgKey, "package: "+m.packageDir))}
+}
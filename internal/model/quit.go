@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	confirmQuitStyle = lipgloss.NewStyle().Margin(1, 0, 0, 4)
+
+	confirmQuitKey = key.NewBinding(key.WithKeys("y"))
+	cancelQuitKey  = key.NewBinding(key.WithKeys("n", "esc"))
+	forceQuitKey   = key.NewBinding(key.WithKeys("ctrl+c"))
+)
+
+// quit exits the program. While the profile is watched, quitting needs to be
+// confirmed, unless the confirmation is disabled or "ctrl+c" is pressed.
+func (m *Model) quit(msg tea.KeyMsg) tea.Cmd {
+	if m.watchInterval > 0 && m.confirmQuit && !key.Matches(msg, forceQuitKey) {
+		m.confirmingQuit = true
+		return nil
+	}
+
+	m.quitting = true
+
+	return tea.Quit
+}
+
+// onConfirmQuitKey handles the keys while quitting is confirmed: "y" quits,
+// and "n" or "esc" goes back. Other keys are ignored.
+func (m *Model) onConfirmQuitKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, confirmQuitKey, forceQuitKey):
+		m.quitting = true
+		return m, tea.Quit
+	case key.Matches(msg, cancelQuitKey):
+		m.confirmingQuit = false
+	}
+
+	return m, nil
+}
+
+func (m *Model) confirmQuitView() string {
+	return confirmQuitStyle.Render(fmt.Sprintf("Stop watching %s and quit? (y/n)", m.profileFilename))
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testsFinishedMsg is sent when the test command exits.
+type testsFinishedMsg struct {
+	output []byte
+	err    error
+}
+
+// testCommandArgs returns the arguments of the command that regenerates the
+// coverage profile. By default, go test is run for the requested packages,
+// or for all the packages of the module.
+func (m *Model) testCommandArgs() []string {
+	if args := strings.Fields(m.testCommand); len(args) > 0 {
+		return args
+	}
+
+	args := []string{"go", "test", "-coverprofile=" + m.profileFilename}
+
+	if len(m.packages) == 0 {
+		return append(args, "./...")
+	}
+
+	return append(args, m.packages...)
+}
+
+// runTests runs the test command in the code root in the background. The
+// coverage profile is reloaded when the command succeeds; otherwise, the
+// current profile stays displayed.
+func (m *Model) runTests() tea.Cmd {
+	if m.profileContent != nil {
+		return m.newStatusMessage("Tests can't be run when the profile is read from stdin")
+	}
+
+	if m.testsRunning {
+		return m.newStatusMessage("Tests are already running")
+	}
+
+	m.testsRunning = true
+	args, dir := m.testCommandArgs(), m.codeRoot
+
+	run := func() tea.Msg {
+		var output bytes.Buffer
+
+		c := exec.Command(args[0], args[1:]...) // nolint: gosec
+		c.Dir = dir
+		c.Stdout = &output
+		c.Stderr = &output
+
+		return testsFinishedMsg{output: output.Bytes(), err: c.Run()}
+	}
+
+	return tea.Batch(m.newStatusMessage("Running "+strings.Join(args, " ")), run)
+}
+
+func (m *Model) onTestsFinished(msg testsFinishedMsg) (tea.Model, tea.Cmd) {
+	m.testsRunning = false
+
+	if msg.err != nil {
+		status := fmt.Sprintf("Tests failed: %v", msg.err)
+		if line := lastLine(msg.output); line != "" {
+			status += ": " + line
+		}
+
+		return m, m.newStatusMessage(status)
+	}
+
+	return m, m.reloadProfiles()
+}
+
+// lastLine returns the last non-empty line of the output, which is usually
+// the summary of go test.
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"path"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/tools/cover"
+)
+
+// SortMode defines the order of files in the list.
+type SortMode string
+
+// Supported sort modes.
+const (
+	// SortByName sorts files by their base name, then by path.
+	SortByName SortMode = "name"
+
+	// SortByPath sorts files alphabetically by their full path.
+	SortByPath SortMode = "path"
+
+	// SortByCoverageAsc puts files with the lowest coverage first.
+	SortByCoverageAsc SortMode = "coverage-asc"
+
+	// SortByCoverageDesc puts files with the highest coverage first.
+	SortByCoverageDesc SortMode = "coverage-desc"
+
+	// SortByLines puts files with the most statements first.
+	SortByLines SortMode = "lines"
+
+	// SortByUncovered puts files with the most uncovered statements first.
+	SortByUncovered SortMode = "uncovered"
+)
+
+// SortModes lists all supported sort modes.
+var SortModes = []SortMode{
+	SortByName, SortByPath, SortByCoverageAsc, SortByCoverageDesc, SortByLines, SortByUncovered,
+}
+
+// IsValid reports whether the sort mode is supported.
+func (s SortMode) IsValid() bool {
+	for _, mode := range SortModes {
+		if s == mode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortProfiles sorts the profiles in place according to the sort mode.
+// Profiles that are equal in the selected order keep their relative
+// position.
+func sortProfiles(profiles []*cover.Profile, mode SortMode) {
+	var less func(a, b *cover.Profile) bool
+
+	switch mode {
+	case SortByName:
+		less = func(a, b *cover.Profile) bool {
+			if nameA, nameB := path.Base(a.FileName), path.Base(b.FileName); nameA != nameB {
+				return nameA < nameB
+			}
+
+			return a.FileName < b.FileName
+		}
+	case SortByPath:
+		less = func(a, b *cover.Profile) bool { return a.FileName < b.FileName }
+	case SortByCoverageAsc:
+		less = func(a, b *cover.Profile) bool { return percentCovered(a) < percentCovered(b) }
+	case SortByCoverageDesc:
+		less = func(a, b *cover.Profile) bool { return percentCovered(a) > percentCovered(b) }
+	case SortByLines:
+		less = func(a, b *cover.Profile) bool { return numStatements(a) > numStatements(b) }
+	case SortByUncovered:
+		less = func(a, b *cover.Profile) bool { return numUncovered(a) > numUncovered(b) }
+	default:
+		return
+	}
+
+	sort.SliceStable(profiles, func(i, j int) bool {
+		return less(profiles[i], profiles[j])
+	})
+}
+
+func numStatements(p *cover.Profile) int {
+	total := 0
+
+	for _, b := range p.Blocks {
+		total += b.NumStmt
+	}
+
+	return total
+}
+
+func numUncovered(p *cover.Profile) int64 {
+	covered, total := countStatements(p)
+
+	return total - covered
+}
+
+// next returns the sort mode that follows this one in SortModes, wrapping
+// around. An unset mode is followed by the first one.
+func (s SortMode) next() SortMode {
+	for i, mode := range SortModes {
+		if s == mode {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+
+	return SortModes[0]
+}
+
+// cycleSortMode sorts the files by the next sort mode, keeping the selected
+// item selected.
+func (m *Model) cycleSortMode() tea.Cmd {
+	m.sortMode = m.sortMode.next()
+
+	profiles := make([]*cover.Profile, 0, len(m.items))
+	items := make(map[*cover.Profile]list.Item, len(m.items))
+
+	for _, item := range m.items {
+		if p, ok := item.(*coverProfile); ok {
+			profiles = append(profiles, p.profile)
+			items[p.profile] = item
+		}
+	}
+
+	sortProfiles(profiles, m.sortMode)
+
+	// the list may share the slice of the items, so a new one is made
+	m.items = make([]list.Item, 0, len(profiles))
+
+	for _, p := range profiles {
+		m.items = append(m.items, items[p])
+	}
+
+	return tea.Batch(m.refreshList(), m.newStatusMessage("Sorted by "+string(m.sortMode)))
+}
+
+// sortIndicator shows the current sort order in the help of the list, once
+// one is set.
+func (m *Model) sortIndicator() []key.Binding {
+	if m.sortMode == "" {
+		return nil
+	}
+
+	sortKey := DefaultKeyMap.Sort.Help().Key
+
+	return []key.Binding{key.NewBinding(key.WithKeys(sortKey), key.WithHelp(sortKey, "sort: "+string(m.sortMode)))}
+}
+
+// SortMode returns the current order of files, which can be changed in the
+// list.
+func (m *Model) SortMode() SortMode {
+	return m.sortMode
+}
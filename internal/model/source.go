@@ -0,0 +1,126 @@
+package model
+
+import (
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// module is the Go module containing the code root.
+type module struct {
+	path string
+	dir  string
+}
+
+// stripPrefix removes the prefixes set using WithStripPrefixes from the name
+// of the file in the profile, in order.
+func (m *Model) stripPrefix(fileName string) string {
+	for _, prefix := range m.stripPrefixes {
+		fileName = strings.TrimPrefix(fileName, prefix)
+	}
+
+	return fileName
+}
+
+// sourcePath returns the path of the source file with the provided name from
+// the coverage profile. The candidates are tried in order, and the first
+// existing file wins:
+//
+//   - the name relative to the code root, which is the usual case, since
+//     module paths are removed from the names when profiles are loaded;
+//   - the name as-is, for absolute paths and paths relative to the working
+//     directory;
+//   - the name without the path of the module containing the code root,
+//     relative to the directory of the module;
+//   - the name relative to the src directory of every GOPATH entry;
+//   - the name relative to the source root, dropping leading directories one
+//     by one, so that profiles generated elsewhere, for example in a
+//     container, still map to the local files.
+//
+// Without a match, the path in the code root is returned.
+func (m *Model) sourcePath(fileName string) string {
+	defaultPath := path.Join(m.codeRoot, fileName)
+
+	if fileExists(defaultPath) {
+		return defaultPath
+	}
+
+	for _, candidate := range m.sourceCandidates(fileName) {
+		if fileExists(candidate) {
+			return candidate
+		}
+	}
+
+	return defaultPath
+}
+
+// sourceFile returns the path of the source of the file in the list,
+// resolved when the profile was loaded, so that opening files doesn't touch
+// the disk. Files that are not in the list map to the code root.
+func (m *Model) sourceFile(fileName string) string {
+	for _, item := range m.items {
+		if f, ok := item.(*coverProfile); ok && f.profile.FileName == fileName && f.source != "" {
+			return f.source
+		}
+	}
+
+	return path.Join(m.codeRoot, fileName)
+}
+
+func (m *Model) sourceCandidates(fileName string) []string {
+	candidates := []string{fileName}
+
+	if mod, ok := m.codeRootModule(); ok && strings.HasPrefix(fileName, mod.path+"/") {
+		candidates = append(candidates, path.Join(mod.dir, strings.TrimPrefix(fileName, mod.path+"/")))
+	}
+
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		candidates = append(candidates, path.Join(filepath.ToSlash(gopath), "src", fileName))
+	}
+
+	if m.sourceRoot != "" {
+		parts := strings.Split(strings.TrimPrefix(path.Clean(fileName), "/"), "/")
+
+		for i := range parts {
+			candidates = append(candidates, path.Join(append([]string{m.sourceRoot}, parts[i:]...)...))
+		}
+	}
+
+	return candidates
+}
+
+// codeRootModule returns the module containing the code root, looked up
+// when the model is created.
+func (m *Model) codeRootModule() (module, bool) {
+	return m.module, m.module.path != ""
+}
+
+// findModule returns the module of the nearest go.mod file, starting from the
+// directory and moving up.
+func findModule(dir string) module {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return module{}
+	}
+
+	for {
+		if pkg, err := determinePackageName(filepath.Join(dir, "go.mod")); err == nil {
+			return module{path: pkg, dir: filepath.ToSlash(dir)}
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return module{}
+		}
+
+		dir = parent
+	}
+}
+
+func fileExists(name string) bool {
+	fi, err := os.Stat(name)
+
+	return err == nil && !fi.IsDir()
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"log"
+	"os"
+
+	"golang.org/x/tools/cover"
+)
+
+// staleWarning is displayed in the code view of stale files.
+const staleWarning = "Modified after the coverage profile; lines may not match"
+
+// staleFiles returns the names of the files modified after the profile was
+// written, whose coverage may no longer match their lines. Profiles read from
+// stdin have no modification time, so none of their files are stale.
+func (m *Model) staleFiles(profilesFile string, profiles []*cover.Profile, sources map[string]string) map[string]bool {
+	if !m.staleCheck || m.profileContent != nil {
+		return nil
+	}
+
+	info, err := os.Stat(profilesFile)
+	if err != nil {
+		return nil
+	}
+
+	modified := make([]bool, len(profiles))
+
+	m.forEachSource(profiles, sources, func(i int, source string) {
+		sourceInfo, err := os.Stat(source)
+		modified[i] = err == nil && sourceInfo.ModTime().After(info.ModTime())
+	})
+
+	stale := map[string]bool{}
+
+	for i, p := range profiles {
+		if modified[i] {
+			log.Println("stale", p.FileName)
+
+			stale[p.FileName] = true
+		}
+	}
+
+	return stale
+}
+
+// setStaleWarning warns in the code view if the open file is stale.
+func (m *Model) setStaleWarning(profile *cover.Profile) {
+	warning := ""
+	if m.stale[profile.FileName] {
+		warning = staleWarning
+	}
+
+	m.code.SetWarning(warning)
+}
@@ -0,0 +1,158 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	chromastyles "github.com/alecthomas/chroma/v2/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultSyntaxTheme is the chroma style used for syntax highlighting,
+// unless set otherwise.
+const DefaultSyntaxTheme = "monokai"
+
+// IsValidSyntaxTheme reports whether there is a chroma style with the
+// provided name.
+func IsValidSyntaxTheme(name string) bool {
+	_, ok := chromastyles.Registry[name]
+	return ok
+}
+
+// recolorizedMsg is sent when the open file is colorized again after syntax
+// highlighting or the heatmap is enabled or disabled.
+type recolorizedMsg struct {
+	contents fileContents
+	status   string
+}
+
+// segment is a piece of a line rendered with a single style.
+type segment struct {
+	text  string
+	style lipgloss.Style
+}
+
+// highlightSyntax splits the lines into segments of tokens, styled using
+// the chroma style. The lexer is chosen using the file name. It returns nil
+// if the language is unknown, or if the style is not set.
+func highlightSyntax(filename string, lines []string, style *chroma.Style) [][]segment {
+	if style == nil {
+		return nil
+	}
+
+	lexer := lexers.Match(filename)
+	if lexer == nil {
+		return nil
+	}
+
+	iterator, err := chroma.Coalesce(lexer).Tokenise(nil, strings.Join(lines, "\n"))
+	if err != nil {
+		return nil
+	}
+
+	highlighted := make([][]segment, 1, len(lines))
+
+	for _, token := range iterator.Tokens() {
+		tokenStyle := tokenStyle(style.Get(token.Type))
+
+		for i, text := range strings.Split(token.Value, "\n") {
+			if i > 0 {
+				highlighted = append(highlighted, nil)
+			}
+
+			if text != "" {
+				last := len(highlighted) - 1
+				highlighted[last] = append(highlighted[last], segment{text: text, style: tokenStyle})
+			}
+		}
+	}
+
+	return highlighted
+}
+
+// tokenStyle converts the chroma style of a token into a lipgloss style.
+// Only the foreground is used, so that the background of the terminal stays
+// the same.
+func tokenStyle(entry chroma.StyleEntry) lipgloss.Style {
+	s := lipgloss.NewStyle()
+
+	if entry.Colour.IsSet() {
+		s = s.Foreground(lipgloss.Color(entry.Colour.String()))
+	}
+
+	return s.Bold(entry.Bold == chroma.Yes).Italic(entry.Italic == chroma.Yes)
+}
+
+// renderSegments renders the segments of the line, skipping the first bytes.
+func renderSegments(segments []segment, from int) string {
+	var b strings.Builder
+
+	for _, s := range segments {
+		text := s.text
+
+		if from >= len(text) {
+			from -= len(text)
+			continue
+		}
+
+		text, from = text[from:], 0
+
+		b.WriteString(s.style.Render(text))
+	}
+
+	return b.String()
+}
+
+// syntaxStyle returns the chroma style for syntax highlighting, or nil if it
+// is disabled.
+func (m *Model) syntaxStyle() *chroma.Style {
+	if !m.syntax || !m.color {
+		return nil
+	}
+
+	return chromastyles.Get(m.syntaxTheme)
+}
+
+// toggleSyntax enables or disables syntax highlighting. The open file is
+// colorized again.
+func (m *Model) toggleSyntax() tea.Cmd {
+	m.syntax = !m.syntax
+
+	status := "Syntax highlighting disabled"
+	if m.syntax {
+		status = "Syntax highlighting enabled"
+	}
+
+	return m.recolorize(status)
+}
+
+// recolorize colorizes the open file again, and shows the status message
+// once it is done.
+func (m *Model) recolorize(status string) tea.Cmd {
+	profile := m.openedProfile()
+	if !m.isCodeView() || profile == nil {
+		return m.newStatusMessage(status)
+	}
+
+	load := m.loadFile(profile)
+
+	return func() tea.Msg {
+		switch msg := load().(type) {
+		case fileContents:
+			return recolorizedMsg{contents: msg, status: status}
+		case error:
+			return statusMsg(fmt.Sprintf("Failed to colorize %s: %v", profile.FileName, msg))
+		default:
+			return msg
+		}
+	}
+}
+
+func (m *Model) onRecolorized(msg recolorizedMsg) (tea.Model, tea.Cmd) {
+	m.code.UpdateContent(msg.contents)
+
+	return m, m.newStatusMessage(msg.status)
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orlangure/gocovsh/internal/editor"
+	"github.com/orlangure/gocovsh/internal/funccover"
+	"github.com/orlangure/gocovsh/internal/testfind"
+)
+
+// testsLoadedMsg is sent when the tests of the package of the open file are
+// found.
+type testsLoadedMsg struct {
+	dir   string
+	tests []*testfind.Test
+
+	// focus is the name of the function at the top of the code view, if any
+	focus string
+}
+
+// loadTests finds the tests in the directory of the open file, and the ones
+// calling the function at the top of the code view.
+func (m *Model) loadTests() tea.Cmd {
+	profile := m.openedProfile()
+	if profile == nil {
+		return nil
+	}
+
+	filename := m.sourceFile(profile.FileName)
+	line := m.code.TopLine()
+
+	return func() tea.Msg {
+		tests, err := testfind.Find(filepath.Dir(filename))
+		if err != nil {
+			return statusMsg(fmt.Sprintf("Failed to find tests: %v", err))
+		}
+
+		msg := testsLoadedMsg{dir: filepath.Dir(filename), tests: tests}
+
+		// tests can be listed even if the function is unknown
+		if src, err := os.ReadFile(filename); err == nil { // nolint: gosec
+			if funcs, err := funccover.Analyze(filename, src, profile); err == nil {
+				msg.focus = focusedFunc(funcs, line)
+			}
+		}
+
+		return msg
+	}
+}
+
+var literalName = regexp.MustCompile(`^func\d+$`)
+
+// focusedFunc returns the name tests use to call the innermost declared
+// function at the line: methods are called without their receivers, and
+// function literals by the function declaring them.
+func focusedFunc(funcs []*funccover.Func, line int) string {
+	name := ""
+
+	for _, f := range funcs {
+		if f.StartLine <= line && line <= f.EndLine {
+			name = f.Name
+		}
+	}
+
+	if i := strings.Index(name, ".func"); i >= 0 {
+		name = name[:i]
+	}
+
+	if i := strings.LastIndex(name, ")."); i >= 0 {
+		name = name[i+2:]
+	}
+
+	// literals outside of functions can't be called by name
+	if literalName.MatchString(name) {
+		return ""
+	}
+
+	return name
+}
+
+func (m *Model) onTestsLoaded(msg testsLoadedMsg) (tea.Model, tea.Cmd) {
+	m.testsDir = msg.dir
+	title := "Tests in " + path.Dir(m.openedFile) + "/"
+	if path.Dir(m.openedFile) == "." {
+		title = "Tests in the module root"
+	}
+
+	m.tests.SetTitle(title)
+	m.tests.SetTests(msg.tests, msg.focus)
+	m.activeView = activeViewTests
+
+	return m, nil
+}
+
+// openTest suspends the program and opens the selected test in the editor.
+func (m *Model) openTest() tea.Cmd {
+	test := m.tests.Selected()
+	if test == nil {
+		return nil
+	}
+
+	c := editor.Command(filepath.Join(m.testsDir, test.File), test.Line)
+
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorFinishedMsg{err}
+	})
+}
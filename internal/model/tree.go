@@ -0,0 +1,177 @@
+package model
+
+import (
+	"path"
+	"sort"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dirItem is a directory in the tree mode of the list. Its coverage is the
+// weighted coverage of all the files below it.
+type dirItem struct {
+	path       string
+	depth      int
+	collapsed  bool
+	percentage float64
+
+	// covered and total statements of all the files below the directory
+	covered int64
+	total   int64
+
+	// compare is the coverage of the files below the directory in the
+	// compared profile
+	compare comparison
+}
+
+func (d *dirItem) FilterValue() string { return d.path }
+
+// listItems returns the items to display in the list: the shown files in
+// flat mode, or the rows of the tree otherwise.
+func (m *Model) listItems() []list.Item {
+	if !m.tree {
+		return m.shownItems()
+	}
+
+	return m.treeItems()
+}
+
+// treeItems nests the files under their directories. Directories come first,
+// in alphabetical order, followed by the files in the order of the profile.
+// Children of collapsed directories are skipped.
+func (m *Model) treeItems() []list.Item {
+	dirs := map[string]*dirItem{}
+	subdirs := map[string][]string{}
+	files := map[string][]*coverProfile{}
+	shown := m.shownItems()
+
+	for _, item := range shown {
+		p, ok := item.(*coverProfile)
+		if !ok {
+			continue
+		}
+
+		dir := parentDir(p.profile.FileName)
+		files[dir] = append(files[dir], p)
+
+		for ; dir != "."; dir = parentDir(dir) {
+			d, ok := dirs[dir]
+			if !ok {
+				d = &dirItem{path: dir, collapsed: m.collapsedDirs[dir]}
+				dirs[dir] = d
+				subdirs[parentDir(dir)] = append(subdirs[parentDir(dir)], dir)
+			}
+
+			d.covered += p.covered
+			d.total += p.total
+			d.compare.found = d.compare.found || p.compare.found
+			d.compare.covered += p.compare.covered
+			d.compare.total += p.compare.total
+		}
+	}
+
+	items := make([]list.Item, 0, len(shown)+len(dirs))
+
+	var walk func(dir string, depth int)
+
+	walk = func(dir string, depth int) {
+		children := subdirs[dir]
+		sort.Strings(children)
+
+		for _, child := range children {
+			d := dirs[child]
+			d.depth = depth
+
+			if d.total > 0 {
+				d.percentage = float64(d.covered) / float64(d.total) * 100
+			}
+
+			items = append(items, d)
+
+			if !d.collapsed {
+				walk(child, depth+1)
+			}
+		}
+
+		for _, p := range files[dir] {
+			p.depth = depth
+			items = append(items, p)
+		}
+	}
+
+	walk(".", 0)
+
+	return items
+}
+
+// parentDir returns the directory of the file, or "." for files at the top
+// level, including absolute paths.
+func parentDir(name string) string {
+	dir := path.Dir(name)
+	if dir == "/" {
+		return "."
+	}
+
+	return dir
+}
+
+// toggleDir expands or collapses the directory.
+func (m *Model) toggleDir(d *dirItem) tea.Cmd {
+	if m.collapsedDirs == nil {
+		m.collapsedDirs = map[string]bool{}
+	}
+
+	m.collapsedDirs[d.path] = !d.collapsed
+
+	return m.refreshList()
+}
+
+// refreshList rebuilds the list items, keeping the selected item selected.
+func (m *Model) refreshList() tea.Cmd {
+	selected := selectedKey(m.list.SelectedItem())
+
+	var cmd tea.Cmd
+
+	m.updatePages(func() { cmd = m.list.SetItems(m.listItems()) })
+	m.selectItem(selected)
+
+	return cmd
+}
+
+// selectItem selects the list item with the provided key, if it is
+// displayed. While the list is filtered, the selection is left as is.
+func (m *Model) selectItem(key string) {
+	if key == "" || m.list.FilterState() != list.Unfiltered {
+		return
+	}
+
+	for i, item := range m.list.Items() {
+		if selectedKey(item) == key {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// selectedKey identifies the item across rebuilds of the list.
+func selectedKey(item list.Item) string {
+	switch item := item.(type) {
+	case *coverProfile:
+		return item.profile.FileName
+	case *dirItem:
+		return item.path + "/"
+	default:
+		return ""
+	}
+}
+
+// activateSelected opens the selected file, or expands or collapses the
+// selected directory.
+func (m *Model) activateSelected() tea.Cmd {
+	if d, ok := m.list.SelectedItem().(*dirItem); ok {
+		return m.toggleDir(d)
+	}
+
+	return m.openSelectedFile()
+}
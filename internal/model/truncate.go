@@ -0,0 +1,199 @@
+package model
+
+import (
+	"path"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+const ellipsis = "…"
+
+// Widths of the columns following the names in the list.
+const (
+	percentageWidth = len("100.00%")
+	unchangedWidth  = len("no changed statements")
+	deltaWidth      = len(" +100.00%")
+	generatedWidth  = len(" generated")
+	staleWidth      = len(staleNote)
+	missingWidth    = len(missingNote)
+)
+
+// itemLabel returns the name of the list item as it is displayed, and the
+// indent prefixing it in the tree.
+func itemLabel(listItem list.Item, tree bool) (name, indent string) {
+	switch item := listItem.(type) {
+	case *coverProfile:
+		if tree {
+			return path.Base(item.name), strings.Repeat(treeIndent, item.depth) + treeFileMarker
+		}
+
+		return item.name, ""
+	case *dirItem:
+		indent = strings.Repeat(treeIndent, item.depth) + treeExpandedMarker
+		if item.collapsed {
+			indent = strings.Repeat(treeIndent, item.depth) + treeCollapsedMarker
+		}
+
+		return path.Base(item.path) + "/", indent
+	default:
+		return "", ""
+	}
+}
+
+// nameWidth returns the width of the column of names in the list: the width
+// of the longest name, unless the coverage wouldn't fit the screen next to
+// it. It returns 0 until the size of the screen is known.
+func (m *Model) nameWidth() int {
+	if m.list.Width() == 0 {
+		return 0
+	}
+
+	longest := 0
+	generated, stale, missing := false, false, false
+
+	for _, item := range m.list.Items() {
+		name, indent := itemLabel(item, m.tree)
+		if w := lipgloss.Width(indent + name); w > longest {
+			longest = w
+		}
+
+		if f, ok := item.(*coverProfile); ok {
+			generated = generated || f.generated
+			stale = stale || f.stale
+			missing = missing || f.missing
+		}
+	}
+
+	// the name is followed by two spaces and the coverage
+	reserved := itemStyle.GetPaddingLeft() + 2 + percentageWidth
+
+	if m.diffOnly {
+		reserved += unchangedWidth - percentageWidth
+	}
+
+	if !m.color && m.hasThresholds() {
+		reserved += lipgloss.Width(styles.CurrentTheme.CoveredMarker)
+	}
+
+	if m.isComparing() {
+		reserved += deltaWidth
+	}
+
+	if generated {
+		reserved += generatedWidth
+	}
+
+	if stale {
+		reserved += staleWidth
+	}
+
+	if missing {
+		reserved += missingWidth
+	}
+
+	if m.hasBookmarks() {
+		reserved += lipgloss.Width(bookmarkNote)
+	}
+
+	if w := m.barWidth(); w > 0 {
+		reserved += 1 + w
+	}
+
+	if available := m.list.Width() - reserved; available < longest {
+		return available
+	}
+
+	return longest
+}
+
+// fitName truncates the name to the width and pads it with spaces, so that
+// the coverage is aligned in a column. The matches are moved along with the
+// characters they belong to.
+func fitName(name string, matches []int, width int) (string, []int) {
+	fitted, head, tail := truncatePath(name, width)
+	if fitted != name {
+		matches = truncateMatches(matches, len(name), head, tail)
+	}
+
+	if pad := width - lipgloss.Width(fitted); pad > 0 {
+		fitted += strings.Repeat(" ", pad)
+	}
+
+	return fitted, matches
+}
+
+// truncatePath shortens the path to fit the width by replacing directories
+// in its middle with an ellipsis, such as internal/…/program.go. The file
+// name is always kept, along with as many of the directories before it as
+// fit. Names that don't fit even on their own lose their beginning instead.
+// It returns the number of bytes kept from the start and the end of the path.
+func truncatePath(name string, width int) (truncated string, head, tail int) {
+	if lipgloss.Width(name) <= width {
+		return name, len(name), 0
+	}
+
+	// directories of the tree end with a slash, which is kept
+	trimmed := strings.TrimSuffix(name, "/")
+	slash := name[len(trimmed):]
+
+	parts := strings.Split(trimmed, "/")
+	kept := parts[len(parts)-1] + slash
+
+	if len(parts) == 1 || lipgloss.Width(ellipsis+"/"+kept) > width {
+		kept = tailFitting(kept, width-lipgloss.Width(ellipsis))
+		return ellipsis + kept, 0, len(kept)
+	}
+
+	// the first directory is dropped if there is no room for it
+	first := parts[0] + "/"
+	if lipgloss.Width(first+ellipsis+"/"+kept) > width {
+		first = ""
+	}
+
+	for i := len(parts) - 2; i > 0; i-- {
+		if lipgloss.Width(first+ellipsis+"/"+parts[i]+"/"+kept) > width {
+			break
+		}
+
+		kept = parts[i] + "/" + kept
+	}
+
+	kept = "/" + kept
+
+	return first + ellipsis + kept, len(first), len(kept)
+}
+
+// tailFitting returns the longest end of s that fits the width.
+func tailFitting(s string, width int) string {
+	runes := []rune(s)
+	start := len(runes)
+
+	for start > 0 && width-runewidth.RuneWidth(runes[start-1]) >= 0 {
+		width -= runewidth.RuneWidth(runes[start-1])
+		start--
+	}
+
+	return string(runes[start:])
+}
+
+// truncateMatches moves the byte offsets of matches in a string of the
+// length to the truncated string keeping head bytes from its start and tail
+// bytes from its end. Matches of the removed characters are dropped.
+func truncateMatches(matches []int, length, head, tail int) []int {
+	truncated := make([]int, 0, len(matches))
+
+	for _, i := range matches {
+		switch {
+		case i < head:
+			truncated = append(truncated, i)
+		case i >= length-tail:
+			truncated = append(truncated, i-(length-tail)+head+len(ellipsis))
+		}
+	}
+
+	return truncated
+}
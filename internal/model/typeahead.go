@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultTypeAheadTimeout is the time after the last typed letter when the
+// next one starts a new prefix.
+const DefaultTypeAheadTimeout = time.Second
+
+// typeAheadResetMsg is sent once the typed prefix times out. Only the
+// message of the last typed letter resets it.
+type typeAheadResetMsg struct{ id int }
+
+// onTypeAhead selects the first file or directory of the list whose name
+// starts with the letters typed so far. A prefix starts with a letter that
+// isn't bound to a key, or with the jump key for names starting with bound
+// letters; until it times out, all the letters extend it. It reports whether
+// the key was handled.
+func (m *Model) onTypeAhead(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.typeAheadTimeout == 0 || !m.isListView() || msg.Type != tea.KeyRunes || msg.Alt || len(msg.Runes) != 1 {
+		return nil, false
+	}
+
+	if !m.typeAheadActive && key.Matches(msg, DefaultKeyMap.Jump) {
+		m.typeAheadActive = true
+
+		return tea.Batch(m.resetTypeAhead(), m.newStatusMessage("Jump: ")), true
+	}
+
+	if !m.typeAheadActive && isBound(msg, m.boundKeys()) {
+		return nil, false
+	}
+
+	m.typeAheadActive = true
+	m.typeAhead += string(msg.Runes)
+	reset := m.resetTypeAhead()
+
+	if !m.selectByPrefix(m.typeAhead) {
+		return tea.Batch(reset, m.newStatusMessage(fmt.Sprintf("No match for %q", m.typeAhead))), true
+	}
+
+	return tea.Batch(reset, m.newStatusMessage("Jump: "+m.typeAhead)), true
+}
+
+// resetTypeAhead schedules the reset of the prefix after the timeout,
+// replacing the one scheduled before.
+func (m *Model) resetTypeAhead() tea.Cmd {
+	m.typeAheadID++
+	id := m.typeAheadID
+
+	return tea.Tick(m.typeAheadTimeout, func(time.Time) tea.Msg { return typeAheadResetMsg{id: id} })
+}
+
+// onTypeAheadReset forgets the typed prefix, unless more letters were typed
+// since the message was scheduled.
+func (m *Model) onTypeAheadReset(msg typeAheadResetMsg) (tea.Model, tea.Cmd) {
+	if msg.id == m.typeAheadID {
+		m.typeAhead = ""
+		m.typeAheadActive = false
+	}
+
+	return m, nil
+}
+
+// selectByPrefix selects the first displayed item whose base name starts with
+// the prefix, ignoring case. It reports whether there is one.
+func (m *Model) selectByPrefix(prefix string) bool {
+	prefix = strings.ToLower(prefix)
+
+	for i, item := range m.list.VisibleItems() {
+		var name string
+
+		switch item := item.(type) {
+		case *coverProfile:
+			name = path.Base(item.profile.FileName)
+		case *dirItem:
+			name = path.Base(item.path)
+		default:
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(name), prefix) {
+			m.list.Select(i)
+			return true
+		}
+	}
+
+	return false
+}
+
+// boundKeys returns the bindings of the model and of the list, which take
+// precedence over starting a prefix.
+func (m *Model) boundKeys() []key.Binding {
+	lk := m.list.KeyMap
+	bindings := []key.Binding{
+		lk.CursorUp, lk.CursorDown, lk.NextPage, lk.PrevPage, lk.GoToStart, lk.GoToEnd, lk.Filter,
+		lk.ShowFullHelp, lk.Quit,
+	}
+
+	for _, group := range DefaultKeyMap.FullHelp() {
+		bindings = append(bindings, group...)
+	}
+
+	return bindings
+}
+
+func isBound(msg tea.KeyMsg, bindings []key.Binding) bool {
+	for _, b := range bindings {
+		if key.Matches(msg, b) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/tools/cover"
+)
+
+// profileStatMsg is sent every time the coverage profile is checked for
+// changes.
+type profileStatMsg struct {
+	modTime time.Time
+	err     error
+}
+
+// profilesReloadedMsg is sent when the coverage profile is parsed again after
+// it changed.
+type profilesReloadedMsg profilesLoadedMsg
+
+// fileReloadedMsg is sent when the currently open file is colorized again
+// using the reloaded coverage profile.
+type fileReloadedMsg fileContents
+
+// reloadFailedMsg wraps errors that happen during reload. Unlike other
+// errors, they don't stop the program, because the next reload may succeed.
+type reloadFailedMsg struct{ error }
+
+func (m *Model) statProfile() tea.Cmd {
+	profilesFile := m.profilePath()
+
+	return func() tea.Msg {
+		fi, err := os.Stat(profilesFile)
+		if err != nil {
+			return profileStatMsg{err: err}
+		}
+
+		return profileStatMsg{modTime: fi.ModTime()}
+	}
+}
+
+func (m *Model) watchProfile() tea.Cmd {
+	stat := m.statProfile()
+
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg {
+		return stat()
+	})
+}
+
+func (m *Model) onProfileStat(msg profileStatMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		return m, tea.Batch(
+			m.newStatusMessage(fmt.Sprintf("Coverage profile is not available: %v", msg.err)),
+			m.watchProfile(),
+		)
+	}
+
+	changed := !m.profileModTime.IsZero() && !msg.modTime.Equal(m.profileModTime)
+	m.profileModTime = msg.modTime
+
+	if !changed {
+		return m, m.watchProfile()
+	}
+
+	return m, tea.Batch(m.reloadProfiles(), m.watchProfile())
+}
+
+func (m *Model) reloadProfiles() tea.Cmd {
+	loader := m.loader()
+
+	return func() tea.Msg {
+		msg, err := loader.readProfiles()
+		if err != nil {
+			return reloadFailedMsg{err}
+		}
+
+		if len(msg.profiles) == 0 {
+			return reloadFailedMsg{fmt.Errorf("coverage profile has no entries")}
+		}
+
+		if msg.compare, err = loader.readCompareProfiles(); err != nil {
+			return reloadFailedMsg{err}
+		}
+
+		return profilesReloadedMsg(msg)
+	}
+}
+
+func (m *Model) onProfilesReloaded(msg profilesReloadedMsg) (tea.Model, tea.Cmd) {
+	selected := selectedKey(m.list.SelectedItem())
+	cmds := []tea.Cmd{m.setProfiles(profilesLoadedMsg(msg))}
+	m.selectItem(selected)
+
+	var openedProfile *cover.Profile
+
+	for _, profile := range msg.profiles {
+		if profile.FileName == m.openedFile {
+			openedProfile = profile
+		}
+	}
+
+	if !m.isCodeView() && !m.isFuncsView() && !m.isBlocksView() {
+		return m, tea.Batch(append(cmds, m.newStatusMessage("Coverage profile reloaded"))...)
+	}
+
+	if openedProfile == nil {
+		m.activeView = activeViewList
+		msg := fmt.Sprintf("%s is no longer in the coverage profile", m.openedFile)
+
+		return m, tea.Batch(append(cmds, m.newStatusMessage(msg))...)
+	}
+
+	m.code.SetUncoveredBlocks(uncoveredBlocks(openedProfile))
+	m.code.SetCoveredBlocks(coveredBlocks(openedProfile))
+	m.setCodeCoverage(openedProfile)
+	m.setLineDelta(openedProfile)
+	m.setLegend(openedProfile)
+	m.setStaleWarning(openedProfile)
+
+	if m.isFuncsView() {
+		cmds = append(cmds, m.loadFuncs())
+	}
+
+	if m.isBlocksView() {
+		m.blocks.SetBlocks(openedProfile.Blocks)
+	}
+
+	return m, tea.Batch(append(cmds, reloadFile(m.loadFile(openedProfile), openedProfile))...)
+}
+
+func (m *Model) onFileReloaded(content fileReloadedMsg) (tea.Model, tea.Cmd) {
+	m.code.UpdateContent(content)
+
+	return m, m.newStatusMessage("Coverage profile reloaded")
+}
+
+func reloadFile(load tea.Cmd, profile *cover.Profile) tea.Cmd {
+	return func() tea.Msg {
+		switch msg := load().(type) {
+		case fileContents:
+			return fileReloadedMsg(msg)
+		case error:
+			return reloadFailedMsg{fmt.Errorf("%s: %w", profile.FileName, msg)}
+		default:
+			return msg
+		}
+	}
+}
+
+// newStatusMessage shows a short message in the active view.
+func (m *Model) newStatusMessage(s string) tea.Cmd {
+	if m.isCodeView() {
+		return m.code.NewStatusMessage(s)
+	}
+
+	return m.list.NewStatusMessage(s)
+}
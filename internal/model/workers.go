@@ -0,0 +1,103 @@
+package model
+
+import (
+	"sync"
+
+	"golang.org/x/tools/cover"
+)
+
+// resolveSources returns the paths of the sources of the profiles by file
+// name, looking up to m.workers sources at once. They are resolved once when
+// the profile is loaded, and stored on the items of the list.
+func (m *Model) resolveSources(profiles []*cover.Profile) map[string]string {
+	paths := make([]string, len(profiles))
+
+	parallel(len(profiles), m.workers, func(i int) {
+		paths[i] = m.sourcePath(profiles[i].FileName)
+	})
+
+	sources := make(map[string]string, len(profiles))
+
+	for i, p := range profiles {
+		sources[p.FileName] = paths[i]
+	}
+
+	return sources
+}
+
+// forEachSource calls fn with the resolved path of the source of each of the
+// profiles, processing up to m.workers sources at once. fn may be called
+// concurrently, so it must only store its results at the index of the
+// profile, which keeps them in the order of the profiles.
+func (m *Model) forEachSource(profiles []*cover.Profile, sources map[string]string, fn func(i int, source string)) {
+	parallel(len(profiles), m.workers, func(i int) {
+		fn(i, sources[profiles[i].FileName])
+	})
+}
+
+// parallel calls fn for every index below n using up to the given number of
+// workers, and returns once all the calls are done.
+func parallel(n, workers int, fn func(i int)) {
+	if workers > n {
+		workers = n
+	}
+
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+
+		return
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// analyzeSources reads the sources of the profiles, dropping the blocks of
+// the lines ignored with comments, and returns the names of the generated
+// files.
+func (m *Model) analyzeSources(profiles []*cover.Profile, sources map[string]string) map[string]bool {
+	generated := make([]bool, len(profiles))
+	ignored := make([]map[int]bool, len(profiles))
+
+	m.forEachSource(profiles, sources, func(i int, source string) {
+		generated[i] = isGenerated(profiles[i].FileName, source)
+
+		if m.ignoreComments {
+			ignored[i] = ignoredLines(source)
+		}
+	})
+
+	generatedFiles := map[string]bool{}
+
+	for i, p := range profiles {
+		if generated[i] {
+			generatedFiles[p.FileName] = true
+		}
+
+		dropBlocks(p, ignored[i])
+	}
+
+	return generatedFiles
+}
@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"golang.org/x/tools/cover"
+)
+
+type coberturaReport struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string          `xml:"filename,attr"`
+			Lines    []coberturaLine `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// coberturaParser reads Cobertura XML reports. Every line becomes a block
+// of a single statement, and branch coverage is ignored.
+type coberturaParser struct{}
+
+func (coberturaParser) Parse(r io.Reader) ([]*cover.Profile, error) {
+	var report coberturaReport
+
+	if err := xml.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to parse cobertura xml: %w", err)
+	}
+
+	hitsByFile := map[string]map[int]int{}
+
+	// several classes can be defined in the same file
+	for _, pkg := range report.Packages {
+		for _, class := range pkg.Classes {
+			if class.Filename == "" {
+				return nil, fmt.Errorf("failed to parse cobertura xml: class without filename")
+			}
+
+			hits, ok := hitsByFile[class.Filename]
+			if !ok {
+				hits = map[int]int{}
+				hitsByFile[class.Filename] = hits
+			}
+
+			for _, line := range class.Lines {
+				if line.Number < 1 {
+					return nil, fmt.Errorf("failed to parse cobertura xml: invalid line number %d in %s",
+						line.Number, class.Filename)
+				}
+
+				hits[line.Number] += line.Hits
+			}
+		}
+	}
+
+	return lineProfiles(hitsByFile), nil
+}
+
+// lineProfiles converts hits of every line of every file into profiles,
+// sorted by file name. Files without lines are skipped.
+func lineProfiles(hitsByFile map[string]map[int]int) []*cover.Profile {
+	profiles := make([]*cover.Profile, 0, len(hitsByFile))
+
+	for filename, hits := range hitsByFile {
+		if len(hits) == 0 {
+			continue
+		}
+
+		p := &cover.Profile{
+			FileName: filename,
+			Mode:     "count",
+			Blocks:   make([]cover.ProfileBlock, 0, len(hits)),
+		}
+
+		for line, count := range hits {
+			p.Blocks = append(p.Blocks, cover.ProfileBlock{
+				StartLine: line,
+				StartCol:  1,
+				EndLine:   line,
+				EndCol:    1,
+				NumStmt:   1,
+				Count:     count,
+			})
+		}
+
+		sort.Slice(p.Blocks, func(i, j int) bool { return p.Blocks[i].StartLine < p.Blocks[j].StartLine })
+
+		profiles = append(profiles, p)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].FileName < profiles[j].FileName })
+
+	return profiles
+}
@@ -0,0 +1,76 @@
+package parser_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestCobertura(t *testing.T) {
+	p, err := parser.New(parser.FormatCobertura)
+	require.NoError(t, err)
+
+	f, err := os.Open("testdata/coverage.xml")
+	require.NoError(t, err)
+
+	defer func() { _ = f.Close() }()
+
+	profiles, err := p.Parse(f)
+	require.NoError(t, err)
+
+	line := func(n, count int) cover.ProfileBlock {
+		return cover.ProfileBlock{StartLine: n, StartCol: 1, EndLine: n, EndCol: 1, NumStmt: 1, Count: count}
+	}
+
+	require.Equal(t, []*cover.Profile{
+		{
+			FileName: "bar/bar.go",
+			Mode:     "count",
+			Blocks:   []cover.ProfileBlock{line(5, 1)},
+		},
+		{
+			FileName: "foo.go",
+			Mode:     "count",
+			Blocks:   []cover.ProfileBlock{line(3, 2), line(4, 0), line(10, 1), line(11, 0)},
+		},
+	}, profiles)
+}
+
+func TestCoberturaInvalid(t *testing.T) {
+	p, err := parser.New(parser.FormatCobertura)
+	require.NoError(t, err)
+
+	class := func(s string) string {
+		return "<coverage><packages><package><classes>" + s + "</classes></package></packages></coverage>"
+	}
+
+	for name, input := range map[string]string{
+		"not xml":          "mode: set\n",
+		"unclosed tag":     `<coverage><packages>`,
+		"no filename":      class(`<class/>`),
+		"bad line number":  class(`<class filename="a.go"><lines><line number="x"/></lines></class>`),
+		"zero line number": class(`<class filename="a.go"><lines><line number="0"/></lines></class>`),
+	} {
+		_, err := p.Parse(strings.NewReader(input))
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "cobertura", name)
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, format := range parser.Formats {
+		require.True(t, format.IsValid())
+
+		_, err := parser.New(format)
+		require.NoError(t, err)
+	}
+
+	require.False(t, parser.Format("xml").IsValid())
+
+	_, err := parser.New("xml")
+	require.Error(t, err)
+}
@@ -0,0 +1,191 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+const modePrefix = "mode: "
+
+// goParser reads profiles of go test -coverprofile. The profile is read line
+// by line, and the blocks of every file are merged as it goes, so that only
+// the parsed profiles are kept in memory. It is compatible with
+// cover.ParseProfilesFromReader, but errors include the line numbers.
+type goParser struct {
+	// mode is assumed if the profile has no mode line
+	mode string
+}
+
+func (g goParser) Parse(r io.Reader) ([]*cover.Profile, error) {
+	files := map[string]*cover.Profile{}
+	mode := ""
+
+	scanner := bufio.NewScanner(r)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+
+		if mode == "" {
+			switch {
+			case strings.HasPrefix(line, modePrefix) && line != modePrefix:
+				mode = strings.TrimPrefix(line, modePrefix)
+				continue
+			case g.mode == "":
+				return nil, fmt.Errorf("failed to parse profile: line %d: bad mode line %q", lineNum, line)
+			default:
+				// the first line is already a block
+				mode = g.mode
+			}
+		}
+
+		filename, block, err := parseGoLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse profile: line %d: %q doesn't match expected format: %w", lineNum, line, err)
+		}
+
+		p := files[filename]
+		if p == nil {
+			p = &cover.Profile{FileName: filename, Mode: mode}
+			files[filename] = p
+		}
+
+		p.Blocks = append(p.Blocks, block)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	profiles := make([]*cover.Profile, 0, len(files))
+
+	for _, p := range files {
+		if err := mergeBlocks(p); err != nil {
+			return nil, fmt.Errorf("failed to parse profile: %s: %w", p.FileName, err)
+		}
+
+		profiles = append(profiles, p)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].FileName < profiles[j].FileName })
+
+	return profiles, nil
+}
+
+// mergeBlocks sorts the blocks of the profile by position, and merges the
+// counts of the blocks at the same position, for example of several test
+// binaries, or the duplicated blocks of atomic profiles of race builds. The
+// blocks are sorted by both ends, so that the duplicates are next to each
+// other even if other blocks start at the same position.
+func mergeBlocks(p *cover.Profile) error {
+	sort.SliceStable(p.Blocks, func(i, j int) bool {
+		a, b := p.Blocks[i], p.Blocks[j]
+
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+
+		if a.StartCol != b.StartCol {
+			return a.StartCol < b.StartCol
+		}
+
+		if a.EndLine != b.EndLine {
+			return a.EndLine < b.EndLine
+		}
+
+		return a.EndCol < b.EndCol
+	})
+
+	merged := p.Blocks[:1]
+
+	for _, b := range p.Blocks[1:] {
+		last := &merged[len(merged)-1]
+
+		if b.StartLine != last.StartLine || b.StartCol != last.StartCol ||
+			b.EndLine != last.EndLine || b.EndCol != last.EndCol {
+			merged = append(merged, b)
+			continue
+		}
+
+		if b.NumStmt != last.NumStmt {
+			return fmt.Errorf("inconsistent NumStmt: changed from %d to %d", last.NumStmt, b.NumStmt)
+		}
+
+		if p.Mode == "set" {
+			last.Count |= b.Count
+		} else {
+			last.Count += b.Count
+		}
+	}
+
+	p.Blocks = merged
+
+	return nil
+}
+
+// parseGoLine parses "name.go:line.column,line.column statements count".
+// The fields are read from the end, because file names can include any of
+// the separators.
+func parseGoLine(line string) (string, cover.ProfileBlock, error) {
+	var (
+		b   cover.ProfileBlock
+		err error
+	)
+
+	end := len(line)
+
+	if b.Count, end, err = lastNumber(line, end, ' ', "Count"); err != nil {
+		return "", b, err
+	}
+
+	if b.NumStmt, end, err = lastNumber(line, end, ' ', "NumStmt"); err != nil {
+		return "", b, err
+	}
+
+	if b.EndCol, end, err = lastNumber(line, end, '.', "EndCol"); err != nil {
+		return "", b, err
+	}
+
+	if b.EndLine, end, err = lastNumber(line, end, ',', "EndLine"); err != nil {
+		return "", b, err
+	}
+
+	if b.StartCol, end, err = lastNumber(line, end, '.', "StartCol"); err != nil {
+		return "", b, err
+	}
+
+	if b.StartLine, end, err = lastNumber(line, end, ':', "StartLine"); err != nil {
+		return "", b, err
+	}
+
+	if end == 0 {
+		return "", b, fmt.Errorf("a FileName cannot be blank")
+	}
+
+	return line[:end], b, nil
+}
+
+// lastNumber parses the number between the last separator before the end
+// and the end. It returns the position of the separator.
+func lastNumber(line string, end int, sep byte, name string) (int, int, error) {
+	start := strings.LastIndexByte(line[:end], sep)
+	if start < 0 {
+		return 0, 0, fmt.Errorf("couldn't find a %s before %s", string(sep), name)
+	}
+
+	value, err := strconv.Atoi(line[start+1 : end])
+	if err != nil {
+		return 0, 0, fmt.Errorf("couldn't parse %q: %w", name, err)
+	}
+
+	if value < 0 {
+		return 0, 0, fmt.Errorf("negative values are not allowed for %s, found %d", name, value)
+	}
+
+	return value, start, nil
+}
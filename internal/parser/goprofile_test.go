@@ -0,0 +1,153 @@
+package parser_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestGo(t *testing.T) {
+	p, err := parser.New(parser.FormatGo)
+	require.NoError(t, err)
+
+	for _, filename := range []string{
+		"../gocovshtest/testdata/general/profile.cover",
+		"../gocovshtest/testdata/tree/profile.cover",
+		"../program/testdata/config/custom.cover",
+	} {
+		bs, err := os.ReadFile(filename)
+		require.NoError(t, err)
+
+		expected, err := cover.ParseProfilesFromReader(strings.NewReader(string(bs)))
+		require.NoError(t, err)
+
+		profiles, err := p.Parse(strings.NewReader(string(bs)))
+		require.NoError(t, err)
+		require.Equal(t, expected, profiles, filename)
+	}
+}
+
+func TestGoMergesBlocks(t *testing.T) {
+	p, err := parser.New(parser.FormatGo)
+	require.NoError(t, err)
+
+	for mode, count := range map[string]int{"set": 1, "count": 2} {
+		profiles, err := p.Parse(strings.NewReader(
+			"mode: " + mode + "\n" +
+				"b.go:1.1,2.2 1 1\n" +
+				"dir/a:b.go:7.1,9.2 2 0\n" +
+				"b.go:3.1,4.2 1 0\n" +
+				"b.go:1.1,2.2 1 1\n",
+		))
+		require.NoError(t, err)
+		require.Equal(t, []*cover.Profile{
+			{
+				FileName: "b.go",
+				Mode:     mode,
+				Blocks: []cover.ProfileBlock{
+					{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: count},
+					{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 1, Count: 0},
+				},
+			},
+			{
+				FileName: "dir/a:b.go",
+				Mode:     mode,
+				Blocks: []cover.ProfileBlock{
+					{StartLine: 7, StartCol: 1, EndLine: 9, EndCol: 2, NumStmt: 2, Count: 0},
+				},
+			},
+		}, profiles, mode)
+	}
+}
+
+func TestGoMergesDuplicateAtomicBlocks(t *testing.T) {
+	p, err := parser.New(parser.FormatGo)
+	require.NoError(t, err)
+
+	// race builds may repeat and reorder blocks, including the ones starting
+	// at the same position as other blocks
+	profiles, err := p.Parse(strings.NewReader(
+		"mode: atomic\n" +
+			"a.go:5.1,6.2 1 3\n" +
+			"a.go:1.1,3.2 2 4\n" +
+			"a.go:1.1,2.2 1 1\n" +
+			"a.go:5.1,6.2 1 0\n" +
+			"a.go:1.1,3.2 2 5\n" +
+			"a.go:1.1,2.2 1 2\n",
+	))
+	require.NoError(t, err)
+	require.Equal(t, []*cover.Profile{
+		{
+			FileName: "a.go",
+			Mode:     "atomic",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 3},
+				{StartLine: 1, StartCol: 1, EndLine: 3, EndCol: 2, NumStmt: 2, Count: 9},
+				{StartLine: 5, StartCol: 1, EndLine: 6, EndCol: 2, NumStmt: 1, Count: 3},
+			},
+		},
+	}, profiles)
+}
+
+func TestGoInvalid(t *testing.T) {
+	p, err := parser.New(parser.FormatGo)
+	require.NoError(t, err)
+
+	for name, test := range map[string]struct {
+		input string
+		err   string
+	}{
+		"malformed line": {
+			input: "mode: set\na.go:1.1,2.2 1 1\na.go:3.1,4.2 1\na.go:5.1,6.2 1 1\n",
+			err:   `failed to parse profile: line 3: "a.go:3.1,4.2 1" doesn't match expected format`,
+		},
+		"bad mode line":     {input: "a.go:1.1,2.2 1 1\n", err: `line 1: bad mode line "a.go:1.1,2.2 1 1"`},
+		"empty mode":        {input: "mode: \n", err: `line 1: bad mode line "mode: "`},
+		"empty line":        {input: "mode: set\n\n", err: `line 2: "" doesn't match expected format`},
+		"negative count":    {input: "mode: count\na.go:1.1,2.2 1 -1\n", err: "line 2: "},
+		"bad number":        {input: "mode: set\na.go:1.x,2.2 1 1\n", err: `couldn't parse "StartCol"`},
+		"empty file name":   {input: "mode: set\n:1.1,2.2 1 1\n", err: "a FileName cannot be blank"},
+		"inconsistent size": {input: "mode: set\na.go:1.1,2.2 1 1\na.go:1.1,2.2 2 1\n", err: "a.go: inconsistent NumStmt"},
+	} {
+		_, err := p.Parse(strings.NewReader(test.input))
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), test.err, name)
+	}
+}
+
+func TestGoMode(t *testing.T) {
+	p, err := parser.New(parser.FormatGo, parser.WithMode("count"))
+	require.NoError(t, err)
+
+	t.Run("without mode line", func(t *testing.T) {
+		profiles, err := p.Parse(strings.NewReader("a.go:1.1,2.2 1 3\na.go:3.1,4.2 1 0\n"))
+		require.NoError(t, err)
+		require.Equal(t, []*cover.Profile{
+			{
+				FileName: "a.go",
+				Mode:     "count",
+				Blocks: []cover.ProfileBlock{
+					{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 1, Count: 3},
+					{StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 1, Count: 0},
+				},
+			},
+		}, profiles)
+	})
+
+	t.Run("mode line wins", func(t *testing.T) {
+		profiles, err := p.Parse(strings.NewReader("mode: set\na.go:1.1,2.2 1 1\n"))
+		require.NoError(t, err)
+		require.Len(t, profiles, 1)
+		require.Equal(t, "set", profiles[0].Mode)
+	})
+
+	t.Run("invalid first line", func(t *testing.T) {
+		_, err := p.Parse(strings.NewReader("mode: \n"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `line 1: "mode: " doesn't match expected format`)
+	})
+}
@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Decompress returns a reader of the decompressed profile if it is gzipped,
+// that is if the name ends with ".gz" or the content starts with the gzip
+// header. Other profiles are read as is.
+func Decompress(r io.Reader, name string) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	header, err := br.Peek(len(gzipMagic))
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+
+	if !strings.HasSuffix(name, ".gz") && string(header) != string(gzipMagic) {
+		return br, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress profile: %w", err)
+	}
+
+	return gzipReader{zr}, nil
+}
+
+// gzipReader reports corrupt gzip streams as such, instead of as failures to
+// read the profile.
+type gzipReader struct{ r *gzip.Reader }
+
+func (g gzipReader) Read(p []byte) (int, error) {
+	n, err := g.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("failed to decompress profile: %w", err)
+	}
+
+	return n, err
+}
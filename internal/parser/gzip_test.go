@@ -0,0 +1,97 @@
+package parser_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/stretchr/testify/require"
+)
+
+const plainProfile = "mode: set\nexample.com/a/a.go:3.15,5.2 1 1\n"
+
+func gzipped(t *testing.T, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestDecompress(t *testing.T) {
+	read := func(t *testing.T, content []byte, name string) (string, error) {
+		t.Helper()
+
+		r, err := parser.Decompress(bytes.NewReader(content), name)
+		if err != nil {
+			return "", err
+		}
+
+		bs, err := io.ReadAll(r)
+
+		return string(bs), err
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		s, err := read(t, []byte(plainProfile), "coverage.out")
+		require.NoError(t, err)
+		require.Equal(t, plainProfile, s)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		s, err := read(t, nil, "coverage.out")
+		require.NoError(t, err)
+		require.Empty(t, s)
+	})
+
+	t.Run("gzip extension", func(t *testing.T) {
+		s, err := read(t, gzipped(t, plainProfile), "coverage.out.gz")
+		require.NoError(t, err)
+		require.Equal(t, plainProfile, s)
+	})
+
+	t.Run("gzip header", func(t *testing.T) {
+		s, err := read(t, gzipped(t, plainProfile), "coverage.out")
+		require.NoError(t, err)
+		require.Equal(t, plainProfile, s)
+	})
+
+	t.Run("not gzipped", func(t *testing.T) {
+		_, err := read(t, []byte(plainProfile), "coverage.out.gz")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decompress profile: gzip: invalid header")
+	})
+
+	t.Run("corrupt", func(t *testing.T) {
+		content := gzipped(t, plainProfile)
+
+		_, err := read(t, content[:len(content)-4], "coverage.out.gz")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to decompress profile")
+	})
+}
+
+func TestGoGzipped(t *testing.T) {
+	p, err := parser.New(parser.FormatGo)
+	require.NoError(t, err)
+
+	r, err := parser.Decompress(bytes.NewReader(gzipped(t, plainProfile)), "coverage.out.gz")
+	require.NoError(t, err)
+
+	profiles, err := p.Parse(r)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+	require.Equal(t, "example.com/a/a.go", profiles[0].FileName)
+
+	expected, err := p.Parse(strings.NewReader(plainProfile))
+	require.NoError(t, err)
+	require.Equal(t, expected, profiles)
+}
@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// lcovParser reads LCOV .info files. Every DA record becomes a block of a
+// single statement, other records are ignored.
+type lcovParser struct{}
+
+func (lcovParser) Parse(r io.Reader) ([]*cover.Profile, error) {
+	hitsByFile := map[string]map[int]int{}
+
+	// hits of the file in the current record, nil outside of records
+	var hits map[int]int
+
+	scanner := bufio.NewScanner(r)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			filename := strings.TrimPrefix(line, "SF:")
+			if filename == "" {
+				return nil, fmt.Errorf("failed to parse lcov: line %d: empty source file name", lineNum)
+			}
+
+			// several records can refer to the same file, for example one
+			// for every test
+			if hits = hitsByFile[filename]; hits == nil {
+				hits = map[int]int{}
+				hitsByFile[filename] = hits
+			}
+
+		case strings.HasPrefix(line, "DA:"):
+			if hits == nil {
+				return nil, fmt.Errorf("failed to parse lcov: line %d: DA record outside of a source file", lineNum)
+			}
+
+			number, count, err := parseLCOVLine(strings.TrimPrefix(line, "DA:"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse lcov: line %d: %w", lineNum, err)
+			}
+
+			hits[number] += count
+
+		case line == "end_of_record":
+			hits = nil
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lcov: %w", err)
+	}
+
+	return lineProfiles(hitsByFile), nil
+}
+
+// parseLCOVLine parses "<line number>,<execution count>[,<checksum>]".
+func parseLCOVLine(s string) (int, int, error) {
+	fields := strings.Split(s, ",")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, 0, fmt.Errorf("invalid DA record %q", s)
+	}
+
+	number, err := strconv.Atoi(fields[0])
+	if err != nil || number < 1 {
+		return 0, 0, fmt.Errorf("invalid line number in DA record %q", s)
+	}
+
+	count, err := strconv.Atoi(fields[1])
+	if err != nil || count < 0 {
+		return 0, 0, fmt.Errorf("invalid execution count in DA record %q", s)
+	}
+
+	return number, count, nil
+}
@@ -0,0 +1,108 @@
+package parser_test
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/cover"
+)
+
+func TestLCOV(t *testing.T) {
+	p, err := parser.New(parser.FormatLCOV)
+	require.NoError(t, err)
+
+	bs, err := os.ReadFile("testdata/lcov.info")
+	require.NoError(t, err)
+
+	profiles, err := p.Parse(strings.NewReader(string(bs)))
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+	require.Equal(t, "src/math.js", profiles[0].FileName)
+	require.Equal(t, "src/missing.js", profiles[1].FileName)
+
+	// every line record of the input is available in the profiles, and
+	// nothing else is
+	require.Equal(t, lcovRecords(t, string(bs)), profileRecords(profiles))
+}
+
+func TestLCOVMergesRecords(t *testing.T) {
+	p, err := parser.New(parser.FormatLCOV)
+	require.NoError(t, err)
+
+	profiles, err := p.Parse(strings.NewReader(
+		"TN:one\nSF:a.c\nDA:2,1,abc\nDA:1,0\nend_of_record\nTN:two\nSF:a.c\nDA:1,2\nend_of_record\nSF:b.c\nend_of_record\n",
+	))
+	require.NoError(t, err)
+	require.Equal(t, []*cover.Profile{
+		{
+			FileName: "a.c",
+			Mode:     "count",
+			Blocks: []cover.ProfileBlock{
+				{StartLine: 1, StartCol: 1, EndLine: 1, EndCol: 1, NumStmt: 1, Count: 2},
+				{StartLine: 2, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 1},
+			},
+		},
+	}, profiles)
+}
+
+func TestLCOVInvalid(t *testing.T) {
+	p, err := parser.New(parser.FormatLCOV)
+	require.NoError(t, err)
+
+	for name, input := range map[string]string{
+		"no source file":  "DA:1,1\n",
+		"after record":    "SF:a.c\nDA:1,1\nend_of_record\nDA:2,1\n",
+		"empty file name": "SF:\n",
+		"no count":        "SF:a.c\nDA:1\n",
+		"bad line number": "SF:a.c\nDA:x,1\n",
+		"bad count":       "SF:a.c\nDA:1,-1\n",
+	} {
+		_, err := p.Parse(strings.NewReader(input))
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "failed to parse lcov", name)
+	}
+}
+
+// lcovRecords returns "file:line,count" of every DA record in the input.
+func lcovRecords(t *testing.T, input string) []string {
+	t.Helper()
+
+	var (
+		records []string
+		file    string
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if strings.HasPrefix(line, "SF:") {
+			file = strings.TrimPrefix(line, "SF:")
+		}
+
+		if strings.HasPrefix(line, "DA:") {
+			records = append(records, file+":"+strings.TrimPrefix(line, "DA:"))
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return records
+}
+
+func profileRecords(profiles []*cover.Profile) []string {
+	var records []string
+
+	for _, p := range profiles {
+		for _, b := range p.Blocks {
+			records = append(records, fmt.Sprintf("%s:%d,%d", p.FileName, b.StartLine, b.Count))
+		}
+	}
+
+	return records
+}
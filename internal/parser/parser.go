@@ -0,0 +1,93 @@
+// Package parser reads coverage reports of different formats into the common
+// representation used by the rest of the program: Go coverage profiles.
+package parser
+
+import (
+	"fmt"
+
+	gocovshparser "github.com/orlangure/gocovsh/parser"
+)
+
+// Format is the format of a coverage report.
+type Format string
+
+// Supported formats.
+const (
+	// FormatGo is the format of go test -coverprofile.
+	FormatGo Format = "go"
+
+	// FormatCobertura is the Cobertura XML format. Only line coverage is
+	// supported.
+	FormatCobertura Format = "cobertura"
+
+	// FormatLCOV is the format of LCOV .info files. Only line coverage is
+	// supported.
+	FormatLCOV Format = "lcov"
+)
+
+// Formats lists all supported formats.
+var Formats = []Format{FormatGo, FormatCobertura, FormatLCOV}
+
+// IsValid reports whether the format is supported.
+func (f Format) IsValid() bool {
+	for _, format := range Formats {
+		if f == format {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Modes lists the modes of Go coverage profiles.
+var Modes = []string{"set", "count", "atomic"}
+
+// IsValidMode reports whether the mode of a Go coverage profile is known.
+func IsValidMode(mode string) bool {
+	for _, m := range Modes {
+		if mode == m {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parser reads coverage reports. It is the public interface, so that custom
+// parsers can be passed to gocovsh from outside the module.
+type Parser = gocovshparser.Parser
+
+// Option configures a parser.
+type Option func(*options)
+
+type options struct {
+	mode string
+}
+
+// WithMode sets the mode assumed for Go profiles without a mode line, such
+// as the ones mangled by other tools. The mode line of a profile takes
+// precedence. Other formats ignore it.
+func WithMode(mode string) Option {
+	return func(o *options) {
+		o.mode = mode
+	}
+}
+
+// New returns a parser of the provided format.
+func New(format Format, opts ...Option) (Parser, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch format {
+	case FormatGo, "":
+		return goParser{mode: o.mode}, nil
+	case FormatCobertura:
+		return coberturaParser{}, nil
+	case FormatLCOV:
+		return lcovParser{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,78 @@
+// Package pkgpattern matches import paths of packages against patterns such
+// as "./internal/..." or "github.com/me/proj/cmd/...", following the
+// semantics of the go command.
+package pkgpattern
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Pattern matches import paths of packages. Use New to create a new
+// instance.
+type Pattern struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+// New creates a pattern. Patterns starting with "./", and "." itself, are
+// relative to the module root, and are resolved using the module path.
+//
+// "..." in the pattern matches any string, including an empty one and one
+// with slashes. A trailing "/..." also matches the path without it, so
+// "net/..." matches both "net" and "net/http".
+func New(pattern, module string) Pattern {
+	resolved := resolve(pattern, module)
+
+	re := strings.ReplaceAll(regexp.QuoteMeta(resolved), `\.\.\.`, `.*`)
+	if strings.HasSuffix(re, `/.*`) {
+		re = strings.TrimSuffix(re, `/.*`) + `(/.*)?`
+	}
+
+	return Pattern{
+		pattern: pattern,
+		re:      regexp.MustCompile("^" + re + "$"),
+	}
+}
+
+// Split parses a comma-separated list of patterns, skipping empty ones.
+func Split(list string) []string {
+	var patterns []string
+
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// Match reports whether the import path matches the pattern.
+func (p Pattern) Match(importPath string) bool {
+	return p.re.MatchString(importPath)
+}
+
+// String returns the pattern as it was provided.
+func (p Pattern) String() string {
+	return p.pattern
+}
+
+func resolve(pattern, module string) string {
+	if pattern != "." && !strings.HasPrefix(pattern, "./") {
+		return pattern
+	}
+
+	rel := strings.TrimPrefix(strings.TrimPrefix(pattern, "."), "/")
+
+	switch {
+	case module == "" && rel == "":
+		return "."
+	case module == "":
+		return rel
+	case rel == "":
+		return module
+	default:
+		return module + "/" + rel
+	}
+}
@@ -0,0 +1,57 @@
+package pkgpattern_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/pkgpattern"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	const module = "example.com/proj"
+
+	tests := []struct {
+		pattern    string
+		importPath string
+		match      bool
+	}{
+		{"./...", "example.com/proj", true},
+		{"./...", "example.com/proj/internal/model", true},
+		{"./...", "example.com/other", false},
+		{".", "example.com/proj", true},
+		{".", "example.com/proj/cmd", false},
+		{"./internal/...", "example.com/proj/internal", true},
+		{"./internal/...", "example.com/proj/internal/model", true},
+		{"./internal/...", "example.com/proj/internalfoo", false},
+		{"./internal", "example.com/proj/internal/model", false},
+		{"example.com/proj/cmd/...", "example.com/proj/cmd/gocovsh", true},
+		{"example.com/proj/cmd/...", "example.com/proj", false},
+		{"example.com/.../model", "example.com/proj/internal/model", true},
+		{"example.com/proj/internal/mod...", "example.com/proj/internal/model", true},
+		{"example.com/proj/internal/mod...", "example.com/proj/internal/model/sub", true},
+		{"example.com/proj/in.ernal", "example.com/proj/internal", false},
+		{"...", "anything/at/all", true},
+	}
+
+	for _, test := range tests {
+		p := pkgpattern.New(test.pattern, module)
+		require.Equal(t, test.match, p.Match(test.importPath), "%s matching %s", test.pattern, test.importPath)
+	}
+
+	t.Run("without module", func(t *testing.T) {
+		require.True(t, pkgpattern.New("./internal/...", "").Match("internal/model"))
+		require.True(t, pkgpattern.New(".", "").Match("."))
+		require.True(t, pkgpattern.New("./...", "").Match("internal/model"))
+	})
+
+	require.Equal(t, "./internal/...", pkgpattern.New("./internal/...", module).String())
+}
+
+func TestSplit(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{"./internal/...", "./cmd/..."}, pkgpattern.Split(" ./internal/..., ,./cmd/...,"))
+	require.Nil(t, pkgpattern.Split(""))
+}
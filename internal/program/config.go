@@ -0,0 +1,119 @@
+package program
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/orlangure/gocovsh/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// configFilename is the name of the configuration file in the working
+// directory. In the user configuration directory, the file is
+// gocovsh/config.yaml.
+const configFilename = ".gocovsh.yaml"
+
+// config holds the defaults of the flags. Values of the configuration files
+// override the built-in defaults, environment variables override the
+// configuration files, and the flags override everything.
+type config struct {
+	Profile   string  `yaml:"profile"`
+	Sort      string  `yaml:"sort"`
+	Theme     string  `yaml:"theme"`
+	Threshold float64 `yaml:"threshold"`
+	Session   bool    `yaml:"session"`
+
+	// Targets are the minimum coverage percentages of the paths matching
+	// the globs; the most specific glob wins
+	Targets map[string]float64 `yaml:"targets"`
+}
+
+// withDefaults returns the config with the built-in defaults of the values
+// that are not set.
+func (c config) withDefaults() config {
+	if c.Profile == "" {
+		c.Profile = defaultProfileFilename
+	}
+
+	if c.Sort == "" {
+		c.Sort = string(model.SortByPath)
+	}
+
+	return c
+}
+
+// loadConfig reads the user configuration file, and then the one of the
+// code root, so that project settings take precedence. Missing files are
+// skipped. Environment variables are applied last. Built-in defaults are not
+// applied, so that it is known which values are set.
+func (p *Program) loadConfig() (config, error) {
+	var cfg config
+
+	for _, filename := range p.configFiles() {
+		if err := readConfig(filename, &cfg); err != nil {
+			cfg = config{}
+			cfg.applyEnv()
+
+			return cfg, err
+		}
+	}
+
+	cfg.applyEnv()
+
+	return cfg, nil
+}
+
+// applyEnv overrides the values that are set in the environment. Empty
+// variables are ignored.
+func (c *config) applyEnv() {
+	for name, value := range map[string]*string{
+		"GOCOVSH_PROFILE": &c.Profile,
+		"GOCOVSH_SORT":    &c.Sort,
+		"GOCOVSH_THEME":   &c.Theme,
+	} {
+		if v := os.Getenv(name); v != "" {
+			*value = v
+		}
+	}
+}
+
+func (p *Program) configFiles() []string {
+	var files []string
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir, _ = os.UserConfigDir()
+	}
+
+	if dir != "" {
+		files = append(files, filepath.Join(dir, "gocovsh", "config.yaml"))
+	}
+
+	return append(files, filepath.Join(p.codeRoot, configFilename))
+}
+
+// readConfig decodes the file into cfg, keeping the values that are not set
+// in the file.
+func readConfig(filename string, cfg *config) error {
+	bs, err := os.ReadFile(filename) // nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(bs))
+	dec.KnownFields(true)
+
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse %s: %w", filename, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package program_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/program"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	files := func(t *testing.T, args ...string) []string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("testdata/config"),
+			program.WithFlagSet(flagSet, append([]string{"-json"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		var r struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+
+		paths := make([]string, 0, len(r.Files))
+		for _, f := range r.Files {
+			paths = append(paths, f.Path)
+		}
+
+		return paths
+	}
+
+	t.Run("project config", func(t *testing.T) {
+		require.Equal(t, []string{longName, "covered.go"}, files(t))
+	})
+
+	t.Run("flags override config", func(t *testing.T) {
+		require.Equal(t, []string{"covered.go", longName}, files(t, "-sort", "path"))
+	})
+
+	t.Run("project config overrides user config", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", dir)
+
+		writeConfig(t, filepath.Join(dir, "gocovsh", "config.yaml"), "sort: coverage-desc\nprofile: missing.cover\n")
+
+		require.Equal(t, []string{longName, "covered.go"}, files(t))
+	})
+
+	t.Run("environment overrides config", func(t *testing.T) {
+		t.Setenv("GOCOVSH_SORT", "coverage-desc")
+
+		require.Equal(t, []string{"covered.go", longName}, files(t))
+		require.Equal(t, []string{longName, "covered.go"}, files(t, "-sort", "coverage-asc"))
+	})
+
+	t.Run("profile from environment", func(t *testing.T) {
+		t.Setenv("GOCOVSH_PROFILE", "missing.cover")
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithCodeRoot("testdata/config"),
+			program.WithFlagSet(flagSet, []string{"-json"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "missing.cover")
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"GOCOVSH_SORT":  `invalid sort mode "size"`,
+			"GOCOVSH_THEME": `invalid theme "size"`,
+		} {
+			t.Setenv(name, "size")
+
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithCodeRoot("testdata/config"),
+				program.WithFlagSet(flagSet, []string{"-json"}),
+			)
+
+			err := p.Run()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), expected)
+
+			t.Setenv(name, "")
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		dir := t.TempDir()
+		writeConfig(t, filepath.Join(dir, ".gocovsh.yaml"), "sort-mode: path\n")
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithCodeRoot(dir),
+			program.WithFlagSet(flagSet, nil),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to load config")
+		require.Contains(t, err.Error(), "field sort-mode not found")
+	})
+
+	t.Run("missing config", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithCodeRoot(t.TempDir()),
+			program.WithFlagSet(flagSet, []string{"-json"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "failed to load config")
+	})
+}
+
+func writeConfig(t *testing.T, filename, content string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filename), 0o750))
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+}
@@ -0,0 +1,94 @@
+package program_test
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/orlangure/gocovsh/internal/gocovshtest/input"
+	"github.com/orlangure/gocovsh/internal/program"
+	"golang.org/x/tools/cover"
+)
+
+// lineParser reads a made up format: every line is a file name, a line
+// number and the number of times the line was executed.
+type lineParser struct{}
+
+func (lineParser) Parse(r io.Reader) ([]*cover.Profile, error) {
+	var profiles []*cover.Profile
+
+	byName := map[string]*cover.Profile{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+
+		line, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid line %q: %w", fields[1], err)
+		}
+
+		count, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count %q: %w", fields[2], err)
+		}
+
+		p, ok := byName[fields[0]]
+		if !ok {
+			p = &cover.Profile{FileName: fields[0], Mode: "count"}
+			byName[fields[0]] = p
+			profiles = append(profiles, p)
+		}
+
+		p.Blocks = append(p.Blocks, cover.ProfileBlock{
+			StartLine: line, StartCol: 1, EndLine: line, EndCol: 2, NumStmt: 1, Count: count,
+		})
+	}
+
+	return profiles, scanner.Err()
+}
+
+// A custom parser replaces the built-in one selected by -format. Programs
+// outside of the module pass it to cli.WithParser instead, as in
+// examples/custom-parser.
+func ExampleWithParser() {
+	report := "covered.go 3 1\ncovered.go 4 0\n"
+
+	buf := bytes.NewBuffer(nil)
+	p := program.New(
+		program.WithParser(lineParser{}),
+		program.WithOutput(buf),
+		program.WithInput(input.NewMockFile(report, os.ModeNamedPipe)),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flag.NewFlagSet("example", flag.ContinueOnError), []string{"-profile", "-", "-json"}),
+	)
+
+	if err := p.Run(); err != nil {
+		fmt.Println(err)
+	}
+
+	fmt.Print(buf.String())
+	// Output:
+	// {
+	//   "covered": 1,
+	//   "files": [
+	//     {
+	//       "covered": 1,
+	//       "path": "covered.go",
+	//       "percentage": 50,
+	//       "total": 2
+	//     }
+	//   ],
+	//   "mode": "count",
+	//   "percentage": 50,
+	//   "total": 2
+	// }
+}
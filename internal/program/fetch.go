@@ -0,0 +1,74 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultProfileTimeout limits the time to fetch the profile from -profile-url.
+const defaultProfileTimeout = 30 * time.Second
+
+// loadProfileURL fetches the profile from -profile-url, unless another one is
+// requested.
+func (p *Program) loadProfileURL() error {
+	if p.profileContent != nil || p.isFlagPassed("profile") {
+		return fmt.Errorf("-profile-url can't be used with -profile or a coverage profile in stdin")
+	}
+
+	if p.watch {
+		return fmt.Errorf("coverage profile from a URL can't be watched")
+	}
+
+	if p.profileTimeout <= 0 {
+		return fmt.Errorf("invalid profile-timeout %v: must be positive", p.profileTimeout)
+	}
+
+	return p.fetchProfile()
+}
+
+// fetchProfile downloads the coverage profile from -profile-url, sending the
+// headers of -profile-header, such as the ones authorizing the request.
+func (p *Program) fetchProfile() error {
+	req, err := http.NewRequest(http.MethodGet, p.profileURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid profile URL %q: %w", p.profileURL, err)
+	}
+
+	for _, h := range p.profileHeaders {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid profile header %q: must be Name: value", h)
+		}
+
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	client := &http.Client{Timeout: p.profileTimeout}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return fmt.Errorf("failed to fetch coverage profile: no response from %s within %s", p.profileURL, p.profileTimeout)
+		}
+
+		return fmt.Errorf("failed to fetch coverage profile: %w", err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch coverage profile: %s responded with %s", p.profileURL, resp.Status)
+	}
+
+	if p.profileContent, err = io.ReadAll(resp.Body); err != nil {
+		return fmt.Errorf("failed to fetch coverage profile: %w", err)
+	}
+
+	return nil
+}
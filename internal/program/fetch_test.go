@@ -0,0 +1,86 @@
+package program_test
+
+import (
+	"bytes"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/orlangure/gocovsh/internal/program"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileURL(t *testing.T) {
+	profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coverage.out":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_, _ = w.Write(profile)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	run := func(args ...string) (string, error) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-json"}, args...)),
+		)
+
+		err := p.Run()
+
+		return buf.String(), err
+	}
+
+	t.Run("fetched", func(t *testing.T) {
+		out, err := run("-profile-url", srv.URL+"/coverage.out", "-profile-header", "Authorization: Bearer secret")
+		require.NoError(t, err)
+		require.Contains(t, out, `"percentage": 80`)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/coverage.out")
+		require.EqualError(t, err, "failed to fetch coverage profile: "+srv.URL+"/coverage.out responded with 401 Unauthorized")
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/missing.out", "-profile-header", "Authorization: Bearer secret")
+		require.EqualError(t, err, "failed to fetch coverage profile: "+srv.URL+"/missing.out responded with 404 Not Found")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/slow", "-profile-timeout", "50ms")
+		require.EqualError(t, err, "failed to fetch coverage profile: no response from "+srv.URL+"/slow within 50ms")
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/coverage.out", "-profile-header", "Bearer secret")
+		require.EqualError(t, err, `invalid profile header "Bearer secret": must be Name: value`)
+	})
+
+	t.Run("with profile", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/coverage.out", "-profile", "profile.cover")
+		require.EqualError(t, err, "-profile-url can't be used with -profile or a coverage profile in stdin")
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := run("-profile-url", srv.URL+"/coverage.out", "-profile-timeout", "0s")
+		require.EqualError(t, err, "invalid profile-timeout 0s: must be positive")
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"io/fs"
 	"runtime/debug"
+
+	"github.com/orlangure/gocovsh/internal/parser"
 )
 
 // Option is a function that can be passed to WithOptions.
@@ -36,6 +38,14 @@ func WithLogFile(path string) Option {
 	}
 }
 
+// WithCodeRoot sets the root directory of the code to be analyzed. The
+// coverage profile and go.mod file are looked up relative to it.
+func WithCodeRoot(root string) Option {
+	return func(p *Program) {
+		p.codeRoot = root
+	}
+}
+
 // WithFlagSet is an optional way to set the flag set for the program. Useful
 // in tests.
 func WithFlagSet(fs *flag.FlagSet, args []string) Option {
@@ -49,18 +59,27 @@ func WithFlagSet(fs *flag.FlagSet, args []string) Option {
 	}
 }
 
-// WithOutput sets the stdout writer for the program. This should be used
-// for testing some of the basic outputs (non-interactive).
+// WithOutput sets the stdout writer for the program. The UI is rendered to it
+// too, so that the program can be tested or embedded.
 func WithOutput(w io.Writer) Option {
 	return func(p *Program) {
 		p.output = w
 	}
 }
 
-// WithInput sets the stdin for the program. This should be used for testing
-// the features that read from stdin.
+// WithInput sets the stdin for the program. Unless it is a file that is not a
+// terminal, the UI reads the keys from it.
 func WithInput(file fs.File) Option {
 	return func(p *Program) {
 		p.input = file
 	}
 }
+
+// WithParser reads the coverage profile using a custom parser instead of the
+// built-in one selected by -format, so that gocovsh can be built with support
+// for other formats.
+func WithParser(custom parser.Parser) Option {
+	return func(p *Program) {
+		p.parser = custom
+	}
+}
@@ -3,63 +3,390 @@
 package program
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/orlangure/gocovsh/internal/browser"
+	"github.com/orlangure/gocovsh/internal/codeview"
+	"github.com/orlangure/gocovsh/internal/export"
+	"github.com/orlangure/gocovsh/internal/gitignore"
 	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/orlangure/gocovsh/internal/parser"
+	"github.com/orlangure/gocovsh/internal/pkgpattern"
+	"github.com/orlangure/gocovsh/internal/report"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/internal/targets"
 	"github.com/waigani/diffparser"
+	"golang.org/x/term"
+	"golang.org/x/tools/cover"
 )
 
 const (
 	defaultProfileFilename = "coverage.out"
+	stdinProfileFilename   = "-"
+	profileWatchInterval   = time.Second
 	usageHeader            = `gocovsh: Go Coverage in your terminal
 
-Usage: %s [options]
+Usage: %[1]s [options] [file.go ...]
+
+Files passed as arguments are the only ones listed, and the first of them is
+opened right away:
+
+	%[1]s internal/program/program.go
+
+Arguments after -- are files even if they start with a dash:
+
+	%[1]s -- -weird-name.go
 
 If provided, stdin is expected to be a list of files to be processed, for example:
 
-	git diff --name-only | %s
+	git diff --name-only | %[1]s
+
+Stdin can also be a diff, or the coverage profile itself:
+
+	git diff | %[1]s
+	go test -coverprofile /dev/stdout ./... | %[1]s -profile -
+
+With "-profile -", stdin is always read as the coverage profile. Otherwise, it
+is read as the coverage profile only when it starts with a "mode: " line, and
+as a diff when it starts with "diff ". Anything else is a list of files.
 
 Supported options:
 
 `
 )
 
+var profileModePattern = regexp.MustCompile(`^mode: (set|count|atomic)\s*$`)
+
 // New return a new Program instance. Optional configuration is available using
 // `With...` functions.
 func New(opts ...Option) *Program {
 	p := &Program{
-		input:   os.Stdin,
-		output:  os.Stdout,
-		flagSet: flag.CommandLine,
-		args:    os.Args[1:],
+		input:    os.Stdin,
+		output:   os.Stdout,
+		flagSet:  flag.CommandLine,
+		args:     os.Args[1:],
+		codeRoot: ".",
 	}
 
 	for _, opt := range opts {
 		opt(p)
 	}
 
-	p.flagSet.BoolVar(&p.showVersion, "version", false, "show version")
-	p.flagSet.BoolVar(&p.sortByCoverage, "sort-by-coverage", false, "sort files by coverage instead of alphabetically")
+	cfg, err := p.loadConfig()
+	if err != nil {
+		p.configErr = err
+	}
+
+	p.config = cfg
+	cfg = cfg.withDefaults()
+
+	p.flagSet.BoolVar(&p.showVersion, "version", false, "show version; use -json for JSON")
+	p.flagSet.StringVar(
+		&p.format, "format", string(parser.FormatGo),
+		"Format of the coverage profile: "+strings.Join(formatNames(), ", "),
+	)
+	p.flagSet.StringVar(
+		&p.mode, "mode", "",
+		"Mode assumed for Go coverage profiles without a mode line: "+strings.Join(parser.Modes, ", "),
+	)
+	p.flagSet.StringVar(
+		&p.sortMode, "sort", cfg.Sort,
+		"Order of files: "+strings.Join(sortModeNames(), ", "),
+	)
+	p.flagSet.BoolVar(&p.sortByCoverage, "sort-by-coverage", false, "deprecated: use -sort coverage-asc")
+	p.flagSet.Float64Var(
+		&p.threshold, "threshold", cfg.Threshold,
+		"Highlight files with coverage below this percentage (0-100, 0 disables highlighting)",
+	)
+	p.flagSet.Float64Var(
+		&p.failUnder, "fail-under", 0,
+		"Print total coverage and exit with an error if it is below this percentage (0-100), without starting the UI",
+	)
+	p.flagSet.IntVar(
+		&p.minFiles, "min-files", 0,
+		"Exit with an error if fewer files of the coverage profile are selected, without starting the UI",
+	)
+	p.flagSet.IntVar(
+		&p.limit, "limit", 0,
+		"Only show the first files once they are sorted, also in the printed reports",
+	)
+	p.flagSet.BoolVar(
+		&p.summary, "summary", false,
+		"Print the coverage of every file as an aligned table instead of starting the UI",
+	)
+	p.flagSet.BoolVar(
+		&p.markdown, "markdown", false,
+		"Print the coverage of every file as a Markdown table instead of starting the UI, with a status column if -threshold or targets are set",
+	)
+	p.flagSet.BoolVar(
+		&p.quiet, "quiet", false,
+		"Only print the requested output and errors, without log messages and notes",
+	)
+	p.flagSet.BoolVar(&p.jsonOutput, "json", false, "print coverage of every file as JSON instead of starting the UI")
+	p.flagSet.BoolVar(
+		&p.diffReport, "diff-report", false,
+		"Print coverage of the changed lines of a diff piped to stdin instead of starting the UI; use -json for JSON",
+	)
+	p.flagSet.BoolVar(&p.watch, "watch", false, "reload the coverage profile when it changes")
+	p.flagSet.BoolVar(&p.confirmQuit, "confirm-quit", true, "ask to confirm quitting in -watch mode")
+	p.flagSet.DurationVar(
+		&p.typeAheadTimeout, "type-ahead-timeout", model.DefaultTypeAheadTimeout,
+		"Time after the last letter typed in the list when the next one starts a new name to jump to; 0 disables it",
+	)
+	p.flagSet.StringVar(
+		&p.testCommand, "test-cmd", "",
+		"Command that regenerates the coverage profile when r is pressed (default \"go test -coverprofile=<profile> ./...\")",
+	)
+	p.flagSet.BoolVar(
+		&p.forceTUI, "force-tui", false,
+		"Start the UI even if the output or stdin is not a terminal; otherwise a coverage summary is printed",
+	)
+	p.flagSet.BoolVar(
+		&p.printSelection, "print-selection", false,
+		"Print the path of the last opened file on exit, for scripts; nothing is printed if no file was opened",
+	)
+	p.flagSet.BoolVar(
+		&p.session, "session", cfg.Session,
+		"Remember the sort mode, theme and last opened file between runs",
+	)
+	p.flagSet.BoolVar(
+		&p.tree, "tree", false,
+		"Group files by directory in the list, with the coverage of every directory; toggle with t",
+	)
+	p.flagSet.BoolVar(
+		&p.uncoveredOnly, "uncovered-only", false,
+		"Fold covered code, showing only uncovered blocks and the lines around them; toggle with U",
+	)
+	p.flagSet.BoolVar(
+		&p.diffOnly, "diff-only", false,
+		"Measure coverage of the changed lines of a diff piped to stdin, and only show them",
+	)
+	p.flagSet.BoolVar(
+		&p.diffView, "diff-view", false,
+		"Show the hunks of a diff piped to stdin instead of whole files, with the coverage of the added lines; toggle with D",
+	)
+	p.flagSet.BoolVar(
+		&p.minimap, "minimap", false,
+		"Show a strip next to the code summarizing the coverage of the whole file; toggle with |",
+	)
+	p.flagSet.IntVar(
+		&p.context, "context", codeview.DefaultFoldContext,
+		"Number of lines to show around uncovered blocks with -uncovered-only, or changed lines with -diff-only",
+	)
+	p.flagSet.StringVar(
+		&p.theme, "theme", cfg.Theme,
+		"Color theme: "+strings.Join(styles.ThemeNames(), ", "),
+	)
+	p.flagSet.StringVar(
+		&p.coveredColor, "covered-color", "",
+		"Color of covered code, overriding the theme: hex such as #00ff00, ANSI number 0-255, or name such as green",
+	)
+	p.flagSet.StringVar(&p.uncoveredColor, "uncovered-color", "", "Color of uncovered code, overriding the theme")
+	p.flagSet.StringVar(
+		&p.partialColor, "partial-color", "",
+		"Color of partially covered lines and their numbers, overriding the theme",
+	)
+	p.flagSet.StringVar(&p.coveredGlyph, "covered-glyph", "", "Marker of covered code without colors (default \"+\")")
+	p.flagSet.StringVar(&p.uncoveredGlyph, "uncovered-glyph", "", "Marker of uncovered code without colors (default \"-\")")
+	p.flagSet.BoolVar(
+		&p.syntax, "syntax", false,
+		"Highlight syntax of covered code; toggle with s",
+	)
+	p.flagSet.BoolVar(
+		&p.heatmap, "heatmap", false,
+		"Shade covered code by hit count in count and atomic mode profiles; toggle with H",
+	)
+	p.flagSet.BoolVar(
+		&p.legend, "legend", true,
+		"Explain the colors of covered and uncovered code below the file; toggle with c",
+	)
+	p.flagSet.StringVar(
+		&p.syntaxTheme, "syntax-theme", model.DefaultSyntaxTheme,
+		"Chroma style used for syntax highlighting, such as monokai, dracula or github",
+	)
+	p.flagSet.BoolVar(
+		&p.noColor, "no-color", false,
+		"Mark coverage with symbols instead of colors; also enabled by NO_COLOR or when output is not a terminal",
+	)
+	p.flagSet.BoolVar(
+		&p.patterns, "patterns", false,
+		"Border lines with glyphs of different shapes for covered and uncovered code, independent of colors",
+	)
 	p.flagSet.StringVar(
-		&p.profileFilename, "profile", defaultProfileFilename,
-		"File name of coverage profile generated by go test -coverprofile coverage.out",
+		&p.repoURLTemplate, "repo-url-template", browser.DefaultTemplate,
+		"Address opened in the browser with w, with {module}, {package}, {path}, {dir} and {line} placeholders",
+	)
+	p.flagSet.BoolVar(
+		&p.openFirst, "open-first", false,
+		"Open the first file of the list right away, such as the least covered one with -sort coverage-asc",
+	)
+	p.flagSet.StringVar(
+		&p.selectMatch, "select", "",
+		"Select the first file of the list whose path contains this substring, without opening it",
+	)
+	p.flagSet.BoolVar(
+		&p.inline, "inline", false,
+		"Render the UI in the scrollback of the terminal instead of the alternate screen",
+	)
+	p.flagSet.BoolVar(
+		&p.mouse, "mouse", true,
+		"Enable mouse support to select files and scroll; disable to select text in the terminal",
+	)
+	p.flagSet.StringVar(
+		&p.exportHTMLDir, "export-html", "",
+		"Export every file as annotated HTML into this directory instead of starting the UI",
+	)
+	p.flagSet.BoolVar(
+		&p.respectGitignore, "respect-gitignore", false,
+		"Skip files from stdin that are ignored by .gitignore files",
+	)
+	p.flagSet.StringVar(
+		&p.sourceRoot, "root", "",
+		"Look up source files missing from the current directory in this one, dropping leading path components",
+	)
+	p.flagSet.StringVar(
+		&p.filesList, "files", "",
+		"Only show the files listed in this file, one per line, instead of the ones piped to stdin",
+	)
+	p.flagSet.Var(
+		&p.stripPrefixes, "strip-prefix",
+		"Remove this prefix from the paths of the profile, such as _/home/runner/work/project/; repeat to remove several prefixes in order",
+	)
+	p.flagSet.StringVar(
+		&p.filter, "filter", "",
+		"Only show files with paths matching this regular expression",
+	)
+	p.flagSet.StringVar(
+		&p.include, "include", "",
+		"Only show files with paths matching any of these comma-separated globs, such as internal/**/*.go",
+	)
+	p.flagSet.StringVar(
+		&p.exclude, "exclude", "",
+		"Hide files with paths matching any of these comma-separated globs, such as **/*_mock.go; takes precedence over -include",
+	)
+	p.flagSet.BoolVar(
+		&p.excludeTestFiles, "exclude-test-files", false,
+		"Hide Go test files (*_test.go) from the profile and the requested files",
+	)
+	p.flagSet.BoolVar(
+		&p.bars, "bars", false,
+		"Show a bar of the coverage next to every file in the list, colored by the threshold; toggle with B",
+	)
+	p.flagSet.BoolVar(
+		&p.checkSources, "check-sources", false,
+		"Mark the files whose sources are not found when the profile is loaded, instead of when they are opened",
+	)
+	p.flagSet.BoolVar(
+		&p.gitInfo, "git-info", false,
+		"Show the last commit of the opened file next to its name, looked up with git",
+	)
+	p.flagSet.BoolVar(
+		&p.noStaleCheck, "no-stale-check", false,
+		"Don't mark the files modified after the coverage profile, whose covered lines may be wrong",
+	)
+	p.flagSet.BoolVar(
+		&p.ignoreComments, "ignore-comments", false,
+		"Exclude the lines marked with //gocovsh:ignore comments from the coverage, reading every source file on load",
+	)
+	p.flagSet.IntVar(
+		&p.workers, "workers", runtime.NumCPU(),
+		"Number of source files read at once when the coverage profile is loaded",
+	)
+	p.flagSet.BoolVar(
+		&p.hideGenerated, "hide-generated", false,
+		"Hide generated files, such as *.pb.go or the ones with a \"Code generated ... DO NOT EDIT.\" header; show them muted with x",
+	)
+	p.flagSet.StringVar(
+		&p.packages, "packages", "",
+		"Only show files of packages matching these comma-separated patterns, such as ./internal/... or example.com/mod/cmd/...",
+	)
+	p.flagSet.StringVar(
+		&p.profileFilename, "profile", cfg.Profile,
+		"File name of coverage profile generated by go test -coverprofile coverage.out, or - to read it from stdin",
+	)
+	p.flagSet.StringVar(
+		&p.profileURL, "profile-url", "",
+		"URL to fetch the coverage profile from instead of a file, such as an artifact published by CI",
+	)
+	p.flagSet.Var(
+		&p.profileHeaders, "profile-header",
+		"Header of the request fetching -profile-url, such as \"Authorization: Bearer $TOKEN\"; repeat to send several headers",
+	)
+	p.flagSet.DurationVar(
+		&p.profileTimeout, "profile-timeout", defaultProfileTimeout,
+		"Time to wait for -profile-url to be fetched",
+	)
+	p.flagSet.StringVar(
+		&p.compareFilename, "profile-compare", "",
+		"File name of another coverage profile to compare with, such as the one before a change; switch between them with tab",
 	)
 
 	p.flagSet.Usage = func() {
-		fmt.Fprintf(p.output, usageHeader, p.flagSet.Name(), p.flagSet.Name())
+		fmt.Fprintf(p.output, usageHeader, p.flagSet.Name())
 		p.flagSet.PrintDefaults()
 	}
 
 	return p
 }
 
+// versionInfo is the build information printed by -version -json.
+type versionInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit"`
+	Date       string `json:"date"`
+	ModVersion string `json:"modVersion"`
+	ModSum     string `json:"modSum"`
+}
+
+// writeVersion prints the build information, as JSON if -json is set.
+func (p *Program) writeVersion() error {
+	if p.jsonOutput {
+		enc := json.NewEncoder(p.output)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(versionInfo{
+			Version:    p.version,
+			Commit:     p.commit,
+			Date:       p.date,
+			ModVersion: p.modVersion,
+			ModSum:     p.modSum,
+		})
+	}
+
+	out := fmt.Sprintf(
+		"Version: %s\nCommit: %s\nDate: %s\n",
+		p.version, p.commit, p.date,
+	)
+	if p.modVersion != "" {
+		out += fmt.Sprintf(
+			"Module Version: %s\nModule Checksum: %s\n",
+			p.modVersion, p.modSum,
+		)
+	}
+
+	_, err := fmt.Fprint(p.output, out)
+
+	return err
+}
+
 // Program holds the program configuration.
 type Program struct {
 	version string
@@ -69,18 +396,93 @@ type Program struct {
 	modVersion string
 	modSum     string
 
-	showVersion     bool
-	profileFilename string
-	sortByCoverage  bool
+	showVersion      bool
+	profileFilename  string
+	compareFilename  string
+	format           string
+	parser           parser.Parser
+	mode             string
+	sortMode         string
+	sortByCoverage   bool
+	threshold        float64
+	failUnder        float64
+	minFiles         int
+	limit            int
+	jsonOutput       bool
+	summary          bool
+	markdown         bool
+	quiet            bool
+	diffReport       bool
+	watch            bool
+	confirmQuit      bool
+	typeAheadTimeout time.Duration
+	testCommand      string
+	forceTUI         bool
+	printSelection   bool
+	session          bool
+	tree             bool
+	uncoveredOnly    bool
+	diffOnly         bool
+	diffView         bool
+	minimap          bool
+	context          int
+	mouse            bool
+	inline           bool
+	openFirst        bool
+	selectMatch      string
+	repoURLTemplate  string
+	theme            string
+	coveredColor     string
+	uncoveredColor   string
+	partialColor     string
+	coveredGlyph     string
+	uncoveredGlyph   string
+	syntax           bool
+	syntaxTheme      string
+	heatmap          bool
+	patterns         bool
+	legend           bool
+	noColor          bool
+	exportHTMLDir    string
+	filter           string
+	include          string
+	exclude          string
+	hideGenerated    bool
+	excludeTestFiles bool
+	noStaleCheck     bool
+	ignoreComments   bool
+	workers          int
+	checkSources     bool
+	gitInfo          bool
+	bars             bool
+	packages         string
+	sourceRoot       string
+	stripPrefixes    stringList
+	profileURL       string
+	profileHeaders   stringList
+	profileTimeout   time.Duration
+	filesList        string
+	respectGitignore bool
+
+	flagSet  *flag.FlagSet
+	args     []string
+	input    fs.File
+	output   io.Writer
+	logFile  string
+	codeRoot string
+
+	config    config
+	configErr error
+	targets   targets.Targets
 
-	flagSet *flag.FlagSet
-	args    []string
-	input   fs.File
-	output  io.Writer
-	logFile string
+	sessionFile string
+	state       session
 
 	requestedFiles []string
 	diffLines      map[string][]int
+	diffHunks      map[string][]codeview.DiffHunk
+	profileContent []byte
+	openFile       string
 }
 
 // Run parses the command line arguments and runs the program.
@@ -89,20 +491,112 @@ func (p *Program) Run() error {
 		return fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	if p.configErr != nil {
+		return fmt.Errorf("failed to load config: %w", p.configErr)
+	}
+
+	// the profile is parsed with logging, which goes to stderr unless the
+	// UI is started
+	if p.quiet {
+		log.SetOutput(io.Discard)
+	}
+
 	if p.showVersion {
-		out := fmt.Sprintf(
-			"Version: %s\nCommit: %s\nDate: %s\n",
-			p.version, p.commit, p.date,
-		)
-		if p.modVersion != "" {
-			out += fmt.Sprintf(
-				"Module Version: %s\nModule Checksum: %s\n",
-				p.modVersion, p.modSum,
-			)
+		return p.writeVersion()
+	}
+
+	if p.threshold < 0 || p.threshold > 100 {
+		return fmt.Errorf("invalid threshold %v: must be between 0 and 100", p.threshold)
+	}
+
+	coverageTargets, err := targets.New(p.config.Targets)
+	if err != nil {
+		return fmt.Errorf("invalid coverage targets: %w", err)
+	}
+
+	p.targets = coverageTargets
+
+	if p.failUnder < 0 || p.failUnder > 100 {
+		return fmt.Errorf("invalid fail-under value %v: must be between 0 and 100", p.failUnder)
+	}
+
+	if p.typeAheadTimeout < 0 {
+		return fmt.Errorf("invalid type-ahead-timeout %v: must not be negative", p.typeAheadTimeout)
+	}
+
+	if p.minFiles < 0 {
+		return fmt.Errorf("invalid min-files value %d: must not be negative", p.minFiles)
+	}
+
+	if p.limit < 0 {
+		return fmt.Errorf("invalid limit %d: must not be negative", p.limit)
+	}
+
+	if p.workers < 1 {
+		return fmt.Errorf("invalid workers %d: must be positive", p.workers)
+	}
+
+	if p.limit > 0 && (p.minFiles > 0 || p.isFlagPassed("fail-under")) {
+		return fmt.Errorf("-limit can't be used with -min-files or -fail-under")
+	}
+
+	if p.session {
+		if err := p.restoreSession(); err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
 		}
+	}
+
+	if p.context < 0 {
+		return fmt.Errorf("invalid context %d: must not be negative", p.context)
+	}
+
+	if p.theme != "" && !styles.UseTheme(p.theme) {
+		return fmt.Errorf("invalid theme %q: must be one of %s", p.theme, strings.Join(styles.ThemeNames(), ", "))
+	}
+
+	if err := styles.CurrentTheme.Customize(styles.Customization{
+		CoveredColor:   p.coveredColor,
+		UncoveredColor: p.uncoveredColor,
+		PartialColor:   p.partialColor,
+		CoveredGlyph:   p.coveredGlyph,
+		UncoveredGlyph: p.uncoveredGlyph,
+	}); err != nil {
+		return err
+	}
 
-		_, err := fmt.Fprint(p.output, out)
+	if !model.IsValidSyntaxTheme(p.syntaxTheme) {
+		return fmt.Errorf("invalid syntax theme %q", p.syntaxTheme)
+	}
 
+	sortMode, err := p.resolveSortMode()
+	if err != nil {
+		return err
+	}
+
+	format := parser.Format(p.format)
+	if !format.IsValid() {
+		return fmt.Errorf("invalid format %q: must be one of %s", p.format, strings.Join(formatNames(), ", "))
+	}
+
+	if p.mode != "" && !parser.IsValidMode(p.mode) {
+		return fmt.Errorf("invalid mode %q: must be one of %s", p.mode, strings.Join(parser.Modes, ", "))
+	}
+
+	var fileFilter *regexp.Regexp
+
+	if p.filter != "" {
+		if fileFilter, err = regexp.Compile(p.filter); err != nil {
+			return fmt.Errorf("invalid filter %q: %w", p.filter, err)
+		}
+	}
+
+	includeGlobs, err := splitGlobs("include", p.include)
+	if err != nil {
+		return err
+	}
+
+	excludeGlobs, err := splitGlobs("exclude", p.exclude)
+	if err != nil {
 		return err
 	}
 
@@ -110,13 +604,125 @@ func (p *Program) Run() error {
 		return fmt.Errorf("failed to parse input: %w", err)
 	}
 
+	if p.diffOnly && p.diffLines == nil {
+		return fmt.Errorf("diff-only mode requires a diff in stdin")
+	}
+
+	if p.diffView && p.diffLines == nil {
+		return fmt.Errorf("diff view requires a diff in stdin")
+	}
+
+	if p.diffReport && p.diffLines == nil {
+		return fmt.Errorf("diff report requires a diff in stdin")
+	}
+
+	if p.selectMatch != "" && (p.openFile != "" || p.openFirst) {
+		return fmt.Errorf("-select can't be used with -open-first or files passed as arguments")
+	}
+
+	if p.profileURL != "" {
+		if err := p.loadProfileURL(); err != nil {
+			return err
+		}
+	}
+
+	if p.watch && p.profileContent != nil {
+		return fmt.Errorf("coverage profile from stdin can't be watched")
+	}
+
+	if p.compareFilename == stdinProfileFilename {
+		return fmt.Errorf("compared coverage profile can't be read from stdin")
+	}
+
+	color := p.isColorEnabled()
+	if !color {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
 	m := model.New(
+		model.WithColor(color),
+		model.WithCodeRoot(p.codeRoot),
+		model.WithSourceRoot(p.sourceRoot),
 		model.WithProfileFilename(p.profileFilename),
+		model.WithCompareProfile(p.compareFilename),
+		model.WithFormat(format),
+		model.WithParser(p.parser),
+		model.WithMode(p.mode),
+		model.WithProfileContent(p.profileContent),
 		model.WithRequestedFiles(p.requestedFiles),
-		model.WithCoverageSorting(p.sortByCoverage),
+		model.WithFileFilter(fileFilter),
+		model.WithStripPrefixes(p.stripPrefixes),
+		model.WithIncludeGlobs(includeGlobs),
+		model.WithExcludeGlobs(excludeGlobs),
+		model.WithExcludeTestFiles(p.excludeTestFiles),
+		model.WithHideGenerated(p.hideGenerated),
+		model.WithStaleCheck(!p.noStaleCheck),
+		model.WithIgnoreComments(p.ignoreComments),
+		model.WithWorkers(p.workers),
+		model.WithSourceCheck(p.checkSources),
+		model.WithGitInfo(p.gitInfo),
+		model.WithPackages(pkgpattern.Split(p.packages)),
+		model.WithSortMode(sortMode),
+		model.WithLimit(p.limit),
+		model.WithThreshold(p.threshold),
+		model.WithTargets(p.targets),
+		model.WithTree(p.tree),
+		model.WithInline(p.inline),
+		model.WithRepoURLTemplate(p.repoURLTemplate),
+		model.WithBars(p.bars),
+		model.WithSyntax(p.syntax),
+		model.WithSyntaxTheme(p.syntaxTheme),
+		model.WithHeatmap(p.heatmap),
+		model.WithPatterns(p.patterns),
+		model.WithLegend(p.legend),
+		model.WithUncoveredOnly(p.uncoveredOnly),
+		model.WithDiffOnly(p.diffOnly),
+		model.WithFoldContext(p.context),
 		model.WithFilteredLines(p.diffLines),
+		model.WithDiffHunks(p.diffHunks),
+		model.WithDiffView(p.diffView),
+		model.WithMinimap(p.minimap),
+		model.WithWatch(p.watchInterval()),
+		model.WithConfirmQuit(p.confirmQuit),
+		model.WithTypeAheadTimeout(p.typeAheadTimeout),
+		model.WithTestCommand(p.testCommand),
+		model.WithSelectedFile(p.selectedFile()),
+		model.WithSelectedMatch(p.selectMatch),
+		model.WithOpenSelected(p.openFile != ""),
+		model.WithOpenIndex(p.openIndex()),
+		model.WithBookmarks(p.sessionBookmarks()),
 	)
 
+	if p.minFiles > 0 {
+		if err := p.checkMinFiles(m); err != nil {
+			return err
+		}
+	}
+
+	if p.diffReport {
+		return p.writeDiffReport(m)
+	}
+
+	if p.isFlagPassed("fail-under") {
+		return p.checkCoverage(m)
+	}
+
+	if p.jsonOutput {
+		return p.writeJSON(m)
+	}
+
+	if p.exportHTMLDir != "" {
+		return p.exportHTML(m)
+	}
+
+	if p.summary {
+		return p.writeTable(m)
+	}
+
+	if p.markdown {
+		return p.writeMarkdown(m)
+	}
+
 	if p.logFile != "" {
 		f, err := tea.LogToFile(p.logFile, "gocovsh")
 		if err != nil {
@@ -125,44 +731,627 @@ func (p *Program) Run() error {
 
 		defer func() { _ = f.Close() }()
 
-		log.Println("logging to", p.logFile)
+		if !p.quiet {
+			log.Println("logging to", p.logFile)
+		}
 	} else {
 		log.SetOutput(io.Discard)
 	}
 
-	if err := tea.NewProgram(m, tea.WithAltScreen()).Start(); err != nil {
+	// without a terminal, for example in CI, the UI would wait for input
+	// forever
+	if !p.forceTUI && !p.isInteractive() {
+		log.Println("not a terminal, printing summary")
+		return p.writeSummary(m)
+	}
+
+	if err := p.checkProfileExists(); err != nil {
+		return err
+	}
+
+	if err := p.checkCompareProfileExists(); err != nil {
+		return err
+	}
+
+	if err := tea.NewProgram(m, p.teaOptions()...).Start(); err != nil {
 		return fmt.Errorf("failed to start program: %w", err)
 	}
 
+	if p.session {
+		if err := p.saveSession(m.SortMode(), m.OpenedFile(), m.Bookmarks()); err != nil {
+			return fmt.Errorf("failed to save session: %w", err)
+		}
+	}
+
+	if p.printSelection && m.OpenedFile() != "" {
+		if _, err := fmt.Fprintln(p.output, m.OpenedPath()); err != nil {
+			return fmt.Errorf("failed to print selection: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadProfiles loads the profiles of the requested files for the reports
+// printed instead of the UI.
+func (p *Program) loadProfiles(m *model.Model) ([]*cover.Profile, error) {
+	if err := p.checkProfileExists(); err != nil {
+		return nil, err
+	}
+
+	profiles, err := m.LoadProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coverage profile: %w", err)
+	}
+
+	return profiles, nil
+}
+
+// checkCoverage reports the total coverage of the requested files, and fails
+// if it is below the configured minimum, or if any file or directory is
+// below its target.
+func (p *Program) checkCoverage(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	r := report.New(profiles)
+
+	// the rounded percentage of the report would let 79.996% pass 80%
+	coverage := report.ExactPercentage(r.Covered, r.Total)
+
+	summary := fmt.Sprintf("coverage: %.2f%% of statements (minimum %.2f%%)\n", coverage, p.failUnder)
+	if _, err := fmt.Fprint(p.output, summary); err != nil {
+		return err
+	}
+
+	violations := p.targets.Check(r.Files)
+
+	for _, v := range violations {
+		if _, err := fmt.Fprintf(p.output, "%s: %.2f%% is below its target %.2f%%\n", v.Path, v.Percentage, v.Target); err != nil {
+			return err
+		}
+	}
+
+	if coverage < p.failUnder {
+		return fmt.Errorf("coverage %.2f%% is below %.2f%%", coverage, p.failUnder)
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("%d paths are below their coverage targets", len(violations))
+	}
+
+	return nil
+}
+
+// checkMinFiles fails if the profile has fewer of the requested files than
+// expected, so that a wrong list of files or an outdated profile isn't
+// mistaken for good coverage.
+func (p *Program) checkMinFiles(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) < p.minFiles {
+		return fmt.Errorf("only %d files match, expected at least %d (-min-files)", len(profiles), p.minFiles)
+	}
+
 	return nil
 }
 
+// writeJSON prints the coverage of the requested files as a JSON document.
+func (p *Program) writeJSON(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	return report.New(profiles).WriteJSON(p.output)
+}
+
+// writeSummary prints the coverage of the requested files as plain text.
+func (p *Program) writeSummary(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	return report.New(profiles).WriteText(p.output)
+}
+
+// writeTable prints the coverage of the requested files as an aligned table.
+func (p *Program) writeTable(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	return report.New(profiles).WriteTable(p.output)
+}
+
+// writeMarkdown prints the coverage of the requested files as a Markdown
+// table, marking the files below the threshold or their targets.
+func (p *Program) writeMarkdown(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	var threshold func(string) float64
+
+	if p.threshold > 0 || !p.targets.Empty() {
+		threshold = func(path string) float64 {
+			if target, ok := p.targets.For(path); ok && path != "" {
+				return target
+			}
+
+			return p.threshold
+		}
+	}
+
+	return report.New(profiles).WriteMarkdown(p.output, threshold)
+}
+
+// writeDiffReport prints the coverage of the changed lines of the requested
+// files, as JSON or plain text.
+func (p *Program) writeDiffReport(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	r := report.NewDiff(profiles, p.diffLines)
+
+	if p.jsonOutput {
+		return r.WriteJSON(p.output)
+	}
+
+	return r.WriteDiffText(p.output)
+}
+
+// exportHTML writes every requested file as HTML into the export directory.
+func (p *Program) exportHTML(m *model.Model) error {
+	profiles, err := p.loadProfiles(m)
+	if err != nil {
+		return err
+	}
+
+	if err := export.WriteDir(p.exportHTMLDir, p.codeRoot, profiles); err != nil {
+		return fmt.Errorf("failed to export html: %w", err)
+	}
+
+	if p.quiet {
+		return nil
+	}
+
+	_, err = fmt.Fprintf(p.output, "exported %d files to %s\n", len(profiles), p.exportHTMLDir)
+
+	return err
+}
+
+// resolveSortMode returns the requested sort mode. The deprecated
+// -sort-by-coverage flag is an alias for coverage-asc, unless -sort is passed
+// explicitly.
+func (p *Program) resolveSortMode() (model.SortMode, error) {
+	mode := model.SortMode(p.sortMode)
+
+	if p.sortByCoverage && !p.isFlagPassed("sort") {
+		mode = model.SortByCoverageAsc
+	}
+
+	if !mode.IsValid() {
+		return "", fmt.Errorf("invalid sort mode %q: must be one of %s", p.sortMode, strings.Join(sortModeNames(), ", "))
+	}
+
+	return mode, nil
+}
+
+func sortModeNames() []string {
+	names := make([]string, 0, len(model.SortModes))
+
+	for _, mode := range model.SortModes {
+		names = append(names, string(mode))
+	}
+
+	return names
+}
+
+func formatNames() []string {
+	names := make([]string, 0, len(parser.Formats))
+
+	for _, format := range parser.Formats {
+		names = append(names, string(format))
+	}
+
+	return names
+}
+
+// openIndex returns the index of the file opened right away with -open-first,
+// or -1 to only show the list.
+func (p *Program) openIndex() int {
+	if p.openFirst {
+		return 0
+	}
+
+	return -1
+}
+
+// isColorEnabled reports whether the output should be colored. Colors are
+// disabled explicitly, using NO_COLOR environment variable
+// (https://no-color.org), or when the output is not a terminal.
+func (p *Program) isColorEnabled() bool {
+	if p.noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	f, ok := p.output.(*os.File)
+	if !ok {
+		return false
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// teaOptions returns the options of the UI. It is rendered to the output of
+// the program, in the alternate screen unless -inline is set, and reads the
+// keys from its input. Files other than terminals, such as pipes that were
+// already read, are replaced with the terminal of the user.
+func (p *Program) teaOptions() []tea.ProgramOption {
+	opts := []tea.ProgramOption{tea.WithOutput(p.output)}
+
+	if !p.inline {
+		opts = append(opts, tea.WithAltScreen())
+	}
+
+	if _, ok := p.input.(*os.File); ok && !isTerminal(p.input) {
+		opts = append(opts, tea.WithInputTTY())
+	} else {
+		opts = append(opts, tea.WithInput(p.input))
+	}
+
+	if p.mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+
+	return opts
+}
+
+// isInteractive reports whether the UI can be used. The output must be a
+// terminal, and so must be stdin, unless something is piped to it: the UI
+// then reads the keys from the controlling terminal.
+func (p *Program) isInteractive() bool {
+	return isTerminal(p.output) && (isTerminal(p.input) || p.isInputStreamAvailable())
+}
+
+func isTerminal(v interface{}) bool {
+	f, ok := v.(interface{ Fd() uintptr })
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (p *Program) watchInterval() time.Duration {
+	if !p.watch {
+		return 0
+	}
+
+	return profileWatchInterval
+}
+
+func (p *Program) isFlagPassed(name string) bool {
+	passed := false
+
+	p.flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+
+	return passed
+}
+
+// checkProfileExists explains how to create the coverage profile when it is
+// missing, instead of failing to open it later. The message depends on
+// whether the profile was requested explicitly.
+func (p *Program) checkProfileExists() error {
+	if p.profileContent != nil {
+		return nil
+	}
+
+	_, err := os.Stat(model.ResolvePath(p.codeRoot, p.profileFilename))
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	create := ""
+	if parser.Format(p.format) == parser.FormatGo {
+		create = fmt.Sprintf("go test -coverprofile %s ./...", p.profileFilename)
+	}
+
+	if p.config.Profile == "" && !p.isFlagPassed("profile") {
+		if create == "" {
+			return fmt.Errorf("coverage profile %q not found: use -profile to open another file", p.profileFilename)
+		}
+
+		return fmt.Errorf(
+			"coverage profile %q not found: run %q to create it, or use -profile to open another file",
+			p.profileFilename, create,
+		)
+	}
+
+	if create == "" {
+		return fmt.Errorf("coverage profile %q not found: check the path passed to -profile", p.profileFilename)
+	}
+
+	return fmt.Errorf(
+		"coverage profile %q not found: check the path passed to -profile, or run %q to create it",
+		p.profileFilename, create,
+	)
+}
+
+// splitGlobs parses the comma-separated globs of the flag, and makes sure
+// they are valid.
+func splitGlobs(flag, list string) ([]string, error) {
+	globs := pkgpattern.Split(list)
+
+	for _, glob := range globs {
+		if !doublestar.ValidatePattern(glob) {
+			return nil, fmt.Errorf("invalid %s glob %q", flag, glob)
+		}
+	}
+
+	return globs, nil
+}
+
+// stringList is a flag that can be repeated, collecting all the values in
+// order.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// checkCompareProfileExists fails early when the profile passed to
+// -profile-compare is missing.
+func (p *Program) checkCompareProfileExists() error {
+	if p.compareFilename == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(model.ResolvePath(p.codeRoot, p.compareFilename)); errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("compared coverage profile %q not found: check the path passed to -profile-compare", p.compareFilename)
+	}
+
+	return nil
+}
+
+// parseInput reads the profile, the diff or the requested files from stdin,
+// the requested files passed as arguments and from the file passed to -files.
+// Only one of these can list the files.
 func (p *Program) parseInput() error {
-	if p.isInputStreamAvailable() {
+	if err := p.readInput(); err != nil {
+		return err
+	}
+
+	if args := p.flagSet.Args(); len(args) > 0 {
+		if p.requestedFiles != nil || p.filesList != "" {
+			return fmt.Errorf("files passed as arguments can't be used with -files, a list of files or a diff in stdin")
+		}
+
+		p.requestedFiles = make([]string, 0, len(args))
+		for _, arg := range args {
+			p.requestedFiles = append(p.requestedFiles, filepath.ToSlash(filepath.Clean(arg)))
+		}
+
+		p.openFile = p.requestedFiles[0]
+	}
+
+	if p.filesList != "" {
+		if p.requestedFiles != nil {
+			return fmt.Errorf("-files can't be used with a list of files or a diff in stdin")
+		}
+
+		f, err := os.Open(p.filesList) // nolint: gosec
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p.filesList, err)
+		}
+
+		defer func() { _ = f.Close() }()
+
+		if p.requestedFiles, err = readLines(f); err != nil {
+			return fmt.Errorf("failed to read %s: %w", p.filesList, err)
+		}
+	}
+
+	if p.respectGitignore && p.requestedFiles != nil {
+		if err := p.skipIgnoredFiles(); err != nil {
+			return fmt.Errorf("failed to apply .gitignore: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// readInput reads the profile, the diff or the requested files from stdin, if
+// anything was piped.
+func (p *Program) readInput() error {
+	if !p.isInputStreamAvailable() {
+		if p.profileFilename == stdinProfileFilename {
+			return fmt.Errorf("coverage profile is expected in stdin, but nothing was piped")
+		}
+
+		return nil
+	}
+
+	// explicitly requested profile in stdin takes precedence over any other
+	// kind of input, so it is not even inspected
+	if p.profileFilename == stdinProfileFilename {
 		bs, err := io.ReadAll(p.input)
 		if err != nil {
 			return fmt.Errorf("failed to read stdin: %w", err)
 		}
 
-		if inputStr := strings.TrimSpace(string(bs)); strings.HasPrefix(inputStr, "diff ") {
-			if diff, err := diffparser.Parse(inputStr); err == nil {
-				p.diffLines = diff.Changed()
+		p.profileContent = bs
+
+		return nil
+	}
+
+	// the kind of input is known from its first line, and only profiles and
+	// diffs are read whole: lists of files are read line by line
+	r := bufio.NewReader(p.input)
+
+	firstLine, read, err := readFirstLine(r)
+	if err != nil {
+		return fmt.Errorf("failed to read stdin: %w", err)
+	}
+
+	input := io.MultiReader(bytes.NewReader(read), r)
+
+	switch {
+	case profileModePattern.MatchString(firstLine):
+		if p.profileContent, err = io.ReadAll(input); err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+	case strings.HasPrefix(firstLine, "diff "):
+		bs, err := io.ReadAll(input)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+
+		p.parseDiff(strings.TrimSpace(string(bs)))
+	default:
+		if p.requestedFiles, err = readLines(input); err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// parseDiff requests the files changed in the diff, and the changed lines and
+// the hunks of its Go files. Invalid diffs are ignored.
+func (p *Program) parseDiff(input string) {
+	if diff, err := diffparser.Parse(input); err == nil {
+		p.diffLines = diff.Changed()
+		p.diffHunks = diffHunks(diff)
+
+		for file := range p.diffLines {
+			if !strings.HasSuffix(file, ".go") {
+				delete(p.diffLines, file)
+			}
+		}
+
+		for _, file := range diff.Files {
+			p.requestedFiles = append(p.requestedFiles, file.NewName)
+		}
+	}
+}
+
+// diffHunks returns the hunks of the Go files of the diff, except the deleted
+// ones.
+func diffHunks(diff *diffparser.Diff) map[string][]codeview.DiffHunk {
+	hunks := map[string][]codeview.DiffHunk{}
 
-				for file := range p.diffLines {
-					if !strings.HasSuffix(file, ".go") {
-						delete(p.diffLines, file)
-					}
-				}
+	kinds := map[diffparser.DiffLineMode]codeview.DiffLineKind{
+		diffparser.UNCHANGED: codeview.DiffUnchanged,
+		diffparser.ADDED:     codeview.DiffAdded,
+		diffparser.REMOVED:   codeview.DiffRemoved,
+	}
 
-				for _, file := range diff.Files {
-					p.requestedFiles = append(p.requestedFiles, file.NewName)
-				}
+	for _, f := range diff.Files {
+		if f.Mode == diffparser.DELETED || !strings.HasSuffix(f.NewName, ".go") {
+			continue
+		}
+
+		for _, h := range f.Hunks {
+			header := fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OrigRange.Start, h.OrigRange.Length, h.NewRange.Start, h.NewRange.Length)
+			if h.HunkHeader != "" {
+				header += " " + h.HunkHeader
 			}
-		} else {
-			p.requestedFiles = p.splitLines(inputStr)
+
+			hunk := codeview.DiffHunk{Header: header, Lines: make([]codeview.DiffLine, 0, len(h.WholeRange.Lines))}
+
+			for _, l := range h.WholeRange.Lines {
+				hunk.Lines = append(hunk.Lines, codeview.DiffLine{Kind: kinds[l.Mode], Number: l.Number, Text: l.Content})
+			}
+
+			hunks[f.NewName] = append(hunks[f.NewName], hunk)
 		}
 	}
 
+	return hunks
+}
+
+// readFirstLine returns the first line of the input that isn't blank, with
+// the leading and trailing spaces trimmed, and all the bytes read from the
+// input up to the end of that line.
+func readFirstLine(r *bufio.Reader) (line string, read []byte, err error) {
+	for {
+		bs, err := r.ReadBytes('\n')
+		read = append(read, bs...)
+
+		if line = strings.TrimSpace(string(bs)); line != "" || err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+
+			return line, read, err
+		}
+	}
+}
+
+// readLines returns the lines of the input that aren't blank, with the
+// leading and trailing spaces trimmed. The list is empty, but not nil, if
+// there are none, so that no files are requested.
+func readLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines, scanner.Err()
+}
+
+// skipIgnoredFiles removes the files ignored by git from the requested files
+// and from the diff.
+func (p *Program) skipIgnoredFiles() error {
+	matcher, err := gitignore.New(p.codeRoot)
+	if err != nil {
+		return err
+	}
+
+	requestedFiles := make([]string, 0, len(p.requestedFiles))
+
+	for _, file := range p.requestedFiles {
+		ignored, err := matcher.Ignored(file)
+		if err != nil {
+			return err
+		}
+
+		if ignored {
+			delete(p.diffLines, file)
+			continue
+		}
+
+		requestedFiles = append(requestedFiles, file)
+	}
+
+	p.requestedFiles = requestedFiles
+
 	return nil
 }
 
@@ -174,7 +1363,3 @@ func (p *Program) isInputStreamAvailable() bool {
 
 	return fi.Mode()&os.ModeNamedPipe != 0
 }
-
-func (p *Program) splitLines(s string) []string {
-	return strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
-}
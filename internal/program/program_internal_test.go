@@ -2,9 +2,13 @@ package program
 
 import (
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/orlangure/gocovsh/internal/codeview"
 	"github.com/orlangure/gocovsh/internal/gocovshtest/input"
 	"github.com/stretchr/testify/require"
 )
@@ -29,6 +33,10 @@ index e6cd709..adc400e 100644
  	"os"
  
  	"github.com/orlangure/gocovsh/internal/program"
+`
+
+	profileStr = `mode: set
+github.com/orlangure/gocovsh/main.go:3.20,5.2 1 1
 `
 )
 
@@ -61,5 +69,191 @@ func TestParseInput(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, p.requestedFiles, 1)
 		require.EqualValues(t, []string{"main.go"}, p.requestedFiles)
+
+		hunks := p.diffHunks["main.go"]
+		require.Len(t, hunks, 1)
+		require.Equal(t, "@@ -2,6 +2,7 @@ package main", hunks[0].Header)
+		require.Len(t, hunks[0].Lines, 7)
+		require.Equal(t, codeview.DiffLine{Kind: codeview.DiffAdded, Number: 5, Text: "\t_ \"foo\""}, hunks[0].Lines[3])
+		require.Equal(t, codeview.DiffLine{Kind: codeview.DiffUnchanged, Number: 6, Text: "\t\"os\""}, hunks[0].Lines[4])
+	})
+	t.Run("profile", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := input.NewMockFile(profileStr, os.ModeNamedPipe)
+		p := New(
+			WithInput(f),
+			WithFlagSet(flagSet, nil),
+		)
+
+		err := p.parseInput()
+		require.NoError(t, err)
+		require.Empty(t, p.requestedFiles)
+		require.Equal(t, profileStr, string(p.profileContent))
+	})
+
+	t.Run("explicit profile", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := input.NewMockFile(diffStr, os.ModeNamedPipe)
+		p := New(
+			WithInput(f),
+			WithFlagSet(flagSet, []string{"-profile", "-"}),
+		)
+
+		require.NoError(t, flagSet.Parse(p.args))
+
+		err := p.parseInput()
+		require.NoError(t, err)
+		require.Empty(t, p.requestedFiles)
+		require.Empty(t, p.diffLines)
+		require.Equal(t, diffStr, string(p.profileContent))
+	})
+
+	t.Run("respect gitignore", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("bar.go\n"), 0o600))
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := input.NewMockFile(filesList, os.ModeNamedPipe)
+		p := New(
+			WithInput(f),
+			WithCodeRoot(root),
+			WithFlagSet(flagSet, []string{"-respect-gitignore"}),
+		)
+
+		require.NoError(t, flagSet.Parse(p.args))
+
+		err := p.parseInput()
+		require.NoError(t, err)
+		require.EqualValues(t, []string{"foo.go", "baz.go"}, p.requestedFiles)
+	})
+
+	t.Run("respect gitignore in diff", func(t *testing.T) {
+		root := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.go\n"), 0o600))
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := input.NewMockFile(diffStr, os.ModeNamedPipe)
+		p := New(
+			WithInput(f),
+			WithCodeRoot(root),
+			WithFlagSet(flagSet, []string{"-respect-gitignore"}),
+		)
+
+		require.NoError(t, flagSet.Parse(p.args))
+
+		err := p.parseInput()
+		require.NoError(t, err)
+		require.NotNil(t, p.requestedFiles)
+		require.Empty(t, p.requestedFiles)
+		require.Empty(t, p.diffLines)
 	})
+
+	t.Run("explicit profile without input", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		f := input.NewMockFile(profileStr, os.ModeDir)
+		p := New(
+			WithInput(f),
+			WithFlagSet(flagSet, []string{"-profile", "-"}),
+		)
+
+		require.NoError(t, flagSet.Parse(p.args))
+		require.Error(t, p.parseInput())
+		require.False(t, f.InputRead)
+	})
+}
+
+func TestParseLargeInput(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+
+	parse := func(t *testing.T, content string) *Program {
+		t.Helper()
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := New(
+			WithInput(input.NewMockFile(content, os.ModeNamedPipe)),
+			WithFlagSet(flagSet, nil),
+		)
+
+		require.NoError(t, p.parseInput())
+
+		return p
+	}
+
+	t.Run("files list", func(t *testing.T) {
+		var b strings.Builder
+
+		b.WriteString("\n\n")
+
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&b, "pkg%d/file%d.go\r\n", i%100, i)
+
+			if i%1000 == 0 {
+				b.WriteString("\n")
+			}
+		}
+
+		p := parse(t, b.String())
+		require.Len(t, p.requestedFiles, n)
+		require.Equal(t, "pkg0/file0.go", p.requestedFiles[0])
+		require.Equal(t, fmt.Sprintf("pkg99/file%d.go", n-1), p.requestedFiles[n-1])
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		var b strings.Builder
+
+		for i := 0; i < n/10; i++ {
+			fmt.Fprintf(&b, "diff --git a/f%[1]d.go b/f%[1]d.go\n--- a/f%[1]d.go\n+++ b/f%[1]d.go\n", i)
+			b.WriteString("@@ -1,2 +1,3 @@\n package main\n+var x = 1\n \n")
+		}
+
+		p := parse(t, b.String())
+		require.Len(t, p.requestedFiles, n/10)
+		require.Len(t, p.diffLines, n/10)
+		require.Equal(t, []int{2}, p.diffLines["f0.go"])
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		var b strings.Builder
+
+		b.WriteString("\nmode: count\n")
+
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&b, "example.com/mod/f%d.go:1.1,2.2 1 %d\n", i, i%3)
+		}
+
+		p := parse(t, b.String())
+		require.Nil(t, p.requestedFiles)
+		require.Equal(t, b.String(), string(p.profileContent))
+	})
+
+	t.Run("empty files list", func(t *testing.T) {
+		p := parse(t, "\n  \n")
+		require.NotNil(t, p.requestedFiles)
+		require.Empty(t, p.requestedFiles)
+	})
+}
+
+func TestCheckCompareProfileExists(t *testing.T) {
+	t.Parallel()
+
+	compare, err := filepath.Abs("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	check := func(compareFilename string) error {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := New(
+			WithCodeRoot("../gocovshtest/testdata/general"),
+			WithFlagSet(flagSet, []string{"-profile-compare", compareFilename}),
+		)
+		require.NoError(t, p.flagSet.Parse(p.args))
+
+		return p.checkCompareProfileExists()
+	}
+
+	require.NoError(t, check("profile.cover"))
+	require.NoError(t, check(compare))
+	require.EqualError(t, check("missing.cover"),
+		`compared coverage profile "missing.cover" not found: check the path passed to -profile-compare`)
 }
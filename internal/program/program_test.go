@@ -2,10 +2,16 @@ package program_test
 
 import (
 	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"log"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +20,28 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestMain(m *testing.M) {
+	// configuration and environment of the user running the tests must not
+	// affect them
+	dir, err := os.MkdirTemp("", "gocovsh-config")
+	if err != nil {
+		panic(err)
+	}
+
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("XDG_CACHE_HOME", dir)
+
+	for _, name := range []string{"GOCOVSH_PROFILE", "GOCOVSH_SORT", "GOCOVSH_THEME"} {
+		os.Unsetenv(name)
+	}
+
+	code := m.Run()
+
+	_ = os.RemoveAll(dir)
+
+	os.Exit(code)
+}
+
 func TestVersion(t *testing.T) {
 	version := "1.2.3"
 	commit := "abcdef"
@@ -33,6 +61,27 @@ func TestVersion(t *testing.T) {
 	require.Equal(t, expectedVersion, buf.String())
 }
 
+func TestVersionJSON(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithBuildInfo("1.2.3", "abcdef", "2022-05-01T00:00:00Z"),
+		program.WithOutput(buf),
+		program.WithFlagSet(flagSet, []string{"-version", "-json"}),
+	)
+
+	require.NoError(t, p.Run())
+
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	require.Equal(t, "1.2.3", got["version"])
+	require.Equal(t, "abcdef", got["commit"])
+	require.Equal(t, "2022-05-01T00:00:00Z", got["date"])
+	require.Contains(t, got, "modVersion")
+	require.Contains(t, got, "modSum")
+}
+
 func TestLogger(t *testing.T) {
 	buf := bytes.NewBuffer(nil)
 
@@ -89,3 +138,1337 @@ func TestInput(t *testing.T) {
 		require.False(t, f.InputRead)
 	})
 }
+
+func TestThreshold(t *testing.T) {
+	for _, threshold := range []string{"-1", "100.5"} {
+		t.Run(threshold, func(t *testing.T) {
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithFlagSet(flagSet, []string{"-threshold", threshold}),
+			)
+
+			err := p.Run()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "must be between 0 and 100")
+		})
+	}
+}
+
+func TestContext(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithFlagSet(flagSet, []string{"-uncovered-only", "-context", "-1"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid context -1")
+}
+
+func TestSort(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	tests := []struct {
+		name  string
+		args  []string
+		files []string
+	}{
+		{name: "default", files: []string{"covered.go", longName}},
+		{name: "coverage-asc", args: []string{"-sort", "coverage-asc"}, files: []string{longName, "covered.go"}},
+		{name: "coverage-desc", args: []string{"-sort", "coverage-desc"}, files: []string{"covered.go", longName}},
+		{name: "lines", args: []string{"-sort", "lines"}, files: []string{longName, "covered.go"}},
+		{name: "uncovered", args: []string{"-sort", "uncovered"}, files: []string{longName, "covered.go"}},
+		{name: "deprecated alias", args: []string{"-sort-by-coverage"}, files: []string{longName, "covered.go"}},
+		{
+			name:  "explicit sort wins over alias",
+			args:  []string{"-sort-by-coverage", "-sort", "path"},
+			files: []string{"covered.go", longName},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithOutput(buf),
+				program.WithCodeRoot("../gocovshtest/testdata/general"),
+				program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-json"}, test.args...)),
+			)
+
+			require.NoError(t, p.Run())
+
+			var r struct {
+				Files []struct {
+					Path string `json:"path"`
+				} `json:"files"`
+			}
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+			require.Len(t, r.Files, len(test.files))
+
+			for i, file := range test.files {
+				require.Equal(t, file, r.Files[i].Path)
+			}
+		})
+	}
+
+	t.Run("invalid mode", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithFlagSet(flagSet, []string{"-sort", "size"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be one of name, path, coverage-asc, coverage-desc, lines")
+	})
+}
+
+func TestFilter(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	t.Run("narrows requested files", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile("covered.go\n"+longName, os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-filter", "^cov"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 1,
+			"files": [{"covered": 1, "path": "covered.go", "percentage": 100, "total": 1}],
+			"mode": "set",
+			"percentage": 100,
+			"total": 1
+		}`, buf.String())
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithFlagSet(flagSet, []string{"-filter", "internal/("}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `invalid filter "internal/("`)
+	})
+}
+
+func TestFilesList(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "files.txt")
+	require.NoError(t, os.WriteFile(list, []byte("covered.go\n"), 0o600))
+
+	t.Run("requested files", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-files", list}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 1,
+			"files": [{"covered": 1, "path": "covered.go", "percentage": 100, "total": 1}],
+			"mode": "set",
+			"percentage": 100,
+			"total": 1
+		}`, buf.String())
+	})
+
+	t.Run("conflicts with stdin", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithInput(input.NewMockFile("covered.go", os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-files", list}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "-files can't be used with a list of files or a diff in stdin")
+	})
+
+	t.Run("missing list", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-files", list + ".missing"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestLimit(t *testing.T) {
+	run := func(args ...string) ([]string, error) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/tree"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-json"}, args...)),
+		)
+
+		if err := p.Run(); err != nil {
+			return nil, err
+		}
+
+		var out struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+		paths := make([]string, 0, len(out.Files))
+		for _, f := range out.Files {
+			paths = append(paths, f.Path)
+		}
+
+		return paths, nil
+	}
+
+	paths, err := run("-limit", "2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"main.go", "pkg/a/a.go"}, paths)
+
+	paths, err = run("-limit", "2", "-sort", "coverage-asc")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pkg/a/util.go", "pkg/a/a.go"}, paths)
+
+	paths, err = run("-limit", "10", "-filter", "pkg/a")
+	require.NoError(t, err)
+	require.Equal(t, []string{"pkg/a/a.go", "pkg/a/util.go"}, paths)
+
+	_, err = run("-limit", "-1")
+	require.EqualError(t, err, "invalid limit -1: must not be negative")
+
+	_, err = run("-limit", "2", "-min-files", "3")
+	require.EqualError(t, err, "-limit can't be used with -min-files or -fail-under")
+}
+
+func TestAbsoluteProfilePath(t *testing.T) {
+	profile, err := filepath.Abs("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flagSet, []string{"-profile", profile, "-json"}),
+	)
+
+	require.NoError(t, p.Run())
+	require.Contains(t, buf.String(), `"percentage": 80`)
+}
+
+func TestSelectWithOpenedFile(t *testing.T) {
+	for _, args := range [][]string{{"-open-first"}, {"main.go"}} {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(bytes.NewBuffer(nil)),
+			program.WithCodeRoot("../gocovshtest/testdata/tree"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-select", "util"}, args...)),
+		)
+
+		require.EqualError(t, p.Run(), "-select can't be used with -open-first or files passed as arguments", args)
+	}
+}
+
+func TestFileArguments(t *testing.T) {
+	t.Run("requested files", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "./covered.go"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 1,
+			"files": [{"covered": 1, "path": "covered.go", "percentage": 100, "total": 1}],
+			"mode": "set",
+			"percentage": 100,
+			"total": 1
+		}`, buf.String())
+	})
+
+	t.Run("after double dash", func(t *testing.T) {
+		profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+		require.NoError(t, err)
+
+		content := string(profile) + "github.com/orlangure/gocovsh/internal/model/testdata/general/-weird-name.go:3.20,5.2 1 0\n"
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile(content, os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "-", "-json", "--", "-weird-name.go"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 0,
+			"files": [{"covered": 0, "path": "-weird-name.go", "percentage": 0, "total": 1}],
+			"mode": "set",
+			"percentage": 0,
+			"total": 1
+		}`, buf.String())
+	})
+
+	t.Run("conflicts with -files", func(t *testing.T) {
+		list := filepath.Join(t.TempDir(), "files.txt")
+		require.NoError(t, os.WriteFile(list, []byte("covered.go\n"), 0o600))
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-files", list, "covered.go"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "files passed as arguments can't be used with -files")
+	})
+}
+
+func TestPackages(t *testing.T) {
+	tests := []struct {
+		name     string
+		packages string
+		args     []string
+		files    []string
+	}{
+		{name: "module root", packages: ".", files: []string{"main.go"}},
+		{name: "relative", packages: "./pkg/...", files: []string{"pkg/a/a.go", "pkg/a/util.go", "pkg/b/b.go"}},
+		{name: "import path", packages: "example.com/tree/pkg/b/...", files: []string{"pkg/b/b.go"}},
+		{name: "several patterns", packages: "./pkg/b, .", files: []string{"main.go", "pkg/b/b.go"}},
+		{name: "with filter", packages: "./pkg/...", args: []string{"-filter", "util"}, files: []string{"pkg/a/util.go"}},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithOutput(buf),
+				program.WithCodeRoot("../gocovshtest/testdata/tree"),
+				program.WithFlagSet(flagSet, append(
+					[]string{"-profile", "profile.cover", "-json", "-packages", test.packages},
+					test.args...,
+				)),
+			)
+
+			require.NoError(t, p.Run())
+
+			var r struct {
+				Files []struct {
+					Path string `json:"path"`
+				} `json:"files"`
+			}
+
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+
+			paths := make([]string, 0, len(r.Files))
+			for _, f := range r.Files {
+				paths = append(paths, f.Path)
+			}
+
+			require.Equal(t, test.files, paths)
+		})
+	}
+
+	t.Run("no matches", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(io.Discard),
+			program.WithCodeRoot("../gocovshtest/testdata/tree"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-packages", "./pkg/...,./cmd/..."}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `package pattern "./cmd/..." matches no files`)
+	})
+}
+
+func TestStripPrefix(t *testing.T) {
+	dir := t.TempDir()
+	profile := "mode: set\n" +
+		"_/home/runner/work/example.com/mod/a.go:3.15,5.2 1 1\n" +
+		"_/home/runner/work/example.com/mod/pkg/b.go:3.15,5.2 1 0\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile), 0o600))
+
+	run := func(t *testing.T, args ...string) []string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot(dir),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-json", "-sort", "path"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		var r struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+
+		paths := make([]string, 0, len(r.Files))
+		for _, f := range r.Files {
+			paths = append(paths, f.Path)
+		}
+
+		return paths
+	}
+
+	t.Run("without prefix", func(t *testing.T) {
+		require.Equal(t, []string{
+			"_/home/runner/work/example.com/mod/a.go",
+			"_/home/runner/work/example.com/mod/pkg/b.go",
+		}, run(t))
+	})
+
+	t.Run("single prefix", func(t *testing.T) {
+		require.Equal(t, []string{"a.go", "pkg/b.go"}, run(t, "-strip-prefix", "_/home/runner/work/"))
+	})
+
+	t.Run("prefixes in order", func(t *testing.T) {
+		require.Equal(t, []string{"a.go", "pkg/b.go"}, run(t, "-strip-prefix", "_/home/", "-strip-prefix", "runner/work/"))
+	})
+
+	t.Run("prefixes out of order", func(t *testing.T) {
+		require.Equal(t, []string{
+			"runner/work/example.com/mod/a.go",
+			"runner/work/example.com/mod/pkg/b.go",
+		}, run(t, "-strip-prefix", "runner/work/", "-strip-prefix", "_/home/"))
+	})
+}
+
+func TestSummaryWithoutTerminal(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flagSet, []string{"-profile", "profile.cover"}),
+	)
+
+	// the output is not a terminal, so the UI is not started
+	require.NoError(t, p.Run())
+	require.Equal(t, `covered.go: 100.00% (1/1 statements)
+partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go: 75.00% (3/4 statements)
+total: 80.00% (4/5 statements)
+`, buf.String())
+}
+
+func TestSummaryTable(t *testing.T) {
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-summary"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		return buf.String()
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		require.Equal(t, `partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go    75.00%  3/4
+covered.go                                                           100.00%  1/1
+total                                                                 80.00%  4/5
+`, run(t, "-sort", "coverage-asc"))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		require.Equal(t, `covered.go   100.00%  1/1
+total        100.00%  1/1
+`, run(t, "-include", "covered.go"))
+	})
+}
+
+func TestMarkdownTable(t *testing.T) {
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-markdown"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		return buf.String()
+	}
+
+	t.Run("sorted", func(t *testing.T) {
+		require.Equal(t, "| File | Coverage | Statements |\n"+
+			"| :--- | ---: | ---: |\n"+
+			"| `partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go` | 75.00% | 3/4 |\n"+
+			"| `covered.go` | 100.00% | 1/1 |\n"+
+			"| **Total** | 80.00% | 4/5 |\n", run(t, "-sort", "coverage-asc"))
+	})
+
+	t.Run("threshold", func(t *testing.T) {
+		require.Equal(t, "| File | Coverage | Statements | Status |\n"+
+			"| :--- | ---: | ---: | :---: |\n"+
+			"| `covered.go` | 100.00% | 1/1 | ✅ |\n"+
+			"| `partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go` | 75.00% | 3/4 | ❌ |\n"+
+			"| **Total** | 80.00% | 4/5 | ✅ |\n", run(t, "-threshold", "80"))
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		require.Equal(t, "| File | Coverage | Statements |\n"+
+			"| :--- | ---: | ---: |\n"+
+			"| `covered.go` | 100.00% | 1/1 |\n"+
+			"| **Total** | 100.00% | 1/1 |\n", run(t, "-include", "covered.go"))
+	})
+}
+
+func TestFailUnder(t *testing.T) {
+	tests := []struct {
+		name      string
+		codeRoot  string
+		profile   string
+		failUnder string
+		output    string
+		fails     bool
+	}{
+		{
+			name:      "above minimum",
+			codeRoot:  "../gocovshtest/testdata/general",
+			profile:   "profile.cover",
+			failUnder: "50",
+			output:    "coverage: 80.00% of statements (minimum 50.00%)\n",
+		},
+		{
+			name:      "below minimum",
+			codeRoot:  "../gocovshtest/testdata/general",
+			profile:   "profile.cover",
+			failUnder: "90",
+			output:    "coverage: 80.00% of statements (minimum 90.00%)\n",
+			fails:     true,
+		},
+		{
+			name:      "empty profile",
+			codeRoot:  "../gocovshtest/testdata/errors",
+			profile:   "empty.profile",
+			failUnder: "0.1",
+			output:    "coverage: 0.00% of statements (minimum 0.10%)\n",
+			fails:     true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithOutput(buf),
+				program.WithCodeRoot(test.codeRoot),
+				program.WithFlagSet(flagSet, []string{"-profile", test.profile, "-fail-under", test.failUnder}),
+			)
+
+			err := p.Run()
+			if test.fails {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "is below")
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Equal(t, test.output, buf.String())
+		})
+	}
+}
+
+func TestMinFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		minFiles string
+		err      string
+	}{
+		{name: "enough files", minFiles: "2"},
+		{name: "too few files", minFiles: "3", err: "only 2 files match, expected at least 3 (-min-files)"},
+		{name: "negative", minFiles: "-1", err: "invalid min-files value -1: must not be negative"},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			buf := bytes.NewBuffer(nil)
+			flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+			p := program.New(
+				program.WithOutput(buf),
+				program.WithCodeRoot("../gocovshtest/testdata/general"),
+				program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-min-files", test.minFiles}),
+			)
+
+			err := p.Run()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				require.Empty(t, buf.String())
+			} else {
+				require.NoError(t, err)
+				require.NotEmpty(t, buf.String())
+			}
+		})
+	}
+}
+
+func TestHideGenerated(t *testing.T) {
+	run := func(t *testing.T, args ...string) []string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/generated"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-json", "-sort", "path"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		var r struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &r))
+
+		paths := make([]string, 0, len(r.Files))
+		for _, f := range r.Files {
+			paths = append(paths, f.Path)
+		}
+
+		return paths
+	}
+
+	require.Equal(t, []string{"api/api.pb.go", "mocks/service.go", "service.go"}, run(t))
+	require.Equal(t, []string{"service.go"}, run(t, "-hide-generated"))
+}
+
+func TestFailUnderTargets(t *testing.T) {
+	dir := t.TempDir()
+	profile := "mode: set\n" +
+		"example.com/mod/main.go:3.13,5.2 1 1\n" +
+		"example.com/mod/pkg/a/a.go:3.15,5.2 1 1\n" +
+		"example.com/mod/pkg/a/a.go:7.17,9.2 1 0\n" +
+		"example.com/mod/pkg/a/util.go:3.18,5.2 2 0\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile), 0o600))
+
+	run := func(t *testing.T, config string) (string, error) {
+		t.Helper()
+
+		writeConfig(t, filepath.Join(dir, ".gocovsh.yaml"), config)
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot(dir),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-fail-under", "10"}),
+		)
+
+		err := p.Run()
+
+		return buf.String(), err
+	}
+
+	t.Run("targets are met", func(t *testing.T) {
+		output, err := run(t, "targets:\n  \"*.go\": 100\n  \"pkg/a\": 25\n")
+		require.NoError(t, err)
+		require.Equal(t, "coverage: 40.00% of statements (minimum 10.00%)\n", output)
+	})
+
+	t.Run("most specific target is violated", func(t *testing.T) {
+		output, err := run(t, "targets:\n  \"pkg/**\": 25\n  \"pkg/a/*.go\": 40\n")
+		require.EqualError(t, err, "1 paths are below their coverage targets")
+		require.Equal(t, "coverage: 40.00% of statements (minimum 10.00%)\n"+
+			"pkg/a/util.go: 0.00% is below its target 40.00%\n", output)
+	})
+
+	t.Run("directories are checked", func(t *testing.T) {
+		output, err := run(t, "targets:\n  \"pkg/a\": 30\n")
+		require.EqualError(t, err, "1 paths are below their coverage targets")
+		require.Contains(t, output, "pkg/a: 25.00% is below its target 30.00%\n")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := run(t, "targets:\n  \"pkg/**\": 101\n")
+		require.EqualError(t, err, `invalid coverage targets: invalid target 101 of "pkg/**": must be between 0 and 100`)
+	})
+}
+
+func TestFailUnderBoundary(t *testing.T) {
+	// 19999 of 25000 statements are 79.996%, which rounds to 80.00%
+	dir := t.TempDir()
+	profile := "mode: set\n" +
+		"example.com/mod/main.go:3.13,5.2 19999 1\n" +
+		"example.com/mod/main.go:7.13,9.2 5001 0\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile), 0o600))
+
+	run := func(t *testing.T, failUnder, config string) (string, error) {
+		t.Helper()
+
+		writeConfig(t, filepath.Join(dir, ".gocovsh.yaml"), config)
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot(dir),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-fail-under", failUnder}),
+		)
+
+		err := p.Run()
+
+		return buf.String(), err
+	}
+
+	t.Run("total just under the minimum", func(t *testing.T) {
+		_, err := run(t, "80", "")
+		require.EqualError(t, err, "coverage 80.00% is below 80.00%")
+
+		_, err = run(t, "79.99", "")
+		require.NoError(t, err)
+	})
+
+	t.Run("target just under the minimum", func(t *testing.T) {
+		output, err := run(t, "10", "targets:\n  \"main.go\": 80\n")
+		require.EqualError(t, err, "1 paths are below their coverage targets")
+		require.Contains(t, output, "main.go: 80.00% is below its target 80.00%\n")
+	})
+}
+
+func TestIgnoreComments(t *testing.T) {
+	dir := t.TempDir()
+	source := "package mod\n" +
+		"\n" +
+		"func A(err error) error {\n" +
+		"	if err != nil { //gocovsh:ignore\n" +
+		"		return err\n" +
+		"	}\n" +
+		"\n" +
+		"	return nil\n" +
+		"}\n" +
+		"\n" +
+		"//gocovsh:ignore-start\n" +
+		"func B() {\n" +
+		"	println()\n" +
+		"}\n" +
+		"//gocovsh:ignore-end\n" +
+		"\n" +
+		"//gocovsh:ignore\n" +
+		"func C() {\n" +
+		"	println()\n" +
+		"}\n" +
+		"\n" +
+		// strings are not comments
+		"func D() string { return \"//gocovsh:ignore\" }\n" +
+		// lines longer than the buffers of bufio.Scanner are fine
+		"var long = \"" + strings.Repeat("x", 70000) + "\"\n" +
+		"\n" +
+		"//gocovsh:ignore unreachable\n" +
+		"func E() {\n" +
+		"	println()\n" +
+		"}\n" +
+		"\n" +
+		// ranges without an end exclude nothing
+		"//gocovsh:ignore-start\n" +
+		"func F() {\n" +
+		"	println()\n" +
+		"}\n"
+	profile := "mode: set\n" +
+		"example.com/mod/mod.go:3.26,4.16 1 1\n" +
+		"example.com/mod/mod.go:4.16,6.3 1 0\n" +
+		"example.com/mod/mod.go:8.2,8.12 1 1\n" +
+		"example.com/mod/mod.go:12.10,14.2 1 0\n" +
+		"example.com/mod/mod.go:18.10,20.2 1 0\n" +
+		"example.com/mod/mod.go:22.17,22.45 1 0\n" +
+		"example.com/mod/mod.go:26.10,28.2 1 0\n" +
+		"example.com/mod/mod.go:31.10,33.2 1 1\n"
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mod.go"), []byte(source), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile), 0o600))
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot(dir),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-fail-under", "70"}, args...)),
+		)
+
+		err := p.Run()
+
+		return buf.String(), err
+	}
+
+	t.Run("ignored lines are excluded", func(t *testing.T) {
+		output, err := run(t, "-ignore-comments")
+		require.NoError(t, err)
+		require.Equal(t, "coverage: 75.00% of statements (minimum 70.00%)\n", output)
+	})
+
+	t.Run("ignore comments are disabled by default", func(t *testing.T) {
+		output, err := run(t)
+		require.Error(t, err)
+		require.Equal(t, "coverage: 37.50% of statements (minimum 70.00%)\n", output)
+	})
+}
+
+func TestQuiet(t *testing.T) {
+	logs := bytes.NewBuffer(nil)
+
+	log.SetOutput(logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		logs.Reset()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-include", "covered.go"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		return buf.String()
+	}
+
+	t.Run("logs without quiet", func(t *testing.T) {
+		require.NotEmpty(t, run(t, "-json"))
+		require.Contains(t, logs.String(), "excluding")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output := run(t, "-json", "-quiet")
+		require.True(t, json.Valid([]byte(output)))
+		require.Empty(t, logs.String())
+	})
+
+	t.Run("export", func(t *testing.T) {
+		dir := t.TempDir()
+
+		require.Empty(t, run(t, "-export-html", dir, "-quiet"))
+		require.Empty(t, logs.String())
+		require.FileExists(t, filepath.Join(dir, "covered.go.html"))
+	})
+}
+
+func TestJSON(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	t.Run("all files sorted by coverage", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json", "-sort-by-coverage"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 4,
+			"files": [
+				{"covered": 3, "path": "`+longName+`", "percentage": 75, "total": 4},
+				{"covered": 1, "path": "covered.go", "percentage": 100, "total": 1}
+			],
+			"mode": "set",
+			"percentage": 80,
+			"total": 5
+		}`, buf.String())
+	})
+
+	t.Run("requested files", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile("covered.go", os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-json"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 1,
+			"files": [{"covered": 1, "path": "covered.go", "percentage": 100, "total": 1}],
+			"mode": "set",
+			"percentage": 100,
+			"total": 1
+		}`, buf.String())
+	})
+
+	t.Run("missing profile", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "missing.cover", "-json"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Equal(t, `coverage profile "missing.cover" not found: check the path passed to -profile, `+
+			`or run "go test -coverprofile missing.cover ./..." to create it`, err.Error())
+		require.Empty(t, buf.String())
+	})
+
+	t.Run("missing default profile", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(bytes.NewBuffer(nil)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-json"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Equal(t, `coverage profile "coverage.out" not found: run "go test -coverprofile coverage.out ./..." `+
+			`to create it, or use -profile to open another file`, err.Error())
+	})
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("cobertura without go.mod", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithCodeRoot("../gocovshtest/testdata/cobertura"),
+			program.WithFlagSet(flagSet, []string{"-profile", "coverage.xml", "-format", "cobertura", "-json"}),
+		)
+
+		require.NoError(t, p.Run())
+		require.JSONEq(t, `{
+			"covered": 3,
+			"files": [{"covered": 3, "path": "src/main/java/example/Greeter.java", "percentage": 75, "total": 4}],
+			"mode": "count",
+			"percentage": 75,
+			"total": 4
+		}`, buf.String())
+	})
+
+	t.Run("invalid xml", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(bytes.NewBuffer(nil)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-format", "cobertura", "-json"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to parse cobertura xml")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithFlagSet(flagSet, []string{"-format", "xml"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "must be one of go, cobertura, lcov")
+	})
+}
+
+func TestExcludeTestFiles(t *testing.T) {
+	profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	content := string(profile) + "github.com/orlangure/gocovsh/internal/model/testdata/general/covered_test.go:5.30,7.2 1 1\n"
+
+	run := func(args ...string) []string {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile(content, os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "-", "-json"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		var out struct {
+			Files []struct {
+				Path string `json:"path"`
+			} `json:"files"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+
+		paths := make([]string, 0, len(out.Files))
+		for _, f := range out.Files {
+			paths = append(paths, f.Path)
+		}
+
+		return paths
+	}
+
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	require.Equal(t, []string{"covered.go", "covered_test.go", longName}, run())
+	require.Equal(t, []string{"covered.go", longName}, run("-exclude-test-files"))
+	require.Equal(t, []string{"covered.go"}, run("-exclude-test-files", "-include", "covered*"))
+}
+
+func TestMode(t *testing.T) {
+	profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	// the mode line is stripped by some tools
+	headerless := strings.SplitN(string(profile), "\n", 2)[1]
+
+	run := func(content string, args ...string) (string, error) {
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile(content, os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "-", "-json"}, args...)),
+		)
+
+		err := p.Run()
+
+		return buf.String(), err
+	}
+
+	t.Run("without mode line", func(t *testing.T) {
+		_, err := run(headerless)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "bad mode line")
+	})
+
+	t.Run("assumed mode", func(t *testing.T) {
+		out, err := run(headerless, "-mode", "count")
+		require.NoError(t, err)
+		require.Contains(t, out, `"mode": "count"`)
+	})
+
+	t.Run("mode line wins", func(t *testing.T) {
+		out, err := run(string(profile), "-mode", "count")
+		require.NoError(t, err)
+		require.Contains(t, out, `"mode": "set"`)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := run(string(profile), "-mode", "sometimes")
+		require.EqualError(t, err, `invalid mode "sometimes": must be one of set, count, atomic`)
+	})
+}
+
+func TestProfileFromInput(t *testing.T) {
+	profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithInput(input.NewMockFile(string(profile), os.ModeNamedPipe)),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flagSet, []string{"-profile", "-", "-fail-under", "50"}),
+	)
+
+	require.NoError(t, p.Run())
+	require.Equal(t, "coverage: 80.00% of statements (minimum 50.00%)\n", buf.String())
+}
+
+func TestGzippedProfile(t *testing.T) {
+	profile, err := os.ReadFile("../gocovshtest/testdata/general/profile.cover")
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	writeGzipped(t, filepath.Join(dir, "profile.cover.gz"), profile)
+	copyFile(t, "../gocovshtest/testdata/general/go.mod", filepath.Join(dir, "go.mod"))
+
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithCodeRoot(dir),
+		program.WithFlagSet(flagSet, []string{"-profile", "profile.cover.gz", "-fail-under", "50"}),
+	)
+
+	require.NoError(t, p.Run())
+	require.Equal(t, "coverage: 80.00% of statements (minimum 50.00%)\n", buf.String())
+}
+
+func writeGzipped(t *testing.T, filename string, content []byte) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(filename, buf.Bytes(), 0o600))
+}
+
+func copyFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	bs, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, bs, 0o600))
+}
+
+func TestWatchProfileFromInput(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithInput(input.NewMockFile("mode: set\n", os.ModeNamedPipe)),
+		program.WithFlagSet(flagSet, []string{"-profile", "-", "-watch"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't be watched")
+}
+
+func TestDiffViewWithoutDiff(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithInput(input.NewMockFile("covered.go", os.ModeNamedPipe)),
+		program.WithFlagSet(flagSet, []string{"-diff-view"}),
+	)
+
+	err := p.Run()
+	require.EqualError(t, err, "diff view requires a diff in stdin")
+}
+
+func TestDiffOnlyWithoutDiff(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithInput(input.NewMockFile("covered.go", os.ModeNamedPipe)),
+		program.WithFlagSet(flagSet, []string{"-diff-only"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires a diff in stdin")
+}
+
+func TestDiffReport(t *testing.T) {
+	const longName = "partial_with_a_very_long_name_to_trigger_ellipsis_in_the_output.go"
+
+	diff := `diff --git a/` + longName + ` b/` + longName + `
+index 1111111..2222222 100644
+--- a/` + longName + `
++++ b/` + longName + `
+@@ -7,6 +7,6 @@ func Covered() string {
+ func NotCovered() string {
+-	return "uncovered"
++	return "not covered"
+ }
+ 
+ func SecondCovered() string {
+-	switch false {
++	switch true {
+diff --git a/missing.go b/missing.go
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/missing.go
+@@ -0,0 +1,3 @@
++package general
++
++func Missing() {}
+diff --git a/missing_test.go b/missing_test.go
+new file mode 100644
+index 0000000..4444444
+--- /dev/null
++++ b/missing_test.go
+@@ -0,0 +1 @@
++package general
+`
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		buf := bytes.NewBuffer(nil)
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithOutput(buf),
+			program.WithInput(input.NewMockFile(diff, os.ModeNamedPipe)),
+			program.WithCodeRoot("../gocovshtest/testdata/general"),
+			program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-diff-report"}, args...)),
+		)
+
+		require.NoError(t, p.Run())
+
+		return buf.String()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		require.Equal(t, longName+": 50.00% (1/2 changed statements)\n"+
+			"missing.go: 0.00% (no coverage data)\n"+
+			"total: 50.00% (1/2 changed statements)\n", run(t))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		require.JSONEq(t, `{
+			"covered": 1,
+			"files": [
+				{"covered": 1, "path": "`+longName+`", "percentage": 50, "total": 2},
+				{"covered": 0, "path": "missing.go", "percentage": 0, "total": 0, "no_profile": true}
+			],
+			"mode": "set",
+			"percentage": 50,
+			"total": 2
+		}`, run(t, "-json"))
+	})
+
+	t.Run("without diff", func(t *testing.T) {
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := program.New(
+			program.WithInput(input.NewMockFile("covered.go", os.ModeNamedPipe)),
+			program.WithFlagSet(flagSet, []string{"-diff-report"}),
+		)
+
+		err := p.Run()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "requires a diff in stdin")
+	})
+}
+
+func TestSyntaxTheme(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithFlagSet(flagSet, []string{"-syntax", "-syntax-theme", "missing"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid syntax theme "missing"`)
+}
+
+func TestTheme(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithFlagSet(flagSet, []string{"-theme", "missing"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid theme "missing": must be one of colorblind, dark, default, frappe`)
+}
+
+func TestInvalidColor(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(bytes.NewBuffer(nil)),
+		program.WithFlagSet(flagSet, []string{"-covered-color", "#00ff00", "-uncovered-color", "reddish"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid uncovered color: "reddish": must be a hex color such as #00ff00`)
+}
+
+func TestInvalidGlob(t *testing.T) {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(bytes.NewBuffer(nil)),
+		program.WithFlagSet(flagSet, []string{"-include", "internal/**", "-exclude", "a/[b"}),
+	)
+
+	err := p.Run()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `invalid exclude glob "a/[b"`)
+}
+
+func TestScriptedUI(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithInput(input.NewMockFile("q", os.ModeCharDevice)),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-force-tui", "-mouse=false"}),
+	)
+
+	// the keys are read from the input, and the UI is rendered to the output
+	require.NoError(t, p.Run())
+	require.Contains(t, buf.String(), "\x1b[?1049h")
+}
+
+func TestPrintSelectionWithoutOpenedFile(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithInput(input.NewMockFile("q", os.ModeCharDevice)),
+		program.WithCodeRoot("../gocovshtest/testdata/general"),
+		program.WithFlagSet(flagSet, []string{"-profile", "profile.cover", "-force-tui", "-print-selection"}),
+	)
+
+	require.NoError(t, p.Run())
+
+	// nothing is printed after the UI exits
+	out := buf.Bytes()
+	exit := bytes.LastIndex(out, []byte("\x1b[?1049l"))
+	require.GreaterOrEqual(t, exit, 0)
+	require.NotContains(t, string(out[exit:]), ".go")
+}
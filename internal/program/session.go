@@ -0,0 +1,175 @@
+package program
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/orlangure/gocovsh/internal/styles"
+)
+
+// sessionVersion is the version of the schema of the session file. Files of
+// other versions are ignored, so that upgrades start a new session instead
+// of failing.
+const sessionVersion = 1
+
+// session is the state of the UI remembered between runs.
+type session struct {
+	Version int    `json:"version"`
+	Sort    string `json:"sort,omitempty"`
+	Theme   string `json:"theme,omitempty"`
+
+	// Files are the last opened files, keyed by the absolute path of the
+	// code root.
+	Files map[string]string `json:"files,omitempty"`
+
+	// Bookmarks are the bookmarked files, keyed by the absolute path of the
+	// code root.
+	Bookmarks map[string][]string `json:"bookmarks,omitempty"`
+}
+
+// sessionFilename returns the path of the session file in the user cache
+// directory.
+func sessionFilename() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "gocovsh", "session.json"), nil
+}
+
+// loadSession reads the session file. Missing files, files that can't be
+// parsed, and files of other versions result in an empty session.
+func loadSession(filename string) (session, error) {
+	s := session{Version: sessionVersion}
+
+	bs, err := os.ReadFile(filename) // nolint: gosec
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+
+		return s, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	var loaded session
+	if err := json.Unmarshal(bs, &loaded); err != nil || loaded.Version != sessionVersion {
+		return s, nil
+	}
+
+	return loaded, nil
+}
+
+// save writes the session file, creating its directory if needed.
+func (s session) save(filename string) error {
+	bs, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil { // nolint: gosec
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(filename), err)
+	}
+
+	if err := os.WriteFile(filename, append(bs, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", filename, err)
+	}
+
+	return nil
+}
+
+// restoreSession loads the session, and uses its sort mode and theme unless
+// they are set by flags, environment variables or configuration files.
+// Remembered values that are no longer valid are ignored.
+func (p *Program) restoreSession() error {
+	if p.sessionFile == "" {
+		filename, err := sessionFilename()
+		if err != nil {
+			return fmt.Errorf("failed to find cache directory: %w", err)
+		}
+
+		p.sessionFile = filename
+	}
+
+	s, err := loadSession(p.sessionFile)
+	if err != nil {
+		return err
+	}
+
+	p.state = s
+
+	sortSet := p.config.Sort != "" || p.isFlagPassed("sort") || p.isFlagPassed("sort-by-coverage")
+	if !sortSet && model.SortMode(s.Sort).IsValid() {
+		p.sortMode = s.Sort
+	}
+
+	if _, ok := styles.Themes[strings.ToLower(s.Theme)]; ok && p.config.Theme == "" && !p.isFlagPassed("theme") {
+		p.theme = s.Theme
+	}
+
+	return nil
+}
+
+// selectedFile returns the file selected when the program starts: the first
+// file passed as an argument, or the one opened last time.
+func (p *Program) selectedFile() string {
+	if p.openFile != "" {
+		return p.openFile
+	}
+
+	return p.sessionSelectedFile()
+}
+
+// sessionSelectedFile returns the file opened last time in the code root, if
+// any.
+func (p *Program) sessionSelectedFile() string {
+	return p.state.Files[p.sessionRoot()]
+}
+
+// sessionBookmarks returns the files bookmarked last time in the code root.
+func (p *Program) sessionBookmarks() []string {
+	return p.state.Bookmarks[p.sessionRoot()]
+}
+
+// saveSession remembers the current sort mode and theme, and the last file
+// opened and the bookmarks in the code root. If no file was opened, the
+// previous one is kept.
+func (p *Program) saveSession(sortMode model.SortMode, openedFile string, bookmarks []string) error {
+	p.state.Version = sessionVersion
+	p.state.Sort = string(sortMode)
+	p.state.Theme = p.theme
+
+	if openedFile != "" {
+		if p.state.Files == nil {
+			p.state.Files = map[string]string{}
+		}
+
+		p.state.Files[p.sessionRoot()] = openedFile
+	}
+
+	if len(bookmarks) > 0 {
+		if p.state.Bookmarks == nil {
+			p.state.Bookmarks = map[string][]string{}
+		}
+
+		p.state.Bookmarks[p.sessionRoot()] = bookmarks
+	} else {
+		delete(p.state.Bookmarks, p.sessionRoot())
+	}
+
+	return p.state.save(p.sessionFile)
+}
+
+func (p *Program) sessionRoot() string {
+	root, err := filepath.Abs(p.codeRoot)
+	if err != nil {
+		return p.codeRoot
+	}
+
+	return root
+}
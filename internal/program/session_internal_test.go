@@ -0,0 +1,129 @@
+package program
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	const codeRoot = "../gocovshtest/testdata/general"
+
+	newProgram := func(t *testing.T, filename string, args ...string) *Program {
+		t.Helper()
+
+		flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+		p := New(
+			WithCodeRoot(codeRoot),
+			WithFlagSet(flagSet, append([]string{"-session"}, args...)),
+		)
+		p.sessionFile = filename
+
+		require.NoError(t, p.flagSet.Parse(p.args))
+		require.NoError(t, p.restoreSession())
+
+		return p
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		p := newProgram(t, filepath.Join(t.TempDir(), "session.json"))
+		require.Equal(t, string(model.SortByPath), p.sortMode)
+		require.Empty(t, p.theme)
+		require.Empty(t, p.sessionSelectedFile())
+	})
+
+	t.Run("save and restore", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "gocovsh", "session.json")
+
+		p := newProgram(t, filename, "-sort", "lines", "-theme", "mocha")
+		require.NoError(t, p.saveSession(model.SortByLines, "covered.go", nil))
+
+		p = newProgram(t, filename)
+		require.Equal(t, string(model.SortByLines), p.sortMode)
+		require.Equal(t, "mocha", p.theme)
+		require.Equal(t, "covered.go", p.sessionSelectedFile())
+
+		// without an opened file, the previous one is kept
+		require.NoError(t, p.saveSession(model.SortByLines, "", nil))
+
+		p = newProgram(t, filename)
+		require.Equal(t, "covered.go", p.sessionSelectedFile())
+	})
+
+	t.Run("bookmarks", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "session.json")
+
+		p := newProgram(t, filename)
+		require.NoError(t, p.saveSession(model.SortByPath, "", []string{"covered.go", "main.go"}))
+
+		p = newProgram(t, filename)
+		require.Equal(t, []string{"covered.go", "main.go"}, p.sessionBookmarks())
+
+		// removing all the bookmarks forgets them
+		require.NoError(t, p.saveSession(model.SortByPath, "", nil))
+
+		p = newProgram(t, filename)
+		require.Empty(t, p.sessionBookmarks())
+	})
+
+	t.Run("flags override session", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "session.json")
+		writeFile(t, filename, `{"version": 1, "sort": "lines", "theme": "mocha"}`)
+
+		p := newProgram(t, filename, "-sort", "path", "-theme", "latte")
+		require.Equal(t, string(model.SortByPath), p.sortMode)
+		require.Equal(t, "latte", p.theme)
+	})
+
+	t.Run("environment overrides session", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "session.json")
+		writeFile(t, filename, `{"version": 1, "sort": "lines"}`)
+		t.Setenv("GOCOVSH_SORT", "coverage-desc")
+
+		p := newProgram(t, filename)
+		require.Equal(t, string(model.SortByCoverageDesc), p.sortMode)
+	})
+
+	t.Run("other codebase", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "session.json")
+		writeFile(t, filename, `{"version": 1, "files": {"/some/other/project": "main.go"}}`)
+
+		p := newProgram(t, filename)
+		require.Empty(t, p.sessionSelectedFile())
+	})
+
+	t.Run("invalid values are ignored", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "session.json")
+		writeFile(t, filename, `{"version": 1, "sort": "size", "theme": "unknown"}`)
+
+		p := newProgram(t, filename)
+		require.Equal(t, string(model.SortByPath), p.sortMode)
+		require.Empty(t, p.theme)
+	})
+
+	for name, content := range map[string]string{
+		"other version": `{"version": 2, "sort": "lines"}`,
+		"corrupted":     `{"version": 1, "sort": `,
+	} {
+		content := content
+
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "session.json")
+			writeFile(t, filename, content)
+
+			p := newProgram(t, filename)
+			require.Equal(t, string(model.SortByPath), p.sortMode)
+			require.Equal(t, sessionVersion, p.state.Version)
+		})
+	}
+}
+
+func writeFile(t *testing.T, filename, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+}
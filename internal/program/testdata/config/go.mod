@@ -0,0 +1,3 @@
+module github.com/orlangure/gocovsh/internal/model/testdata/general
+
+go 1.19
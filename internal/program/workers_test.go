@@ -0,0 +1,149 @@
+package program_test
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/program"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule writes a module of the given number of files to a temporary
+// directory, with a coverage profile of all of them. Every third file is
+// generated, and every fifth one ignores its uncovered branch.
+func writeModule(tb testing.TB, files int) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	profile := strings.Builder{}
+	profile.WriteString("mode: set\n")
+
+	require.NoError(tb, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/mod\n"), 0o600))
+
+	for i := 0; i < files; i++ {
+		pkg := fmt.Sprintf("pkg%d", i%10)
+		name := fmt.Sprintf("%s/file%d.go", pkg, i)
+		header, ignore := "", ""
+
+		if i%3 == 0 {
+			header = "// Code generated by test. DO NOT EDIT.\n\n"
+		}
+
+		if i%5 == 0 {
+			ignore = " //gocovsh:ignore"
+		}
+
+		source := header + "package " + pkg + "\n" +
+			"\n" +
+			fmt.Sprintf("func F%d(ok bool) int {\n", i) +
+			"	if !ok {" + ignore + "\n" +
+			"		return 0\n" +
+			"	}\n" +
+			"\n" +
+			"	return 1\n" +
+			"}\n"
+
+		require.NoError(tb, os.MkdirAll(filepath.Join(dir, pkg), 0o700))
+		require.NoError(tb, os.WriteFile(filepath.Join(dir, name), []byte(source), 0o600))
+
+		offset := strings.Count(header, "\n")
+		fmt.Fprintf(&profile, "example.com/mod/%s:%d.24,%d.10 1 1\n", name, offset+3, offset+4)
+		fmt.Fprintf(&profile, "example.com/mod/%s:%d.10,%d.3 1 0\n", name, offset+4, offset+6)
+		fmt.Fprintf(&profile, "example.com/mod/%s:%d.2,%d.10 1 1\n", name, offset+8, offset+8)
+	}
+
+	require.NoError(tb, os.WriteFile(filepath.Join(dir, "profile.cover"), []byte(profile.String()), 0o600))
+
+	return dir
+}
+
+func runWorkers(tb testing.TB, dir string, args ...string) (string, error) {
+	tb.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	p := program.New(
+		program.WithOutput(buf),
+		program.WithCodeRoot(dir),
+		program.WithFlagSet(flagSet, append([]string{"-profile", "profile.cover", "-json", "-sort", "coverage-asc"}, args...)),
+	)
+
+	err := p.Run()
+
+	return buf.String(), err
+}
+
+func TestWorkers(t *testing.T) {
+	dir := writeModule(t, 100)
+
+	t.Run("same output as serial", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"-hide-generated", "-ignore-comments"}} {
+			serial, err := runWorkers(t, dir, append([]string{"-workers", "1"}, args...)...)
+			require.NoError(t, err)
+
+			parallel, err := runWorkers(t, dir, append([]string{"-workers", "8"}, args...)...)
+			require.NoError(t, err)
+
+			require.Equal(t, serial, parallel)
+		}
+	})
+
+	t.Run("generated and ignored", func(t *testing.T) {
+		out, err := runWorkers(t, dir, "-workers", "8", "-hide-generated", "-ignore-comments")
+		require.NoError(t, err)
+		require.NotContains(t, out, `"pkg0/file0.go"`)
+		require.Contains(t, out, `"pkg1/file1.go"`)
+		require.Contains(t, out, `"percentage": 100`)
+	})
+
+	t.Run("invalid workers", func(t *testing.T) {
+		_, err := runWorkers(t, dir, "-workers", "0")
+		require.EqualError(t, err, "invalid workers 0: must be positive")
+	})
+}
+
+func BenchmarkWorkers(b *testing.B) {
+	dir := writeModule(b, 2000)
+
+	for _, bench := range []struct {
+		name    string
+		workers int
+	}{{"serial", 1}, {"parallel", runtime.NumCPU()}} {
+		workers := bench.workers
+
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := runWorkers(b, dir, "-workers", fmt.Sprint(workers), "-check-sources"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkIgnoreComments measures the cost of reading every source file of
+// the profile for the ignore comments.
+func BenchmarkIgnoreComments(b *testing.B) {
+	dir := writeModule(b, 2000)
+
+	for _, bench := range []struct {
+		name string
+		args []string
+	}{{"disabled", nil}, {"enabled", []string{"-ignore-comments"}}} {
+		args := bench.args
+
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := runWorkers(b, dir, args...); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+// NewDiff creates a new report of the changed statements only. The changed
+// lines are keyed by file name. Changed files missing from the profiles are
+// reported as not covered at all, after the files of the profiles. Test files
+// are skipped, because they are not measured.
+func NewDiff(profiles []*cover.Profile, changedLines map[string][]int) Report {
+	r := Report{Files: make([]File, 0, len(changedLines)), Mode: Mode(profiles)}
+	profiled := make(map[string]bool, len(profiles))
+
+	for _, p := range profiles {
+		profiled[p.FileName] = true
+
+		lines := changedLines[p.FileName]
+		if len(lines) == 0 {
+			continue
+		}
+
+		f := File{Path: p.FileName}
+		f.Covered, f.Total = ChangedStatements(p, lines)
+		f.Percentage = percentage(f.Covered, f.Total)
+
+		r.Covered += f.Covered
+		r.Total += f.Total
+		r.Files = append(r.Files, f)
+	}
+
+	missing := make([]string, 0, len(changedLines))
+
+	for file, lines := range changedLines {
+		if !profiled[file] && len(lines) > 0 && !strings.HasSuffix(file, "_test.go") {
+			missing = append(missing, file)
+		}
+	}
+
+	sort.Strings(missing)
+
+	for _, file := range missing {
+		r.Files = append(r.Files, File{Path: file, NoProfile: true})
+	}
+
+	r.Percentage = percentage(r.Covered, r.Total)
+
+	return r
+}
+
+// ChangedStatements returns the number of covered statements in the blocks
+// of the profile that include any of the changed lines, and the total number
+// of statements in them. Blocks are counted as a whole, so a changed line
+// makes all the statements of its block count.
+func ChangedStatements(p *cover.Profile, changedLines []int) (covered, total int64) {
+	if len(changedLines) == 0 {
+		return 0, 0
+	}
+
+	changed := make(map[int]bool, len(changedLines))
+	for _, line := range changedLines {
+		changed[line] = true
+	}
+
+	for _, b := range p.Blocks {
+		if !blockChanged(b, changed) {
+			continue
+		}
+
+		total += int64(b.NumStmt)
+
+		if b.Count > 0 {
+			covered += int64(b.NumStmt)
+		}
+	}
+
+	return covered, total
+}
+
+func blockChanged(b cover.ProfileBlock, changed map[int]bool) bool {
+	for line := b.StartLine; line <= b.EndLine; line++ {
+		if changed[line] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteDiffText writes the diff report to w as plain text, one line per
+// file followed by the total.
+func (r Report) WriteDiffText(w io.Writer) error {
+	var b strings.Builder
+
+	for _, f := range r.Files {
+		if f.NoProfile {
+			fmt.Fprintf(&b, "%s: %.2f%% (no coverage data)\n", f.Path, f.Percentage)
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s: %.2f%% (%d/%d changed statements)\n", f.Path, f.Percentage, f.Covered, f.Total)
+	}
+
+	fmt.Fprintf(&b, "total: %.2f%% (%d/%d changed statements)\n", r.Percentage, r.Covered, r.Total)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
@@ -0,0 +1,183 @@
+// Package report aggregates coverage profiles into a summary that can be
+// printed in non-interactive modes, such as JSON output.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/tools/cover"
+)
+
+// Report is the aggregated coverage of a set of files. Statement counts are
+// summed across all files.
+type Report struct {
+	Covered    int64   `json:"covered"`
+	Files      []File  `json:"files"`
+	Mode       string  `json:"mode,omitempty"`
+	Percentage float64 `json:"percentage"`
+	Total      int64   `json:"total"`
+}
+
+// File is the coverage of a single source file.
+type File struct {
+	Covered    int64   `json:"covered"`
+	Path       string  `json:"path"`
+	Percentage float64 `json:"percentage"`
+	Total      int64   `json:"total"`
+
+	// NoProfile is set for changed files that are not in the coverage
+	// profile, for example because no tests ran for them
+	NoProfile bool `json:"no_profile,omitempty"`
+}
+
+// New creates a new report from the provided profiles. The order of the
+// files is preserved.
+func New(profiles []*cover.Profile) Report {
+	r := Report{Files: make([]File, 0, len(profiles)), Mode: Mode(profiles)}
+
+	for _, p := range profiles {
+		f := File{Path: p.FileName}
+
+		for _, b := range p.Blocks {
+			f.Total += int64(b.NumStmt)
+
+			if b.Count > 0 {
+				f.Covered += int64(b.NumStmt)
+			}
+		}
+
+		f.Percentage = percentage(f.Covered, f.Total)
+
+		r.Covered += f.Covered
+		r.Total += f.Total
+		r.Files = append(r.Files, f)
+	}
+
+	r.Percentage = percentage(r.Covered, r.Total)
+
+	return r
+}
+
+// Mode returns the coverage mode of the profiles, such as "set", "count" or
+// "atomic". All the files of a profile share the mode of its header. It
+// returns an empty string if there are no profiles.
+func Mode(profiles []*cover.Profile) string {
+	if len(profiles) == 0 {
+		return ""
+	}
+
+	return profiles[0].Mode
+}
+
+// WriteJSON writes the report to w as an indented JSON document.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// WriteText writes the report to w as plain text, one line per file followed
+// by the total.
+func (r Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	for _, f := range r.Files {
+		fmt.Fprintf(&b, "%s: %.2f%% (%d/%d statements)\n", f.Path, f.Percentage, f.Covered, f.Total)
+	}
+
+	fmt.Fprintf(&b, "total: %.2f%% (%d/%d statements)\n", r.Percentage, r.Covered, r.Total)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteTable writes the report to w as a table aligned in columns, one row
+// per file followed by the total.
+func (r Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, f := range r.Files {
+		fmt.Fprintf(tw, "%s\t%7.2f%%\t%d/%d\n", f.Path, f.Percentage, f.Covered, f.Total)
+	}
+
+	fmt.Fprintf(tw, "total\t%7.2f%%\t%d/%d\n", r.Percentage, r.Covered, r.Total)
+
+	return tw.Flush()
+}
+
+// Markers of the status column of the Markdown table.
+const (
+	markdownPassed = "✅"
+	markdownFailed = "❌"
+)
+
+// WriteMarkdown writes the report to w as a GitHub-flavored Markdown table,
+// one row per file followed by the total. If threshold is set, a
+// status column marks the files below the coverage it returns for their
+// path, and the total below the one it returns for the empty path.
+func (r Report) WriteMarkdown(w io.Writer, threshold func(path string) float64) error {
+	var b strings.Builder
+
+	b.WriteString("| File | Coverage | Statements |")
+
+	if threshold != nil {
+		b.WriteString(" Status |")
+	}
+
+	b.WriteString("\n| :--- | ---: | ---: |")
+
+	if threshold != nil {
+		b.WriteString(" :---: |")
+	}
+
+	b.WriteString("\n")
+
+	row := func(path, name string, pct float64, covered, total int64) {
+		fmt.Fprintf(&b, "| %s | %.2f%% | %d/%d |", name, pct, covered, total)
+
+		if threshold != nil {
+			status := markdownPassed
+			if ExactPercentage(covered, total) < threshold(path) {
+				status = markdownFailed
+			}
+
+			fmt.Fprintf(&b, " %s |", status)
+		}
+
+		b.WriteString("\n")
+	}
+
+	for _, f := range r.Files {
+		row(f.Path, "`"+strings.ReplaceAll(f.Path, "|", "\\|")+"`", f.Percentage, f.Covered, f.Total)
+	}
+
+	row("", "**Total**", r.Percentage, r.Covered, r.Total)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// percentage returns the rounded percentage of covered statements, or 0 if
+// there are no statements.
+func percentage(covered, total int64) float64 {
+	return math.Round(ExactPercentage(covered, total)*100) / 100
+}
+
+// ExactPercentage returns the percentage of covered statements without
+// rounding, or 0 if there are no statements. Thresholds are compared with it,
+// so that 79.996% is below 80%.
+func ExactPercentage(covered, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(covered) / float64(total) * 100
+}
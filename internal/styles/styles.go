@@ -1,7 +1,13 @@
 package styles
 
 import (
+	"fmt"
+	"math"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	catppuccin "github.com/catppuccin/go"
 	"github.com/charmbracelet/lipgloss"
@@ -14,45 +20,263 @@ type Theme struct {
 	SecondaryColor string
 	InactiveColor  string
 
+	// PartialColor is used for lines that are partially covered, where some
+	// blocks were executed and others were not
+	PartialColor string
+
+	// markers prefix the lines when colors are disabled; they are padded to
+	// the same width
+	CoveredMarker   string
+	UncoveredMarker string
+	PartialMarker   string
+	NeutralMarker   string
+
 	NeutralLine   lipgloss.Style
 	CoveredLine   lipgloss.Style
 	UncoveredLine lipgloss.Style
+	PartialLine   lipgloss.Style
+
+	// HeatLines are the styles of covered lines by hit count, from the
+	// least executed to the most executed ones.
+	HeatLines []lipgloss.Style
 }
 
+// heatLevels is the number of styles of the heatmap.
+const heatLevels = 5
+
 func (t *Theme) setStyles() {
 	t.NeutralLine = lipgloss.NewStyle().Foreground(lipgloss.Color(t.InactiveColor))
 	t.CoveredLine = lipgloss.NewStyle().Foreground(lipgloss.Color(t.PrimaryColor))
 	t.UncoveredLine = lipgloss.NewStyle().Foreground(lipgloss.Color(t.SecondaryColor))
+	t.PartialLine = lipgloss.NewStyle().Foreground(lipgloss.Color(t.PartialColor))
+
+	// the ramp goes from the inactive color towards the covered one, so that
+	// rarely executed lines are dim, and hot paths are bright
+	t.HeatLines = make([]lipgloss.Style, heatLevels)
+	for i := range t.HeatLines {
+		ratio := 0.4 + 0.6*float64(i)/float64(heatLevels-1)
+		t.HeatLines[i] = lipgloss.NewStyle().Foreground(lipgloss.Color(blend(t.InactiveColor, t.PrimaryColor, ratio)))
+	}
 }
 
-func Default() Theme {
-	t := Theme{
-		PrimaryColor:   "#00ff00",
-		SecondaryColor: "#ff0000",
-		InactiveColor:  "#7f7f7f",
+// blend mixes two colors in "#rrggbb" format; zero ratio returns the first
+// color, and 1 returns the second one. Colors in other formats are not
+// blended, and the second one is returned.
+func blend(from, to string, ratio float64) string {
+	var r1, g1, b1, r2, g2, b2 int
+
+	if _, err := fmt.Sscanf(from, "#%02x%02x%02x", &r1, &g1, &b1); err != nil {
+		return to
 	}
-	t.setStyles()
 
-	return t
+	if _, err := fmt.Sscanf(to, "#%02x%02x%02x", &r2, &g2, &b2); err != nil {
+		return to
+	}
+
+	mix := func(a, b int) int { return a + int(math.Round(float64(b-a)*ratio)) }
+
+	return fmt.Sprintf("#%02x%02x%02x", mix(r1, r2), mix(g1, g2), mix(b1, b2))
 }
 
-func Catppuccin(cpn catppuccin.Theme) Theme {
+// Themes maps the names of the available themes to the functions creating
+// them. New themes only need to be added here.
+var Themes = map[string]func() Theme{
+	"default":    Default,
+	"dark":       Dark,
+	"light":      Light,
+	"colorblind": Colorblind,
+	"mocha":      func() Theme { return Catppuccin(catppuccin.Mocha) },
+	"latte":      func() Theme { return Catppuccin(catppuccin.Latte) },
+	"frappe":     func() Theme { return Catppuccin(catppuccin.Frappe) },
+	"macchiato":  func() Theme { return Catppuccin(catppuccin.Macchiato) },
+}
+
+// ThemeNames returns the names of the available themes in alphabetical
+// order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+
+	for name := range Themes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func newTheme(primary, secondary, inactive, partial string) Theme {
 	t := Theme{
-		PrimaryColor:   cpn.Green().Hex,
-		SecondaryColor: cpn.Red().Hex,
-		InactiveColor:  cpn.Subtext1().Hex,
+		PrimaryColor:   primary,
+		SecondaryColor: secondary,
+		InactiveColor:  inactive,
+		PartialColor:   partial,
 	}
 	t.setStyles()
+	t.setMarkers("+", "-")
 
 	return t
 }
 
-func SetTheme() {
-	theme := os.Getenv("GOCOVSH_THEME")
+// partialGlyph marks lines that are partially covered without colors.
+const partialGlyph = "~"
+
+// setMarkers pads the glyphs, and separates them from the lines.
+func (t *Theme) setMarkers(covered, uncovered string) {
+	width := lipgloss.Width(partialGlyph)
+
+	for _, glyph := range []string{covered, uncovered} {
+		if w := lipgloss.Width(glyph); w > width {
+			width = w
+		}
+	}
+
+	pad := func(glyph string) string {
+		return glyph + strings.Repeat(" ", width-lipgloss.Width(glyph)+1)
+	}
+
+	t.CoveredMarker, t.UncoveredMarker, t.NeutralMarker = pad(covered), pad(uncovered), pad("")
+	t.PartialMarker = pad(partialGlyph)
+}
+
+// MarkersLegend explains the markers.
+func (t *Theme) MarkersLegend() string {
+	return t.CoveredMarker + "covered • " + t.UncoveredMarker + "not covered • " + t.PartialMarker + "partial"
+}
+
+// ColorsLegend explains the colors of the lines.
+func (t *Theme) ColorsLegend() string {
+	return t.CoveredLine.Render("■ covered") + " • " + t.UncoveredLine.Render("■ not covered") + " • " +
+		t.PartialLine.Render("■ partial")
+}
+
+// Customization overrides colors and markers of a theme. Empty values keep
+// the ones of the theme.
+type Customization struct {
+	CoveredColor   string
+	UncoveredColor string
+	PartialColor   string
+	CoveredGlyph   string
+	UncoveredGlyph string
+}
+
+// Customize applies the customization to the theme. Colors can be hex
+// values such as #00ff00, ANSI color numbers from 0 to 255, or the names of
+// the 16 basic ANSI colors such as green or bright-red.
+func (t *Theme) Customize(c Customization) error {
+	custom := *t
+
+	for _, color := range []struct {
+		name  string
+		value string
+		dst   *string
+	}{
+		{"covered", c.CoveredColor, &custom.PrimaryColor},
+		{"uncovered", c.UncoveredColor, &custom.SecondaryColor},
+		{"partial", c.PartialColor, &custom.PartialColor},
+	} {
+		if color.value == "" {
+			continue
+		}
+
+		parsed, err := ParseColor(color.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s color: %w", color.name, err)
+		}
 
-	if variant := catppuccin.Variant(theme); variant != nil {
-		CurrentTheme = Catppuccin(variant)
-	} else {
+		*color.dst = parsed
+	}
+
+	covered, uncovered := strings.TrimSpace(custom.CoveredMarker), strings.TrimSpace(custom.UncoveredMarker)
+
+	if c.CoveredGlyph != "" {
+		covered = c.CoveredGlyph
+	}
+
+	if c.UncoveredGlyph != "" {
+		uncovered = c.UncoveredGlyph
+	}
+
+	custom.setStyles()
+	custom.setMarkers(covered, uncovered)
+	*t = custom
+
+	return nil
+}
+
+// ansiColors maps the names of the basic ANSI colors to their numbers.
+var ansiColors = map[string]int{
+	"black": 0, "red": 1, "green": 2, "yellow": 3, "blue": 4, "magenta": 5, "cyan": 6, "white": 7,
+	"bright-black": 8, "gray": 8, "grey": 8, "bright-red": 9, "bright-green": 10, "bright-yellow": 11,
+	"bright-blue": 12, "bright-magenta": 13, "bright-cyan": 14, "bright-white": 15,
+}
+
+// ParseColor validates the color, and returns it in a format understood by
+// lipgloss: hex values are kept, and names are converted to ANSI numbers.
+func ParseColor(s string) (string, error) {
+	if n, ok := ansiColors[strings.ToLower(s)]; ok {
+		return strconv.Itoa(n), nil
+	}
+
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 || n > 255 {
+			return "", fmt.Errorf("%q: ANSI color numbers must be between 0 and 255", s)
+		}
+
+		return s, nil
+	}
+
+	if hexColor.MatchString(s) {
+		return strings.ToLower(s), nil
+	}
+
+	return "", fmt.Errorf("%q: must be a hex color such as #00ff00, an ANSI color number, or a name such as green", s)
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+func Default() Theme {
+	return newTheme("#00ff00", "#ff0000", "#7f7f7f", "#ffff00")
+}
+
+// Dark is a softer version of the default theme for dark terminals.
+func Dark() Theme {
+	return newTheme("#5fd75f", "#ff5f5f", "#8a8a8a", "#d7d75f")
+}
+
+// Light uses darker colors that remain readable on light terminals.
+func Light() Theme {
+	return newTheme("#007a00", "#c00000", "#6c6c6c", "#a06c00")
+}
+
+// Colorblind uses blue for covered code, orange for uncovered code and
+// reddish purple for partially covered code, which are distinguishable with
+// the most common color vision deficiencies.
+func Colorblind() Theme {
+	return newTheme("#0072b2", "#e69f00", "#999999", "#cc79a7")
+}
+
+func Catppuccin(cpn catppuccin.Theme) Theme {
+	return newTheme(cpn.Green().Hex, cpn.Red().Hex, cpn.Subtext1().Hex, cpn.Yellow().Hex)
+}
+
+// SetTheme uses the theme set in GOCOVSH_THEME environment variable, or the
+// default one.
+func SetTheme() {
+	if !UseTheme(os.Getenv("GOCOVSH_THEME")) {
 		CurrentTheme = Default()
 	}
 }
+
+// UseTheme makes the theme with the provided name current, ignoring the case.
+// It reports whether the theme exists.
+func UseTheme(name string) bool {
+	theme, ok := Themes[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+
+	CurrentTheme = theme()
+
+	return true
+}
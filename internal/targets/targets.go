@@ -0,0 +1,145 @@
+// Package targets maps globs of paths to the minimum coverage expected of the
+// files and directories matching them, so that different parts of a project
+// can have different standards.
+package targets
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/orlangure/gocovsh/internal/report"
+)
+
+// Targets are the coverage targets of the paths matching globs. The zero
+// value has no targets. Use New to create a new instance.
+type Targets struct {
+	rules []rule
+}
+
+type rule struct {
+	glob   string
+	target float64
+}
+
+// New creates targets from the percentages (0-100) keyed by globs, such as
+// "internal/**", relative to the module root.
+func New(globs map[string]float64) (Targets, error) {
+	rules := make([]rule, 0, len(globs))
+
+	for glob, target := range globs {
+		if !doublestar.ValidatePattern(glob) {
+			return Targets{}, fmt.Errorf("invalid glob %q", glob)
+		}
+
+		if target < 0 || target > 100 {
+			return Targets{}, fmt.Errorf("invalid target %v of %q: must be between 0 and 100", target, glob)
+		}
+
+		rules = append(rules, rule{glob: glob, target: target})
+	}
+
+	// the most specific globs are tried first
+	sort.Slice(rules, func(i, j int) bool {
+		si, sj := specificity(rules[i].glob), specificity(rules[j].glob)
+		if si != sj {
+			return si > sj
+		}
+
+		return rules[i].glob < rules[j].glob
+	})
+
+	return Targets{rules: rules}, nil
+}
+
+// specificity is the number of characters of the glob that aren't wildcards,
+// so that "internal/model/**" is more specific than "internal/**".
+func specificity(glob string) int {
+	n := 0
+
+	for _, r := range glob {
+		if !strings.ContainsRune("*?[]{}", r) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Empty reports whether there are no targets.
+func (t Targets) Empty() bool {
+	return len(t.rules) == 0
+}
+
+// For returns the target of the file or directory: the one of the most
+// specific glob matching it. It reports whether any glob matches.
+func (t Targets) For(p string) (float64, bool) {
+	for _, r := range t.rules {
+		if matched, err := doublestar.Match(r.glob, p); err == nil && matched {
+			return r.target, true
+		}
+	}
+
+	return 0, false
+}
+
+// Violation is a file or directory whose coverage is below its target.
+type Violation struct {
+	Path       string
+	Percentage float64
+	Target     float64
+}
+
+// Check returns the files, and the directories containing them, that are
+// below their targets, sorted by path. The coverage of a directory includes
+// all the files under it. Paths without statements are skipped.
+func (t Targets) Check(files []report.File) []Violation {
+	if t.Empty() {
+		return nil
+	}
+
+	type stats struct{ covered, total int64 }
+
+	paths := make(map[string]*stats, len(files))
+
+	for _, f := range files {
+		paths[f.Path] = &stats{covered: f.Covered, total: f.Total}
+
+		for dir := path.Dir(f.Path); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			s, ok := paths[dir]
+			if !ok {
+				s = &stats{}
+				paths[dir] = s
+			}
+
+			s.covered += f.Covered
+			s.total += f.Total
+		}
+	}
+
+	var violations []Violation
+
+	for p, s := range paths {
+		target, ok := t.For(p)
+		if !ok {
+			continue
+		}
+
+		// without statements there is nothing to cover, like in the overall
+		// percentage of the report
+		if s.total == 0 {
+			continue
+		}
+
+		percentage := report.ExactPercentage(s.covered, s.total)
+		if percentage < target {
+			violations = append(violations, Violation{Path: p, Percentage: percentage, Target: target})
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool { return violations[i].Path < violations[j].Path })
+
+	return violations
+}
@@ -0,0 +1,109 @@
+package targets_test
+
+import (
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/report"
+	"github.com/orlangure/gocovsh/internal/targets"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFor(t *testing.T) {
+	t.Parallel()
+
+	tt, err := targets.New(map[string]float64{
+		"**":                   50,
+		"internal/**":          80,
+		"internal/model/**":    60,
+		"internal/model/*.go":  70,
+		"cmd/gocovsh/main.go":  0,
+		"internal/*/errors.go": 90,
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		path   string
+		target float64
+	}{
+		{"main.go", 50},
+		{"internal", 80},
+		{"internal/program/program.go", 80},
+		{"internal/model", 60},
+		{"internal/model/sub/sub.go", 60},
+		{"internal/model/model.go", 70},
+		{"internal/model/errors.go", 90},
+		{"cmd/gocovsh/main.go", 0},
+	}
+
+	for _, test := range tests {
+		target, ok := tt.For(test.path)
+		require.True(t, ok, test.path)
+		require.Equal(t, test.target, target, test.path)
+	}
+}
+
+func TestForWithoutMatch(t *testing.T) {
+	t.Parallel()
+
+	tt, err := targets.New(map[string]float64{"internal/**": 80})
+	require.NoError(t, err)
+
+	_, ok := tt.For("cmd/main.go")
+	require.False(t, ok)
+
+	_, ok = targets.Targets{}.For("cmd/main.go")
+	require.False(t, ok)
+	require.True(t, targets.Targets{}.Empty())
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := targets.New(map[string]float64{"internal/[": 80})
+	require.EqualError(t, err, `invalid glob "internal/["`)
+
+	_, err = targets.New(map[string]float64{"internal/**": 120})
+	require.EqualError(t, err, `invalid target 120 of "internal/**": must be between 0 and 100`)
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tt, err := targets.New(map[string]float64{
+		"pkg/a/**":   60,
+		"pkg/a/a.go": 50,
+		"pkg/b/*.go": 100,
+	})
+	require.NoError(t, err)
+
+	violations := tt.Check([]report.File{
+		{Path: "main.go", Covered: 0, Total: 1},
+		{Path: "pkg/a/a.go", Covered: 1, Total: 2},
+		{Path: "pkg/a/util.go", Covered: 0, Total: 2},
+		{Path: "pkg/b/b.go", Covered: 1, Total: 1},
+	})
+
+	require.Equal(t, []targets.Violation{
+		{Path: "pkg/a", Percentage: 25, Target: 60},
+		{Path: "pkg/a/util.go", Percentage: 0, Target: 60},
+	}, violations)
+}
+
+func TestCheckWithoutStatements(t *testing.T) {
+	t.Parallel()
+
+	tt, err := targets.New(map[string]float64{"**": 80})
+	require.NoError(t, err)
+
+	violations := tt.Check([]report.File{
+		{Path: "pkg/a/doc.go", Covered: 0, Total: 0},
+		{Path: "pkg/b/b.go", Covered: 1, Total: 2},
+		{Path: "pkg/b/types.go", Covered: 0, Total: 0},
+	})
+
+	require.Equal(t, []targets.Violation{
+		{Path: "pkg", Percentage: 50, Target: 80},
+		{Path: "pkg/b", Percentage: 50, Target: 80},
+		{Path: "pkg/b/b.go", Percentage: 50, Target: 80},
+	}, violations)
+}
@@ -0,0 +1,130 @@
+// Package testfind lists the tests, benchmarks, fuzz tests and examples of a
+// package, and the functions they call.
+package testfind
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Test is a test function of a package.
+type Test struct {
+	// File is the name of the test file, without the directory
+	File string
+	Name string
+	Line int
+
+	// calls are the names of the functions and methods called in the test
+	calls map[string]bool
+}
+
+// Calls reports whether the test calls the function or the method with the
+// provided name. Package and receiver names are ignored, so methods of
+// different types with the same name can't be told apart.
+func (t *Test) Calls(name string) bool {
+	return t.calls[name]
+}
+
+// prefixes of the names of test functions recognized by go test
+var prefixes = []string{"Test", "Benchmark", "Fuzz", "Example"}
+
+// Find parses the test files in the directory, and returns their tests
+// ordered by file name and position. Files that can't be parsed are
+// reported as errors.
+func Find(dir string) ([]*Test, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var (
+		tests []*Test
+		fset  = token.NewFileSet()
+	)
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(dir, e.Name()), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", e.Name(), err)
+		}
+
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Body == nil || !isTestName(fn.Name.Name) {
+				continue
+			}
+
+			tests = append(tests, &Test{
+				File:  e.Name(),
+				Name:  fn.Name.Name,
+				Line:  fset.Position(fn.Pos()).Line,
+				calls: calledNames(fn.Body),
+			})
+		}
+	}
+
+	sort.SliceStable(tests, func(i, j int) bool { return tests[i].File < tests[j].File })
+
+	return tests, nil
+}
+
+// isTestName reports whether the function is run by go test: its name is a
+// prefix followed by nothing or anything but a lower case letter.
+func isTestName(name string) bool {
+	for _, prefix := range prefixes {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		rest := name[len(prefix):]
+		if rest == "" {
+			return true
+		}
+
+		r, _ := utf8.DecodeRuneInString(rest)
+
+		return !unicode.IsLower(r)
+	}
+
+	return false
+}
+
+// calledNames collects the names of the functions called in the node,
+// including the ones called in function literals.
+func calledNames(node ast.Node) map[string]bool {
+	calls := map[string]bool{}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		switch fun := call.Fun.(type) {
+		case *ast.Ident:
+			calls[fun.Name] = true
+		case *ast.SelectorExpr:
+			calls[fun.Sel.Name] = true
+		case *ast.IndexExpr:
+			if id, ok := fun.X.(*ast.Ident); ok {
+				calls[id.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	return calls
+}
@@ -0,0 +1,67 @@
+package testfind_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orlangure/gocovsh/internal/testfind"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.go": "package a\n\nfunc Foo() {}\n",
+		"b_test.go": `package a
+
+import "testing"
+
+func TestB(t *testing.T) {
+	t.Run("sub", func(t *testing.T) { new(T).Bar() })
+}
+
+func helper() { Foo() }
+`,
+		"a_test.go": `package a
+
+import "testing"
+
+func TestFoo(t *testing.T) { Foo() }
+
+func Testing(t *testing.T) {}
+
+func BenchmarkFoo(b *testing.B) {}
+
+func Example() {}
+`,
+	}
+
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	tests, err := testfind.Find(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(tests))
+	for _, test := range tests {
+		names = append(names, test.File+":"+test.Name)
+	}
+
+	require.Equal(t, []string{"a_test.go:TestFoo", "a_test.go:BenchmarkFoo", "a_test.go:Example", "b_test.go:TestB"}, names)
+	require.Equal(t, 5, tests[0].Line)
+	require.True(t, tests[0].Calls("Foo"))
+	require.False(t, tests[3].Calls("Foo"))
+	require.True(t, tests[3].Calls("Bar"))
+}
+
+func TestFindInvalid(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "x_test.go"), []byte("package"), 0o600))
+
+	_, err := testfind.Find(dir)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to parse x_test.go")
+}
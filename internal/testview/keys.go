@@ -0,0 +1,51 @@
+package testview
+
+import "github.com/charmbracelet/bubbles/key"
+
+// KeyMap includes testview key mappings.
+type KeyMap struct {
+	Up   key.Binding
+	Down key.Binding
+	Home key.Binding
+	End  key.Binding
+	Open key.Binding
+	Back key.Binding
+	Help key.Binding
+	Quit key.Binding
+}
+
+// DefaultKeyMap is the default KeyMap used by testview package.
+var DefaultKeyMap = KeyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Home: key.NewBinding(
+		key.WithKeys("home", "g"),
+		key.WithHelp("g/home", "top"),
+	),
+	End: key.NewBinding(
+		key.WithKeys("end", "G"),
+		key.WithHelp("G/end", "bottom"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open in $EDITOR"),
+	),
+	Back: key.NewBinding(
+		key.WithKeys("T", "esc"),
+		key.WithHelp("T/esc", "back"),
+	),
+	Help: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "help"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
+	),
+}
@@ -0,0 +1,310 @@
+// Package testview provides a bubbletea component for displaying the tests of
+// a package, so that one of them can be opened. The tests calling a function
+// of interest are displayed first.
+package testview
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/orlangure/gocovsh/internal/styles"
+	"github.com/orlangure/gocovsh/internal/testfind"
+)
+
+const ellipsis = "…"
+
+var (
+	titleStyle = func() lipgloss.Style {
+		b := lipgloss.RoundedBorder()
+		b.Right = "├"
+		return lipgloss.NewStyle().BorderStyle(b).Padding(0, 1)
+	}()
+
+	rowStyle     = lipgloss.NewStyle().PaddingLeft(2)
+	summaryStyle = lipgloss.NewStyle().Padding(0, 4, 1)
+	helpStyle    = lipgloss.NewStyle().Padding(0, 0, 1, 4)
+)
+
+// New creates a new testview model which is rendered into the provided width
+// and height.
+func New(width, height int) Model {
+	return Model{
+		viewport: viewport.New(width, height),
+		help:     help.New(),
+		showHelp: true,
+		width:    width,
+		height:   height,
+	}
+}
+
+// Model is the testview model. Use New to create a new instance.
+type Model struct {
+	viewport viewport.Model
+	help     help.Model
+	width    int
+	height   int
+	title    string
+	tests    []*testfind.Test
+	focus    string
+	related  int
+	cursor   int
+	showHelp bool
+}
+
+// Update is used to update the internal model state based on the external
+// events.
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, DefaultKeyMap.Up):
+			m.moveCursor(m.cursor - 1)
+		case key.Matches(msg, DefaultKeyMap.Down):
+			m.moveCursor(m.cursor + 1)
+		case key.Matches(msg, DefaultKeyMap.Home):
+			m.moveCursor(0)
+		case key.Matches(msg, DefaultKeyMap.End):
+			m.moveCursor(len(m.tests) - 1)
+		}
+	}
+
+	return m, nil
+}
+
+// View renders the model to be displayed.
+func (m *Model) View() string {
+	sections := []string{m.headerView(), m.summaryView(), m.viewport.View()}
+
+	if helpView := m.helpView(); helpView != "" {
+		sections = append(sections, helpView)
+	}
+
+	return strings.Join(sections, "\n")
+}
+
+// SetTitle sets the title of the testview, usually the directory of the
+// tests.
+func (m *Model) SetTitle(title string) {
+	m.title = title
+	m.recalculateSize()
+}
+
+// SetTests sets the tests to be displayed. The ones calling the focused
+// function, if any, are moved to the top; others keep their order.
+func (m *Model) SetTests(tests []*testfind.Test, focus string) {
+	m.tests = append([]*testfind.Test(nil), tests...)
+	m.focus = focus
+	m.related = 0
+
+	if focus != "" {
+		sort.SliceStable(m.tests, func(i, j int) bool {
+			return m.tests[i].Calls(focus) && !m.tests[j].Calls(focus)
+		})
+
+		for _, t := range m.tests {
+			if t.Calls(focus) {
+				m.related++
+			}
+		}
+	}
+
+	m.cursor = 0
+	m.recalculateSize()
+	m.redrawTests()
+	m.viewport.SetYOffset(0)
+}
+
+// Selected returns the test under the cursor, or nil if there are no tests.
+func (m *Model) Selected() *testfind.Test {
+	if len(m.tests) == 0 {
+		return nil
+	}
+
+	return m.tests[m.cursor]
+}
+
+// SetWidth sets the width of the testview.
+func (m *Model) SetWidth(width int) {
+	m.setSize(width, m.height)
+}
+
+// SetHeight sets the height of the testview.
+func (m *Model) SetHeight(height int) {
+	m.setSize(m.width, height)
+}
+
+// ShortHelp implements help.KeyMap interface.
+func (m *Model) ShortHelp() []key.Binding {
+	return []key.Binding{
+		DefaultKeyMap.Up,
+		DefaultKeyMap.Down,
+		DefaultKeyMap.Open,
+		DefaultKeyMap.Back,
+		DefaultKeyMap.Help,
+	}
+}
+
+// FullHelp implements help.KeyMap interface.
+func (m *Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{DefaultKeyMap.Up, DefaultKeyMap.Down, DefaultKeyMap.Home, DefaultKeyMap.End},
+		{DefaultKeyMap.Open, DefaultKeyMap.Back, DefaultKeyMap.Help, DefaultKeyMap.Quit},
+	}
+}
+
+// SetShowHelp allows to hide or show the help section.
+func (m *Model) SetShowHelp(showHelp bool) {
+	m.showHelp = showHelp
+	m.setSize(m.width, m.height)
+}
+
+// SetShowFullHelp allows to view extended help section, if visible.
+func (m *Model) SetShowFullHelp(showFullHelp bool) {
+	m.help.ShowAll = showFullHelp
+	m.setSize(m.width, m.height)
+}
+
+// moveCursor selects the test at the index, and scrolls the viewport so that
+// it stays visible.
+func (m *Model) moveCursor(cursor int) {
+	if len(m.tests) == 0 {
+		return
+	}
+
+	m.cursor = max(0, min(cursor, len(m.tests)-1))
+
+	switch {
+	case m.cursor < m.viewport.YOffset:
+		m.viewport.SetYOffset(m.cursor)
+	case m.cursor >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(m.cursor - m.viewport.Height + 1)
+	}
+
+	m.redrawTests()
+}
+
+func (m *Model) setSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.help.Width = width
+	m.viewport.Width = width
+	m.recalculateSize()
+	m.redrawTests()
+}
+
+func (m *Model) recalculateSize() {
+	height := m.height
+	height -= lipgloss.Height(m.headerView())
+	height -= lipgloss.Height(m.summaryView())
+	height -= lipgloss.Height(m.helpView())
+
+	m.viewport.Height = max(height, 1)
+}
+
+func (m *Model) redrawTests() {
+	nameWidth := 0
+	for _, t := range m.tests {
+		if w := lipgloss.Width(t.Name); w > nameWidth {
+			nameWidth = w
+		}
+	}
+
+	// names take up to a half of the row, and long positions are truncated
+	availableWidth := m.width - rowStyle.GetHorizontalPadding()
+	nameWidth = max(1, min(nameWidth, availableWidth/2))
+
+	rows := make([]string, 0, len(m.tests))
+
+	for i, t := range m.tests {
+		name := t.Name
+		if lipgloss.Width(name) > nameWidth {
+			name = truncate.StringWithTail(name, uint(nameWidth), ellipsis)
+		}
+
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+
+		color := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+		if i < m.related {
+			color = lipgloss.Color(styles.CurrentTheme.PrimaryColor)
+		}
+
+		row := fmt.Sprintf("%s%-*s %s", cursor, nameWidth, name, position(t))
+		if lipgloss.Width(row) > availableWidth {
+			row = truncate.StringWithTail(row, uint(max(0, availableWidth)), ellipsis)
+		}
+
+		rows = append(rows, rowStyle.Foreground(color).Render(row))
+	}
+
+	m.viewport.SetContent(strings.Join(rows, "\n"))
+}
+
+func position(t *testfind.Test) string {
+	return fmt.Sprintf("%s:%d", t.File, t.Line)
+}
+
+func (m *Model) headerView() string {
+	truncatedTitle := m.title
+
+	if maxWidth := m.width - 5; maxWidth > 0 && len(m.title) > maxWidth {
+		truncatedTitle = fmt.Sprintf("%s%s", ellipsis, m.title[len(m.title)-maxWidth:])
+	}
+
+	title := titleStyle.Render(truncatedTitle)
+	lineWidth := max(0, m.width-lipgloss.Width(title))
+
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, strings.Repeat("─", lineWidth))
+}
+
+// summaryView tells how many tests call the focused function.
+func (m *Model) summaryView() string {
+	var summary string
+
+	switch {
+	case len(m.tests) == 0:
+		summary = "No tests in this package yet."
+	case m.focus == "":
+		summary = fmt.Sprintf("%d tests", len(m.tests))
+	case m.related == 0:
+		summary = fmt.Sprintf("%d tests, none of them calls %s", len(m.tests), m.focus)
+	default:
+		summary = fmt.Sprintf("%d tests, %d of them call %s", len(m.tests), m.related, m.focus)
+	}
+
+	inactive := lipgloss.Color(styles.CurrentTheme.InactiveColor)
+
+	return summaryStyle.Foreground(inactive).Render(truncate.StringWithTail(summary, uint(max(0, m.width-8)), ellipsis))
+}
+
+func (m *Model) helpView() string {
+	if m.showHelp {
+		return helpStyle.Render(m.help.View(m))
+	}
+
+	return ""
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,16 @@
+// Package parser defines the interface of the parsers of coverage reports, so
+// that gocovsh can be built with support for formats it doesn't know. See
+// package cli for running gocovsh with a custom parser.
+package parser
+
+import (
+	"io"
+
+	"golang.org/x/tools/cover"
+)
+
+// Parser reads coverage reports. Every file of the report becomes a profile
+// with its blocks sorted by position.
+type Parser interface {
+	Parse(r io.Reader) ([]*cover.Profile, error)
+}